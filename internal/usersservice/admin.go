@@ -0,0 +1,153 @@
+package usersservice
+
+import (
+	"context"
+	"encoding/base64"
+	"strings"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/twitchtv/twirp"
+)
+
+// ListUsers page sizes. Larger requested pages are cut down to
+// MaxListUsersPageSize.
+const (
+	DefaultListUsersPageSize = 50
+	MaxListUsersPageSize     = 200
+)
+
+func (us *userService) ListUsers(c context.Context, req *pb.ListUsersReq) (*pb.ListUsersResp, error) {
+	if _, err := us.requireAdmin(c, req.Session); err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize < 0:
+		return nil, twirp.InvalidArgumentError("page_size", "must not be negative")
+	case pageSize == 0:
+		pageSize = DefaultListUsersPageSize
+	case pageSize > MaxListUsersPageSize:
+		pageSize = MaxListUsersPageSize
+	}
+
+	after := ""
+	if req.PageToken != "" {
+		prefix, username, ok := decodePageToken(req.PageToken)
+		if !ok || prefix != req.UsernamePrefix {
+			return nil, twirp.InvalidArgumentError("page_token", "is not from a ListUsers call with this username_prefix")
+		}
+		after = username
+	}
+
+	// Ask for one extra user to learn if there is another page
+	users, err := us.Store.ListUsers(req.UsernamePrefix, after, pageSize+1)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.ListUsersResp{Users: []*pb.User{}}
+	if len(users) > pageSize {
+		users = users[:pageSize]
+		resp.NextPageToken = encodePageToken(req.UsernamePrefix, users[pageSize-1].Username)
+	}
+	for _, user := range users {
+		resp.Users = append(resp.Users, publicUser(user, true))
+	}
+	return resp, nil
+}
+
+func (us *userService) DisableUser(c context.Context, req *pb.DisableUserReq) (*pb.DisableUserResp, error) {
+	admin, err := us.requireAdmin(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if req.Username == "" {
+		return nil, twirp.RequiredArgumentError("DisableUserReq.username")
+	}
+	target, err := us.getUser(req.Username)
+	if err != nil {
+		return nil, err
+	}
+	username := target.Username
+	if username == admin.Username {
+		return nil, twirp.NewError(twirp.FailedPrecondition, "admins can not disable themselves")
+	}
+
+	err = us.Store.UpdateUser(username, func(user *pb.PrivateUser) error {
+		if !user.Disabled {
+			user.Disabled = true
+			user.DisabledAt = us.Now().Unix()
+		}
+		return nil
+	})
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, username+" not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	// After the update, Login checks the flag again after storing a session
+	revoked, err := us.Store.DeleteUserSessions(username)
+	if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditDisableUser, admin.Username, username, AuditSuccess), "%s disabled %s, revoking %d sessions", admin.Username, username, revoked)
+
+	return &pb.DisableUserResp{Revoked: int32(revoked)}, nil
+}
+
+func (us *userService) DeleteUser(c context.Context, req *pb.DeleteUserReq) (*pb.DeleteUserResp, error) {
+	admin, err := us.requireAdmin(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if req.Username == "" {
+		return nil, twirp.RequiredArgumentError("DeleteUserReq.username")
+	}
+	target, err := us.getUser(req.Username)
+	if err != nil {
+		return nil, err
+	}
+	username := target.Username
+	if username == admin.Username {
+		return nil, twirp.NewError(twirp.FailedPrecondition, "admins can not delete themselves")
+	}
+
+	if err := us.Store.DeleteUser(username); err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, username+" not found")
+	} else if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditDeleteUser, admin.Username, username, AuditSuccess), "%s deleted %s", admin.Username, username)
+
+	return &pb.DeleteUserResp{}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// errAccountDisabled is returned to a disabled user instead of a session or
+// their details
+func errAccountDisabled() error {
+	return twirp.NewError(twirp.FailedPrecondition, "account disabled")
+}
+
+// encodePageToken makes the continuation token for a page of a listing ending
+// at username. The prefix is kept so a token can not be replayed against a
+// different listing.
+func encodePageToken(prefix, username string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(prefix + "\x00" + username))
+}
+
+func decodePageToken(token string) (prefix, username string, ok bool) {
+	data, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return "", "", false
+	}
+	parts := strings.SplitN(string(data), "\x00", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return "", "", false
+	}
+	return parts[0], parts[1], true
+}
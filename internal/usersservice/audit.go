@@ -0,0 +1,306 @@
+package usersservice
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/twitchtv/twirp"
+)
+
+// Audit event actions
+const (
+	AuditRegister       = "register"
+	AuditLogin          = "login"
+	AuditLogout         = "logout"
+	AuditRevokeSession  = "session.revoke"
+	AuditForgedSession  = "session.forged"
+	AuditChangePassword = "password.change"
+	AuditResetPassword  = "password.reset"
+	AuditGrantRole      = "role.grant"
+	AuditRevokeRole     = "role.revoke"
+	AuditDisableUser    = "user.disable"
+	AuditDeleteUser     = "user.delete"
+	AuditRenameUser     = "user.rename"
+	AuditCreateGroup    = "group.create"
+	AuditAddMember      = "group.add_member"
+	AuditRemoveMember   = "group.remove_member"
+	AuditEnrollTOTP     = "totp.enroll"
+	AuditEnableTOTP     = "totp.enable"
+	AuditDisableTOTP    = "totp.disable"
+	AuditAddWebAuthn    = "webauthn.register"
+	AuditAdminAccess    = "admin.access"
+)
+
+// AuditAnySubject is the subject of the stored login failures that name no
+// user: attempts for unknown usernames and throttled attempts. They are
+// counted and stored at most once a minute for each reason, so guessed
+// usernames can not fill the store. AuditLog still gets every attempt.
+const AuditAnySubject = "*"
+
+// Audit event outcomes
+const (
+	AuditSuccess = "success"
+	AuditFailure = "failure"
+)
+
+// ListAuditEvents page sizes. Larger requested pages are cut down to
+// MaxListAuditEventsPageSize.
+const (
+	DefaultListAuditEventsPageSize = 50
+	MaxListAuditEventsPageSize     = 500
+)
+
+// maxAuditEventsScan bounds how many events one ListAuditEvents call reads
+// looking for matches, a sparse filter returns a short page instead
+const maxAuditEventsScan = 10000
+
+func (us *userService) ListAuditEvents(c context.Context, req *pb.ListAuditEventsReq) (*pb.ListAuditEventsResp, error) {
+	if _, err := us.requireAdmin(c, req.Session); err != nil {
+		return nil, err
+	}
+
+	pageSize := int(req.PageSize)
+	switch {
+	case pageSize < 0:
+		return nil, twirp.InvalidArgumentError("page_size", "must not be negative")
+	case pageSize == 0:
+		pageSize = DefaultListAuditEventsPageSize
+	case pageSize > MaxListAuditEventsPageSize:
+		pageSize = MaxListAuditEventsPageSize
+	}
+
+	filter := req.Filter
+	if filter == nil {
+		filter = &pb.AuditFilter{}
+	}
+	after := ""
+	if req.PageToken != "" {
+		key, id, ok := decodePageToken(req.PageToken)
+		if !ok || key != auditFilterKey(filter) {
+			return nil, twirp.InvalidArgumentError("page_token", "is not from a ListAuditEvents call with this filter")
+		}
+		after = id
+	}
+
+	resp := &pb.ListAuditEventsResp{Events: []*pb.AuditEvent{}}
+	scanned := 0
+	err := us.scanAuditEvents(filter, after, func(event *pb.AuditEvent, matched bool) bool {
+		scanned++
+		if matched {
+			// One match past the page means there is another page
+			if len(resp.Events) == pageSize {
+				resp.NextPageToken = encodePageToken(auditFilterKey(filter), resp.Events[pageSize-1].Id)
+				return false
+			}
+			resp.Events = append(resp.Events, event)
+		}
+		if scanned >= maxAuditEventsScan && len(resp.Events) < pageSize {
+			resp.NextPageToken = encodePageToken(auditFilterKey(filter), event.Id)
+			return false
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// ExportAuditEvents writes the events matching filter to w as JSON Lines,
+// oldest first, and returns how many it wrote.
+//
+//	{"id":"1500000000000000000-9f86d081","time":"2017-07-14T02:40:00Z","action":"login","actor":"eric","subject":"eric","client_ip":"127.0.0.1","outcome":"success","detail":"eric logged in"}
+func (us *userService) ExportAuditEvents(w io.Writer, filter *pb.AuditFilter) (int, error) {
+	if filter == nil {
+		filter = &pb.AuditFilter{}
+	}
+	encoder := json.NewEncoder(w)
+	count := 0
+	var writeErr error
+	err := us.scanAuditEvents(filter, "", func(event *pb.AuditEvent, matched bool) bool {
+		if !matched {
+			return true
+		}
+		if writeErr = encoder.Encode(auditLine(event)); writeErr != nil {
+			return false
+		}
+		count++
+		return true
+	})
+	if err == nil {
+		err = writeErr
+	}
+	return count, err
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// recordEvent writes a security relevant event to the audit log and appends
+// it to the stored audit events. The client ip comes from c. A failure to
+// store the event is logged, it does not fail the rpc.
+func (us *userService) recordEvent(c context.Context, event *pb.AuditEvent, format string, v ...interface{}) {
+	us.audit(format, v...)
+	us.storeEvent(c, event, fmt.Sprintf(format, v...))
+}
+
+// recordLoginFailure writes a failed login for username to the audit log and
+// counts it toward the next stored event for reason, see AuditAnySubject
+func (us *userService) recordLoginFailure(c context.Context, username, reason string) {
+	us.audit("%q login failed: %s", username, reason)
+
+	now := us.Now()
+	us.auditMu.Lock()
+	if us.loginFailures == nil {
+		us.loginFailures = map[string]*countedFailures{}
+	}
+	counted, ok := us.loginFailures[reason]
+	if !ok {
+		counted = &countedFailures{}
+		us.loginFailures[reason] = counted
+	}
+	counted.count++
+	if ok && now.Sub(counted.stored) < time.Minute {
+		us.auditMu.Unlock()
+		return
+	}
+	count := counted.count
+	counted.count, counted.stored = 0, now
+	us.auditMu.Unlock()
+
+	us.storeEvent(c, loginEvent(AuditAnySubject, AuditFailure), fmt.Sprintf("%d failed login(s): %s", count, reason))
+}
+
+// countedFailures are the login failures for a reason since one was stored
+type countedFailures struct {
+	count  int
+	stored time.Time
+}
+
+// storeEvent appends event to the stored audit events with detail
+func (us *userService) storeEvent(c context.Context, event *pb.AuditEvent, detail string) {
+	now := us.Now()
+	event.Id = us.newAuditEventID(now)
+	event.Time = now.Unix()
+	event.ClientIp = ClientInfoFromContext(c).IP
+	event.Detail = detail
+	if err := us.Store.AppendAuditEvent(event); err != nil {
+		log.Printf("audit event %s: %s", event.Id, err)
+		Metrics.Add("audit_event_errors", 1)
+	}
+}
+
+func auditEvent(action, actor, subject, outcome string) *pb.AuditEvent {
+	return &pb.AuditEvent{Action: action, Actor: actor, Subject: subject, Outcome: outcome}
+}
+
+// loginEvent is a login as username, only a successful one has an actor
+func loginEvent(username, outcome string) *pb.AuditEvent {
+	event := auditEvent(AuditLogin, "", username, outcome)
+	if outcome == AuditSuccess {
+		event.Actor = username
+	}
+	return event
+}
+
+// newAuditEventID makes an id that sorts by time: nanoseconds padded to 19
+// digits and a random suffix so servers sharing a store do not collide.
+// Within this process ids always increase, even if the clock does not.
+func (us *userService) newAuditEventID(now time.Time) string {
+	us.auditMu.Lock()
+	nanos := now.UnixNano()
+	if nanos <= us.lastAuditNanos {
+		nanos = us.lastAuditNanos + 1
+	}
+	us.lastAuditNanos = nanos
+	us.auditMu.Unlock()
+
+	var suffix [4]byte
+	rand.Read(suffix[:])
+	return fmt.Sprintf("%019d-%08x", nanos, binary.BigEndian.Uint32(suffix[:]))
+}
+
+// auditScanBatch is how many events scanAuditEvents reads from the store at
+// a time
+const auditScanBatch = 500
+
+// scanAuditEvents calls fn with every event after the id after, oldest
+// first, telling whether it matches filter, until fn returns false. It
+// starts at filter.Since and stops at filter.Until.
+func (us *userService) scanAuditEvents(filter *pb.AuditFilter, after string, fn func(event *pb.AuditEvent, matched bool) bool) error {
+	// Ids start with the time, so since can skip ahead
+	if filter.Since > 0 {
+		if start := fmt.Sprintf("%019d", time.Unix(filter.Since, 0).UnixNano()); start > after {
+			after = start
+		}
+	}
+	for {
+		events, err := us.Store.ListAuditEvents(after, auditScanBatch)
+		if err != nil {
+			return err
+		}
+		for _, event := range events {
+			if filter.Until > 0 && event.Time >= filter.Until {
+				return nil
+			}
+			if !fn(event, auditEventMatches(filter, event)) {
+				return nil
+			}
+		}
+		if len(events) < auditScanBatch {
+			return nil
+		}
+		after = events[len(events)-1].Id
+	}
+}
+
+func auditEventMatches(filter *pb.AuditFilter, event *pb.AuditEvent) bool {
+	return (filter.Action == "" || filter.Action == event.Action) &&
+		(filter.Actor == "" || filter.Actor == event.Actor) &&
+		(filter.Subject == "" || filter.Subject == event.Subject) &&
+		(filter.ClientIp == "" || filter.ClientIp == event.ClientIp) &&
+		(filter.Outcome == "" || filter.Outcome == event.Outcome) &&
+		event.Time >= filter.Since
+}
+
+// auditFilterKey identifies a filter in page tokens. Quoting keeps out the
+// \x00 separator of encodePageToken.
+func auditFilterKey(filter *pb.AuditFilter) string {
+	return fmt.Sprintf("%q %q %q %q %q %d %d",
+		filter.Action, filter.Actor, filter.Subject, filter.ClientIp, filter.Outcome, filter.Since, filter.Until,
+	)
+}
+
+// auditEventLine is an exported AuditEvent, with snake_case field names and
+// an RFC 3339 time
+type auditEventLine struct {
+	ID       string `json:"id"`
+	Time     string `json:"time"`
+	Action   string `json:"action"`
+	Actor    string `json:"actor"`
+	Subject  string `json:"subject"`
+	ClientIP string `json:"client_ip"`
+	Outcome  string `json:"outcome"`
+	Detail   string `json:"detail"`
+}
+
+func auditLine(event *pb.AuditEvent) *auditEventLine {
+	return &auditEventLine{
+		ID:       event.Id,
+		Time:     time.Unix(event.Time, 0).UTC().Format(time.RFC3339),
+		Action:   event.Action,
+		Actor:    event.Actor,
+		Subject:  event.Subject,
+		ClientIP: event.ClientIp,
+		Outcome:  event.Outcome,
+		Detail:   event.Detail,
+	}
+}
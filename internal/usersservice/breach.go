@@ -0,0 +1,266 @@
+package usersservice
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// BreachChecker reports whether a password appears in a corpus of breached
+// passwords
+type BreachChecker interface {
+	Breached(password string) (bool, error)
+}
+
+// A breach index file is
+//
+//	magic | fanout | hashes
+//
+// hashes are the SHA-1 hashes of the corpus sorted and without their first
+// two bytes, fanout is 65537 big endian uint32s where fanout[i] is the index
+// of the first hash whose first two bytes are i. The first two bytes are
+// implied by the fanout bucket a hash is in.
+const (
+	breachIndexMagic = "PWNIDX1\n"
+	breachBuckets    = 1 << 16
+	breachEntrySize  = sha1.Size - 2
+	breachHeaderSize = len(breachIndexMagic) + (breachBuckets+1)*4
+)
+
+// BreachIndex is a BreachChecker backed by an index file built by
+// BuildBreachIndex. Lookups read a handful of entries from disk so the corpus
+// does not need to fit in memory.
+type BreachIndex struct {
+	file   *os.File
+	fanout []uint32
+}
+
+// OpenBreachIndex opens an index built by BuildBreachIndex
+func OpenBreachIndex(path string) (*BreachIndex, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, breachHeaderSize)
+	if _, err := io.ReadFull(file, header); err != nil || string(header[:len(breachIndexMagic)]) != breachIndexMagic {
+		file.Close()
+		return nil, fmt.Errorf("%s: not a breach index", path)
+	}
+	fanout := make([]uint32, breachBuckets+1)
+	for i := range fanout {
+		fanout[i] = binary.BigEndian.Uint32(header[len(breachIndexMagic)+i*4:])
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, err
+	}
+	if info.Size() != int64(breachHeaderSize)+int64(fanout[breachBuckets])*breachEntrySize {
+		file.Close()
+		return nil, fmt.Errorf("%s: truncated breach index", path)
+	}
+	return &BreachIndex{file: file, fanout: fanout}, nil
+}
+
+// Breached binary searches the bucket of the password's hash
+func (b *BreachIndex) Breached(password string) (bool, error) {
+	sum := sha1.Sum([]byte(password))
+	bucket := int(sum[0])<<8 | int(sum[1])
+	want := sum[2:]
+
+	entry := make([]byte, breachEntrySize)
+	lo, hi := int64(b.fanout[bucket]), int64(b.fanout[bucket+1])
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+		if _, err := b.file.ReadAt(entry, int64(breachHeaderSize)+mid*breachEntrySize); err != nil {
+			return false, err
+		}
+		switch bytes.Compare(entry, want) {
+		case 0:
+			return true, nil
+		case -1:
+			lo = mid + 1
+		default:
+			hi = mid
+		}
+	}
+	return false, nil
+}
+
+// Size is how many hashes are in the index
+func (b *BreachIndex) Size() int {
+	return int(b.fanout[breachBuckets])
+}
+
+func (b *BreachIndex) Close() error {
+	return b.file.Close()
+}
+
+// BuildBreachIndex writes an index of the breach corpus at src to dst and
+// returns how many hashes it holds. src is in one of the Have I Been Pwned
+// "Pwned Passwords" download formats:
+//
+//   - a file of HASH:COUNT lines ordered by hash
+//   - a directory of PREFIX.txt range files of SUFFIX:COUNT lines, where the
+//     5 hex digit PREFIX and 35 hex digit SUFFIX make up a hash
+//
+// Hashes are upper or lower case hex SHA-1. Counts are ignored.
+func BuildBreachIndex(src, dst string) (int, error) {
+	info, err := os.Stat(src)
+	if err != nil {
+		return 0, err
+	}
+
+	w, err := newBreachIndexWriter(dst + ".tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(dst + ".tmp")
+
+	if info.IsDir() {
+		err = addRangeFiles(w, src)
+	} else {
+		err = addCorpusFile(w, src, "")
+	}
+	if err != nil {
+		w.file.Close()
+		return 0, err
+	}
+
+	if err := w.close(); err != nil {
+		return 0, err
+	}
+	return w.count, os.Rename(dst+".tmp", dst)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// breachIndexWriter streams sorted hashes into an index file and writes the
+// fanout when it is closed
+type breachIndexWriter struct {
+	file    *os.File
+	out     *bufio.Writer
+	buckets []uint32 // hashes per bucket
+	last    []byte
+	count   int
+}
+
+func newBreachIndexWriter(path string) (*breachIndexWriter, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &breachIndexWriter{
+		file:    file,
+		out:     bufio.NewWriter(file),
+		buckets: make([]uint32, breachBuckets),
+	}
+	// The fanout is filled in by close
+	if _, err := w.out.Write(make([]byte, breachHeaderSize)); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return w, nil
+}
+
+var errBreachOrder = errors.New("hashes are not in order")
+
+// add appends hash, which must not sort before the previous one
+func (w *breachIndexWriter) add(hash []byte) error {
+	if w.last != nil {
+		switch bytes.Compare(hash, w.last) {
+		case 0:
+			return nil
+		case -1:
+			return errBreachOrder
+		}
+	}
+	w.last = append(w.last[:0], hash...)
+
+	w.buckets[int(hash[0])<<8|int(hash[1])]++
+	w.count++
+	_, err := w.out.Write(hash[2:])
+	return err
+}
+
+func (w *breachIndexWriter) close() error {
+	if err := w.out.Flush(); err != nil {
+		w.file.Close()
+		return err
+	}
+
+	header := make([]byte, breachHeaderSize)
+	copy(header, breachIndexMagic)
+	var start uint32
+	for i := 0; i <= breachBuckets; i++ {
+		binary.BigEndian.PutUint32(header[len(breachIndexMagic)+i*4:], start)
+		if i < breachBuckets {
+			start += w.buckets[i]
+		}
+	}
+	if _, err := w.file.WriteAt(header, 0); err != nil {
+		w.file.Close()
+		return err
+	}
+	return w.file.Close()
+}
+
+// addRangeFiles adds every PREFIX.txt range file in dir, in prefix order
+func addRangeFiles(w *breachIndexWriter, dir string) error {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, file := range files {
+		prefix := strings.TrimSuffix(file.Name(), ".txt")
+		if file.IsDir() || len(prefix) != 5 || strings.Trim(strings.ToUpper(prefix), "0123456789ABCDEF") != "" {
+			continue
+		}
+		if err := addCorpusFile(w, filepath.Join(dir, file.Name()), prefix); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// addCorpusFile adds the hashes of a file of HASH:COUNT lines, or of
+// SUFFIX:COUNT lines if prefix is set
+func addCorpusFile(w *breachIndexWriter, path, prefix string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+		if i := strings.IndexByte(text, ':'); i >= 0 {
+			text = text[:i]
+		}
+		hash, err := hex.DecodeString(prefix + text)
+		if err != nil || len(hash) != sha1.Size {
+			return fmt.Errorf("%s:%d: not a SHA-1 hash", path, line)
+		}
+		if err := w.add(hash); err != nil {
+			return fmt.Errorf("%s:%d: %v", path, line, err)
+		}
+	}
+	return scanner.Err()
+}
@@ -0,0 +1,121 @@
+package usersservice
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// ClientInfo describes the HTTP client that made a request
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+}
+
+type clientInfoKey struct{}
+
+// WithClientInfo wraps a twirp server so rpcs can see the client's ip address
+// and User-Agent, which Login stores on new sessions.
+//
+//	handler := usersservice.WithClientInfo(pb.NewUsersServer(server, nil))
+func WithClientInfo(base http.Handler) http.Handler {
+	return WithProxiedClientInfo(base, nil)
+}
+
+// WithProxiedClientInfo is WithClientInfo behind reverse proxies. A request
+// from a trusted proxy is attributed to the last address in its
+// X-Forwarded-For header that is not a trusted proxy, see ParseTrustedProxies.
+func WithProxiedClientInfo(base http.Handler, trusted []*net.IPNet) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		info := ClientInfo{
+			IP:        clientIP(req, trusted),
+			UserAgent: req.UserAgent(),
+		}
+		ctx := context.WithValue(req.Context(), clientInfoKey{}, info)
+		base.ServeHTTP(resp, req.WithContext(ctx))
+	})
+}
+
+// ClientInfoFromContext returns the ClientInfo stored by WithClientInfo
+func ClientInfoFromContext(ctx context.Context) ClientInfo {
+	info, _ := ctx.Value(clientInfoKey{}).(ClientInfo)
+	return info
+}
+
+// ParseTrustedProxies parses a comma separated list of proxy addresses and
+// networks, ex: 10.0.0.0/8,192.168.1.1
+func ParseTrustedProxies(list string) ([]*net.IPNet, error) {
+	trusted := []*net.IPNet{}
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		if !strings.Contains(entry, "/") {
+			ip := net.ParseIP(entry)
+			if ip == nil {
+				return nil, fmt.Errorf("trusted proxy %q is not an ip address", entry)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			trusted = append(trusted, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("trusted proxy %q: %v", entry, err)
+		}
+		trusted = append(trusted, network)
+	}
+	return trusted, nil
+}
+
+// clientIP walks back from the connection's address through
+// X-Forwarded-For while the addresses are trusted proxies. Entries left of an
+// untrusted address could be made up by the client, so they are never used.
+func clientIP(req *http.Request, trusted []*net.IPNet) string {
+	ip := remoteIP(req)
+	if !isTrustedProxy(ip, trusted) {
+		return ip
+	}
+	forwarded := []string{}
+	for _, header := range req.Header["X-Forwarded-For"] {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		hop := strings.TrimSpace(forwarded[i])
+		if net.ParseIP(hop) == nil {
+			break
+		}
+		ip = hop
+		if !isTrustedProxy(ip, trusted) {
+			break
+		}
+	}
+	return ip
+}
+
+func isTrustedProxy(ip string, trusted []*net.IPNet) bool {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range trusted {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,21 @@
+package usersservice
+
+// CommonPasswords are banned by DefaultPolicy. They are the most common
+// passwords in public breach corpora, lower cased.
+var CommonPasswords = []string{
+	"000000", "00000000", "111111", "11111111", "112233", "121212", "123123",
+	"123321", "1234", "12345", "123456", "1234567", "12345678", "123456789",
+	"1234567890", "123qwe", "1q2w3e", "1q2w3e4r", "1q2w3e4r5t", "1qaz2wsx",
+	"654321", "666666", "696969", "7777777", "87654321", "987654321",
+	"aa123456", "abc123", "abcd1234", "access", "admin", "admin123",
+	"administrator", "alexander", "asdf1234", "asdfgh", "asdfghjkl",
+	"ashley", "azerty", "bailey", "baseball", "batman", "charlie", "changeme",
+	"chocolate", "computer", "daniel", "dragon", "football", "freedom",
+	"hello123", "hunter2", "iloveyou", "jennifer", "jessica", "jordan23",
+	"letmein", "login", "lovely", "master", "michael", "michelle",
+	"monkey", "mustang", "p@ssw0rd", "passw0rd", "password", "password1",
+	"password12", "password123", "princess", "qazwsx", "qwerty", "qwerty123",
+	"qwertyuiop", "shadow", "starwars", "summer", "sunshine", "superman",
+	"trustno1", "welcome", "welcome1", "whatever", "zaq12wsx", "zxcvbn",
+	"zxcvbnm",
+}
@@ -0,0 +1,154 @@
+package usersservice
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+	"time"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/twitchtv/twirp"
+)
+
+// DefaultVerificationTokenTTL is how long an email verification token can be
+// used
+const DefaultVerificationTokenTTL = 48 * time.Hour
+
+// ReapVerificationTokens deletes every expired email verification token and
+// returns how many were deleted
+func (us *userService) ReapVerificationTokens() (int, error) {
+	return us.Store.DeleteExpiredVerificationTokens(us.Now().Unix())
+}
+
+func (us *userService) VerifyEmail(c context.Context, req *pb.VerifyEmailReq) (*pb.VerifyEmailResp, error) {
+	if req.Token == "" {
+		return nil, twirp.RequiredArgumentError("VerifyEmailReq.token")
+	}
+
+	token, err := us.Store.TakeVerificationToken(hashSecretToken(req.Token))
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid verification token")
+	} else if err != nil {
+		return nil, err
+	}
+	if us.Now().Unix() >= token.ExpiresAt {
+		return nil, twirp.NewError(twirp.PermissionDenied, "verification token expired")
+	}
+
+	var verified *pb.PrivateUser
+	err = us.Store.UpdateUser(token.Username, func(user *pb.PrivateUser) error {
+		// The token was for an address the user no longer has
+		if user.Email != token.Email {
+			return twirp.NewError(twirp.PermissionDenied, "invalid verification token")
+		}
+		user.EmailVerified = true
+		verified = user
+		return nil
+	})
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid verification token")
+	} else if err == ErrEmailInUse {
+		// Someone else verified the address first
+		return nil, twirp.NewError(twirp.AlreadyExists, "Email: "+token.Email+" already in use")
+	} else if err != nil {
+		return nil, err
+	}
+	us.audit("%s verified %s", verified.Username, verified.Email)
+
+	return &pb.VerifyEmailResp{
+		User: publicUser(verified, true),
+	}, nil
+}
+
+func (us *userService) ResendVerification(c context.Context, req *pb.ResendVerificationReq) (*pb.ResendVerificationResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	user, err := us.getUser(session.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Email == "" {
+		return nil, twirp.NewError(twirp.FailedPrecondition, "no email to verify")
+	}
+	if user.EmailVerified {
+		return nil, twirp.NewError(twirp.FailedPrecondition, "email already verified")
+	}
+
+	if err := us.sendVerification(user); err != nil {
+		return nil, err
+	}
+	return &pb.ResendVerificationResp{}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// sendVerification stores a new verification token for the user's email and
+// notifies the user
+func (us *userService) sendVerification(user *pb.PrivateUser) error {
+	token, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	now := us.Now()
+	expiresAt := now.Add(us.VerificationTokenTTL)
+	err = us.Store.PutVerificationToken(&pb.PrivateVerificationToken{
+		TokenHash: hashSecretToken(token),
+		Username:  user.Username,
+		Email:     user.Email,
+		CreatedAt: now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+
+	return us.Notifier.Notify(&Notification{
+		Kind:      NotifyEmailVerification,
+		Username:  user.Username,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// validateEmail normalizes an email address, returning an InvalidArgument
+// error for field if it is not a bare address
+func validateEmail(field, email string) (string, error) {
+	email = normalizeEmail(email)
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return "", twirp.InvalidArgumentError(field, "must be an email address")
+	}
+	return email, nil
+}
+
+// normalizeEmail is the form emails are stored and looked up in. The whole
+// address is lower cased, in practice local parts are not case sensitive.
+func normalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// publicUser is the User message for user. The email, roles and whether the
+// account is disabled are only shown to the user themselves and admins.
+func publicUser(user *pb.PrivateUser, self bool) *pb.User {
+	public := &pb.User{
+		Username:      user.Username,
+		EmailVerified: user.EmailVerified,
+		Profile:       user.Profile,
+	}
+	if self {
+		public.Disabled = user.Disabled
+		public.Email = user.Email
+		public.Roles = user.Roles
+		public.TotpEnabled = user.TotpEnabled
+		for _, credential := range user.WebauthnCredentials {
+			public.WebauthnCredentials = append(public.WebauthnCredentials, publicWebAuthnCredential(credential))
+		}
+	}
+	return public
+}
@@ -0,0 +1,284 @@
+package usersservice
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/twitchtv/twirp"
+)
+
+var groupNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+func (us *userService) CreateGroup(c context.Context, req *pb.CreateGroupReq) (*pb.CreateGroupResp, error) {
+	admin, err := us.requireAdmin(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if req.Name == "" {
+		return nil, twirp.RequiredArgumentError("CreateGroupReq.name")
+	}
+	if !groupNamePattern.MatchString(req.Name) {
+		return nil, twirp.InvalidArgumentError("name", "must be at most 64 of [a-z0-9_-]")
+	}
+
+	group := &pb.PrivateGroup{
+		Name:        req.Name,
+		Description: req.Description,
+		CreatedAt:   us.Now().Unix(),
+	}
+	if err := us.Store.CreateGroup(group); err == ErrAlreadyExists {
+		return nil, twirp.NewError(twirp.AlreadyExists, "Group: "+group.Name+" already exists")
+	} else if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditCreateGroup, admin.Username, group.Name, AuditSuccess), "%s created group %s", admin.Username, group.Name)
+
+	return &pb.CreateGroupResp{
+		Group: publicGroup(group),
+	}, nil
+}
+
+func (us *userService) AddMember(c context.Context, req *pb.AddMemberReq) (*pb.AddMemberResp, error) {
+	admin, err := us.requireAdmin(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMembership("AddMemberReq", req.Group, req.Member); err != nil {
+		return nil, err
+	}
+	if _, err := us.getGroup(req.Group); err != nil {
+		return nil, err
+	}
+
+	member := req.Member
+	if member.Group != "" {
+		if _, err := us.getGroup(req.Member.Group); err != nil {
+			return nil, err
+		}
+		// group must not already be inside the group being added
+		nested, err := us.subgroups(req.Member.Group)
+		if err != nil {
+			return nil, err
+		}
+		if req.Member.Group == req.Group || nested[req.Group] {
+			return nil, twirp.NewError(twirp.FailedPrecondition, fmt.Sprintf(
+				"adding group %s to %s would create a cycle", req.Member.Group, req.Group,
+			))
+		}
+	} else {
+		user, err := us.getUser(member.Username)
+		if err != nil {
+			return nil, err
+		}
+		// The stored username, not the one given
+		member = &pb.Member{Username: user.Username}
+	}
+
+	if err := us.Store.AddGroupMember(req.Group, member); err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, "group "+req.Group+" not found")
+	} else if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditAddMember, admin.Username, req.Group, AuditSuccess), "%s added %s to group %s", admin.Username, memberKey(member), req.Group)
+
+	return &pb.AddMemberResp{}, nil
+}
+
+func (us *userService) RemoveMember(c context.Context, req *pb.RemoveMemberReq) (*pb.RemoveMemberResp, error) {
+	admin, err := us.requireAdmin(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMembership("RemoveMemberReq", req.Group, req.Member); err != nil {
+		return nil, err
+	}
+
+	member := req.Member
+	if member.Group == "" {
+		user, err := us.getUser(member.Username)
+		if err != nil {
+			return nil, err
+		}
+		// The stored username, not the one given
+		member = &pb.Member{Username: user.Username}
+	}
+
+	if err := us.Store.RemoveGroupMember(req.Group, member); err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, "not a member")
+	} else if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditRemoveMember, admin.Username, req.Group, AuditSuccess), "%s removed %s from group %s", admin.Username, memberKey(member), req.Group)
+
+	return &pb.RemoveMemberResp{}, nil
+}
+
+func (us *userService) ListGroupMembers(c context.Context, req *pb.ListGroupMembersReq) (*pb.ListGroupMembersResp, error) {
+	if _, err := us.validateSession(c, req.Session); err != nil {
+		return nil, err
+	}
+	if req.Group == "" {
+		return nil, twirp.RequiredArgumentError("ListGroupMembersReq.group")
+	}
+
+	if !req.Transitive {
+		members, err := us.Store.GroupMembers(req.Group)
+		if err == ErrNotFound {
+			return nil, twirp.NewError(twirp.NotFound, "group "+req.Group+" not found")
+		} else if err != nil {
+			return nil, err
+		}
+		return &pb.ListGroupMembersResp{Members: members}, nil
+	}
+
+	if _, err := us.getGroup(req.Group); err != nil {
+		return nil, err
+	}
+	groups, err := us.subgroups(req.Group)
+	if err != nil {
+		return nil, err
+	}
+	groups[req.Group] = true
+
+	usernames := map[string]bool{}
+	for group := range groups {
+		members, err := us.Store.GroupMembers(group)
+		if err != nil && err != ErrNotFound {
+			return nil, err
+		}
+		for _, member := range members {
+			if member.Username != "" {
+				usernames[member.Username] = true
+			}
+		}
+	}
+	members := []*pb.Member{}
+	for username := range usernames {
+		members = append(members, &pb.Member{Username: username})
+	}
+	sortMembers(members)
+
+	return &pb.ListGroupMembersResp{Members: members}, nil
+}
+
+func (us *userService) ListUserGroups(c context.Context, req *pb.ListUserGroupsReq) (*pb.ListUserGroupsResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	username := req.Username
+	if username == "" {
+		username = session.Username
+	}
+	if username != session.Username {
+		if _, err := us.requireAdmin(c, req.Session); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := us.getUser(username); err != nil {
+		return nil, err
+	}
+
+	groups, err := us.userGroups(username)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for group := range groups {
+		names = append(names, group)
+	}
+	sort.Strings(names)
+
+	return &pb.ListUserGroupsResp{Groups: names}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// userGroups returns every group username is in, directly or through nested
+// groups
+func (us *userService) userGroups(username string) (map[string]bool, error) {
+	return us.walkGroups(&pb.Member{Username: username}, func(member *pb.Member) ([]*pb.Member, error) {
+		groups, err := us.Store.MemberGroups(member)
+		if err != nil {
+			return nil, err
+		}
+		parents := make([]*pb.Member, len(groups))
+		for i, group := range groups {
+			parents[i] = &pb.Member{Group: group}
+		}
+		return parents, nil
+	})
+}
+
+// subgroups returns every group nested in group, not including group itself
+func (us *userService) subgroups(group string) (map[string]bool, error) {
+	return us.walkGroups(&pb.Member{Group: group}, func(member *pb.Member) ([]*pb.Member, error) {
+		members, err := us.Store.GroupMembers(member.Group)
+		if err == ErrNotFound {
+			return nil, nil
+		}
+		return members, err
+	})
+}
+
+// walkGroups does a breadth first walk of the groups reachable from start
+// through next. Groups are visited once so a cycle, which AddMember refuses
+// to create, can not loop forever.
+func (us *userService) walkGroups(start *pb.Member, next func(member *pb.Member) ([]*pb.Member, error)) (map[string]bool, error) {
+	seen := map[string]bool{}
+	queue := []*pb.Member{start}
+	for len(queue) > 0 {
+		member := queue[0]
+		queue = queue[1:]
+
+		neighbours, err := next(member)
+		if err != nil {
+			return nil, err
+		}
+		for _, neighbour := range neighbours {
+			if neighbour.Group == "" || seen[neighbour.Group] {
+				continue
+			}
+			seen[neighbour.Group] = true
+			queue = append(queue, neighbour)
+		}
+	}
+	delete(seen, start.Group)
+	return seen, nil
+}
+
+// getGroup finds a group, returning a twirp NotFound error if it does not
+// exist
+func (us *userService) getGroup(name string) (*pb.PrivateGroup, error) {
+	group, err := us.Store.GetGroup(name)
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, "group "+name+" not found")
+	} else if err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// validateMembership checks the group and member arguments of req
+func validateMembership(req, group string, member *pb.Member) error {
+	if group == "" {
+		return twirp.RequiredArgumentError(req + ".group")
+	}
+	if member == nil || (member.Username == "") == (member.Group == "") {
+		return twirp.InvalidArgumentError("member", "exactly one of username or group is required")
+	}
+	return nil
+}
+
+func publicGroup(group *pb.PrivateGroup) *pb.Group {
+	return &pb.Group{
+		Name:        group.Name,
+		Description: group.Description,
+		Roles:       group.Roles,
+	}
+}
@@ -0,0 +1,10 @@
+package usersservice
+
+import "expvar"
+
+// Metrics are the service's counters, published with expvar under
+// "usersservice":
+//
+//	breached_password_rejections  new passwords rejected by the breach check
+//	notification_errors           password reset notifications that failed to send
+var Metrics = expvar.NewMap("usersservice")
@@ -0,0 +1,52 @@
+package usersservice
+
+import (
+	"log"
+	"os"
+	"time"
+)
+
+// Notification kinds
+const (
+	NotifyPasswordReset     = "password_reset"
+	NotifyEmailVerification = "email_verification"
+)
+
+// Notification is a message for a user that carries a secret, such as a
+// password reset token. It is up to the Notifier to deliver it.
+type Notification struct {
+	Kind      string // ex: NotifyPasswordReset
+	Username  string
+	Email     string    // where to deliver it, empty if the user has no email
+	Token     string    // the secret the user needs to act on the notification
+	ExpiresAt time.Time // the token is useless after this
+}
+
+// Notifier delivers notifications to users, ex: by email
+type Notifier interface {
+	Notify(n *Notification) error
+}
+
+// LogNotifier writes notifications to a log instead of delivering them. It
+// is meant for local development: the tokens it logs are live secrets.
+type LogNotifier struct {
+	Logger *log.Logger
+}
+
+// NewFileNotifier appends notifications to the file at path
+func NewFileNotifier(path string) (*LogNotifier, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &LogNotifier{Logger: log.New(file, "", log.LstdFlags|log.LUTC)}, nil
+}
+
+func (n *LogNotifier) Notify(notification *Notification) error {
+	n.Logger.Printf(
+		"%s for %s <%s>: token=%s expires=%s",
+		notification.Kind, notification.Username, notification.Email, notification.Token,
+		notification.ExpiresAt.UTC().Format(time.RFC3339),
+	)
+	return nil
+}
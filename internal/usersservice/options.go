@@ -0,0 +1,195 @@
+package usersservice
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"time"
+)
+
+// DefaultDBPath is where New opens a LevelDB store when no store is given
+const DefaultDBPath = "./.usersservice.db"
+
+// Option configures the service created by New
+type Option func(us *userService) error
+
+// New creates a userService. Without a store option it opens a LevelDB
+// store at DefaultDBPath.
+//
+//	server, err := usersservice.New(usersservice.WithLevelDB("./users.db"))
+func New(opts ...Option) (*userService, error) {
+	us := &userService{
+		Hasher:                   DefaultPasswordHasher,
+		Now:                      time.Now,
+		SessionIdleTimeout:       DefaultSessionIdleTimeout,
+		SessionLifetime:          DefaultSessionLifetime,
+		AuditLog:                 log.New(os.Stderr, "audit: ", log.LstdFlags|log.LUTC),
+		ResetTokenTTL:            DefaultResetTokenTTL,
+		VerificationTokenTTL:     DefaultVerificationTokenTTL,
+		Notifier:                 &LogNotifier{Logger: log.New(os.Stderr, "notify: ", log.LstdFlags|log.LUTC)},
+		Roles:                    DefaultRoles,
+		RenameReservation:        DefaultRenameReservation,
+		Policy:                   DefaultPolicy,
+		TOTPIssuer:               "twirp-users",
+		LoginChallengeTTL:        DefaultLoginChallengeTTL,
+		LoginFreeAttempts:        DefaultLoginFreeAttempts,
+		LoginBackoff:             DefaultLoginBackoff,
+		MaxLoginBackoff:          DefaultMaxLoginBackoff,
+		LoginFailureWindow:       DefaultLoginFailureWindow,
+		MaxLoginFailureUsernames: DefaultMaxLoginFailureUsernames,
+		IPLoginRate:              DefaultIPLoginRate,
+		IPLoginBurst:             DefaultIPLoginBurst,
+	}
+
+	for _, opt := range opts {
+		if err := opt(us); err != nil {
+			us.closeOpened()
+			return nil, err
+		}
+	}
+
+	if us.Store == nil {
+		if err := WithLevelDB(DefaultDBPath)(us); err != nil {
+			us.closeOpened()
+			return nil, err
+		}
+	}
+	us.opened = nil
+	return us, nil
+}
+
+// WithStore uses store to persist users and sessions
+func WithStore(store Store) Option {
+	return func(us *userService) error {
+		us.Store = store
+		us.opened = append(us.opened, store)
+		return nil
+	}
+}
+
+// WithLevelDB persists users and sessions in a LevelDB directory at path
+func WithLevelDB(path string) Option {
+	return func(us *userService) error {
+		store, err := NewLevelDBStore(path)
+		if err != nil {
+			return err
+		}
+		us.Store = store
+		us.opened = append(us.opened, store)
+		return nil
+	}
+}
+
+// WithSQLite persists users and sessions in a SQLite database at path,
+// migrating its schema if needed
+func WithSQLite(path string) Option {
+	return func(us *userService) error {
+		store, err := NewSQLiteStore(path)
+		if err != nil {
+			return err
+		}
+		us.Store = store
+		us.opened = append(us.opened, store)
+		return nil
+	}
+}
+
+// WithMemoryStore keeps users and sessions in memory, see MemoryStore
+func WithMemoryStore() Option {
+	return WithStore(NewMemoryStore())
+}
+
+// WithNotifier delivers notifications such as password reset tokens with
+// notifier
+func WithNotifier(notifier Notifier) Option {
+	return func(us *userService) error {
+		us.Notifier = notifier
+		return nil
+	}
+}
+
+// WithRoles defines the roles that can be granted, see LoadRoles
+func WithRoles(roles map[string][]string) Option {
+	return func(us *userService) error {
+		us.Roles = roles
+		return nil
+	}
+}
+
+// WithPolicy enforces policy on new usernames and passwords instead of
+// DefaultPolicy
+func WithPolicy(policy *Policy) Option {
+	return func(us *userService) error {
+		us.Policy = policy
+		return nil
+	}
+}
+
+// WithBreachChecker rejects new passwords that checker reports as breached
+func WithBreachChecker(checker BreachChecker) Option {
+	return func(us *userService) error {
+		us.Breaches = checker
+		return nil
+	}
+}
+
+// WithBreachIndex rejects new passwords found in the index at path, see
+// BuildBreachIndex
+func WithBreachIndex(path string) Option {
+	return func(us *userService) error {
+		index, err := OpenBreachIndex(path)
+		if err != nil {
+			return err
+		}
+		us.Breaches = index
+		us.opened = append(us.opened, index)
+		return nil
+	}
+}
+
+// WithSecretKey seals TOTP secrets with key, which must be SecretKeySize
+// bytes. Without it users can not enroll in two-factor authentication.
+func WithSecretKey(key []byte) Option {
+	return func(us *userService) error {
+		if len(key) != SecretKeySize {
+			return fmt.Errorf("secret key must be %d bytes", SecretKeySize)
+		}
+		us.SecretKey = key
+		return nil
+	}
+}
+
+// WithWebAuthn enables passkey and security key registration and login
+func WithWebAuthn(webAuthn *WebAuthn) Option {
+	return func(us *userService) error {
+		if webAuthn.RPID == "" || len(webAuthn.Origins) == 0 {
+			return fmt.Errorf("WebAuthn needs an RP ID and at least one origin")
+		}
+		if webAuthn.RPName == "" {
+			webAuthn.RPName = webAuthn.RPID
+		}
+		us.WebAuthn = webAuthn
+		return nil
+	}
+}
+
+// WithPasswordHasher hashes new passwords with hasher
+func WithPasswordHasher(hasher PasswordHasher) Option {
+	return func(us *userService) error {
+		us.Hasher = hasher
+		return nil
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// closeOpened closes everything the options opened when New fails, a store
+// replaced by a later option included
+func (us *userService) closeOpened() {
+	for _, closer := range us.opened {
+		closer.Close()
+	}
+	us.opened = nil
+}
@@ -0,0 +1,212 @@
+package usersservice
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// PasswordHasher hashes and verifies passwords. Hashes are encoded as
+// self-describing strings (PHC string format) so the salt and cost
+// parameters are stored alongside the hash.
+type PasswordHasher interface {
+	// Hash hashes password with a fresh random salt
+	Hash(password string) (string, error)
+
+	// Verify checks password against an encoded hash produced by Hash
+	Verify(password, encoded string) (bool, error)
+}
+
+// DefaultPasswordHasher is the hasher used by New
+var DefaultPasswordHasher PasswordHasher = &Argon2idHasher{
+	Time:    1,
+	Memory:  64 * 1024,
+	Threads: 2,
+	KeyLen:  32,
+	SaltLen: 16,
+}
+
+// ErrUnknownHashFormat is returned when an encoded hash can not be parsed
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// VerifyPassword checks password against an encoded hash from any of the
+// supported hashers, so records hashed with a previous default still verify.
+func VerifyPassword(password, encoded string) (bool, error) {
+	switch {
+	case strings.HasPrefix(encoded, "$argon2id$"):
+		return (&Argon2idHasher{}).Verify(password, encoded)
+	case strings.HasPrefix(encoded, "$scrypt$"):
+		return (&ScryptHasher{}).Verify(password, encoded)
+	case strings.HasPrefix(encoded, "$2a$"), strings.HasPrefix(encoded, "$2b$"), strings.HasPrefix(encoded, "$2y$"):
+		return (&BcryptHasher{}).Verify(password, encoded)
+	}
+	return false, ErrUnknownHashFormat
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// argon2id
+///////////////////////////////////////////////////////////////////////////////
+
+// Argon2idHasher hashes passwords with argon2id.
+// Encoded as: $argon2id$v=19$m=<memory>,t=<time>,p=<threads>$<salt>$<hash>
+type Argon2idHasher struct {
+	Time    uint32 // number of passes
+	Memory  uint32 // memory in KiB
+	Threads uint8
+	KeyLen  uint32
+	SaltLen int
+}
+
+func (h *Argon2idHasher) Hash(password string) (string, error) {
+	salt, err := randomSalt(h.SaltLen)
+	if err != nil {
+		return "", err
+	}
+	key := argon2.IDKey([]byte(password), salt, h.Time, h.Memory, h.Threads, h.KeyLen)
+	return fmt.Sprintf(
+		"$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Time, h.Threads, encodeB64(salt), encodeB64(key),
+	), nil
+}
+
+func (h *Argon2idHasher) Verify(password, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, ErrUnknownHashFormat
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, ErrUnknownHashFormat
+	}
+
+	var memory, time uint32
+	var threads uint8
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &memory, &time, &threads); err != nil {
+		return false, ErrUnknownHashFormat
+	}
+
+	salt, key, err := decodeSaltAndKey(parts[4], parts[5])
+	if err != nil {
+		return false, err
+	}
+
+	other := argon2.IDKey([]byte(password), salt, time, memory, threads, uint32(len(key)))
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// bcrypt
+///////////////////////////////////////////////////////////////////////////////
+
+// BcryptHasher hashes passwords with bcrypt. bcrypt only uses the first 72
+// bytes of a password.
+// Encoded in bcrypt's own modular crypt format: $2a$<cost>$<salt+hash>
+type BcryptHasher struct {
+	Cost int
+}
+
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+func (h *BcryptHasher) Verify(password, encoded string) (bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err == bcrypt.ErrMismatchedHashAndPassword {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// scrypt
+///////////////////////////////////////////////////////////////////////////////
+
+// ScryptHasher hashes passwords with scrypt.
+// Encoded as: $scrypt$ln=<log2(N)>,r=<r>,p=<p>$<salt>$<hash>
+type ScryptHasher struct {
+	LogN    uint8 // N = 2^LogN
+	R       int
+	P       int
+	KeyLen  int
+	SaltLen int
+}
+
+func (h *ScryptHasher) Hash(password string) (string, error) {
+	salt, err := randomSalt(h.SaltLen)
+	if err != nil {
+		return "", err
+	}
+	key, err := scrypt.Key([]byte(password), salt, 1<<h.LogN, h.R, h.P, h.KeyLen)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf(
+		"$scrypt$ln=%d,r=%d,p=%d$%s$%s",
+		h.LogN, h.R, h.P, encodeB64(salt), encodeB64(key),
+	), nil
+}
+
+func (h *ScryptHasher) Verify(password, encoded string) (bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[1] != "scrypt" {
+		return false, ErrUnknownHashFormat
+	}
+
+	var logN uint8
+	var r, p int
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &logN, &r, &p); err != nil {
+		return false, ErrUnknownHashFormat
+	}
+
+	salt, key, err := decodeSaltAndKey(parts[3], parts[4])
+	if err != nil {
+		return false, err
+	}
+
+	other, err := scrypt.Key([]byte(password), salt, 1<<logN, r, p, len(key))
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(key, other) == 1, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+func randomSalt(n int) ([]byte, error) {
+	salt := make([]byte, n)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+func encodeB64(b []byte) string {
+	return base64.RawStdEncoding.EncodeToString(b)
+}
+
+func decodeSaltAndKey(salt, key string) ([]byte, []byte, error) {
+	saltBytes, err := base64.RawStdEncoding.DecodeString(salt)
+	if err != nil {
+		return nil, nil, ErrUnknownHashFormat
+	}
+	keyBytes, err := base64.RawStdEncoding.DecodeString(key)
+	if err != nil {
+		return nil, nil, ErrUnknownHashFormat
+	}
+	return saltBytes, keyBytes, nil
+}
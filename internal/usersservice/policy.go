@@ -0,0 +1,214 @@
+package usersservice
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/twitchtv/twirp"
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Policy is the rules for new usernames and passwords. A zero field is not
+// checked, the zero Policy only normalizes usernames.
+type Policy struct {
+	UsernameMinLength int             // characters, after normalization
+	UsernameMaxLength int             // characters, after normalization
+	UsernamePattern   *regexp.Regexp  // the normalized username must match it
+	ReservedUsernames map[string]bool // normalized usernames nobody may register
+	PasswordMinLength int             // characters
+	PasswordMaxLength int             // characters
+	BannedPasswords   map[string]bool // lower cased, compared case-insensitively
+}
+
+// DefaultPolicy is the policy a server enforces when none is configured
+var DefaultPolicy = &Policy{
+	UsernameMinLength: 2,
+	UsernameMaxLength: 32,
+	UsernamePattern:   regexp.MustCompile(`^[\p{L}\p{N}][\p{L}\p{N}._-]*$`),
+	ReservedUsernames: stringSet(DefaultReservedUsernames),
+	PasswordMinLength: 8,
+	PasswordMaxLength: 256,
+	BannedPasswords:   stringSet(CommonPasswords),
+}
+
+// DefaultReservedUsernames could be mistaken for the service or its operators
+var DefaultReservedUsernames = []string{
+	"abuse", "admin", "administrator", "api", "help", "hostmaster", "info",
+	"noreply", "no-reply", "postmaster", "root", "security", "support",
+	"system", "webmaster",
+}
+
+// policyFile is the JSON form of a Policy read by LoadPolicy
+type policyFile struct {
+	UsernameMinLength *int     `json:"username_min_length"`
+	UsernameMaxLength *int     `json:"username_max_length"`
+	UsernamePattern   *string  `json:"username_pattern"`
+	ReservedUsernames []string `json:"reserved_usernames"`
+	PasswordMinLength *int     `json:"password_min_length"`
+	PasswordMaxLength *int     `json:"password_max_length"`
+	BannedPasswords   []string `json:"banned_passwords"`
+}
+
+// LoadPolicy reads a policy from a JSON file. Fields that are left out keep
+// their DefaultPolicy value:
+//
+//	{
+//	  "username_min_length": 3,
+//	  "username_pattern": "^[a-z0-9][a-z0-9_]*$",
+//	  "reserved_usernames": ["admin", "root"],
+//	  "password_min_length": 12
+//	}
+//
+// reserved_usernames and banned_passwords replace the default lists.
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	file := &policyFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+
+	policy := *DefaultPolicy
+	if file.UsernameMinLength != nil {
+		policy.UsernameMinLength = *file.UsernameMinLength
+	}
+	if file.UsernameMaxLength != nil {
+		policy.UsernameMaxLength = *file.UsernameMaxLength
+	}
+	if file.UsernamePattern != nil {
+		policy.UsernamePattern = nil
+		if *file.UsernamePattern != "" {
+			if policy.UsernamePattern, err = regexp.Compile(*file.UsernamePattern); err != nil {
+				return nil, fmt.Errorf("%s: username_pattern: %v", path, err)
+			}
+		}
+	}
+	if file.ReservedUsernames != nil {
+		reserved := make([]string, len(file.ReservedUsernames))
+		for i, username := range file.ReservedUsernames {
+			reserved[i] = normalizeUsername(username)
+		}
+		policy.ReservedUsernames = stringSet(reserved)
+	}
+	if file.PasswordMinLength != nil {
+		policy.PasswordMinLength = *file.PasswordMinLength
+	}
+	if file.PasswordMaxLength != nil {
+		policy.PasswordMaxLength = *file.PasswordMaxLength
+	}
+	if file.BannedPasswords != nil {
+		banned := make([]string, len(file.BannedPasswords))
+		for i, password := range file.BannedPasswords {
+			banned[i] = strings.ToLower(password)
+		}
+		policy.BannedPasswords = stringSet(banned)
+	}
+	return &policy, nil
+}
+
+// ValidateUsername normalizes a new username and checks it against the
+// policy. field names the argument in the returned error.
+func (p *Policy) ValidateUsername(field, username string) (string, error) {
+	username = normalizeUsername(username)
+	if username == "" {
+		return "", twirp.RequiredArgumentError(field)
+	}
+
+	violations := newViolations()
+	length := utf8.RuneCountInString(username)
+	if p.UsernameMinLength > 0 && length < p.UsernameMinLength {
+		violations.add("min_length", fmt.Sprintf("must be at least %d characters", p.UsernameMinLength))
+	}
+	if p.UsernameMaxLength > 0 && length > p.UsernameMaxLength {
+		violations.add("max_length", fmt.Sprintf("must be at most %d characters", p.UsernameMaxLength))
+	}
+	if p.UsernamePattern != nil && !p.UsernamePattern.MatchString(username) {
+		violations.add("charset", "has characters that are not allowed")
+	}
+	if p.ReservedUsernames[username] {
+		violations.add("reserved", "is reserved")
+	}
+	return username, violations.err(field)
+}
+
+// ValidatePassword checks a new password against the policy. field names
+// the argument in the returned error.
+func (p *Policy) ValidatePassword(field, password string) error {
+	if password == "" {
+		return twirp.RequiredArgumentError(field)
+	}
+
+	violations := newViolations()
+	length := utf8.RuneCountInString(password)
+	if p.PasswordMinLength > 0 && length < p.PasswordMinLength {
+		violations.add("min_length", fmt.Sprintf("must be at least %d characters", p.PasswordMinLength))
+	}
+	if p.PasswordMaxLength > 0 && length > p.PasswordMaxLength {
+		violations.add("max_length", fmt.Sprintf("must be at most %d characters", p.PasswordMaxLength))
+	}
+	if p.BannedPasswords[strings.ToLower(password)] {
+		violations.add("banned", "is too common")
+	}
+	return violations.err(field)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// violations collects the rules an argument breaks
+type violations struct {
+	rules    []string
+	messages map[string]string
+}
+
+func newViolations() *violations {
+	return &violations{messages: map[string]string{}}
+}
+
+func (v *violations) add(rule, message string) {
+	v.rules = append(v.rules, rule)
+	v.messages[rule] = message
+}
+
+// err is an InvalidArgument error for field listing every violation, or nil.
+// The "rules" meta is a comma separated list of the violated rules and each
+// rule has a meta entry with its message.
+func (v *violations) err(field string) error {
+	if len(v.rules) == 0 {
+		return nil
+	}
+	messages := make([]string, len(v.rules))
+	for i, rule := range v.rules {
+		messages[i] = v.messages[rule]
+	}
+	err := twirp.InvalidArgumentError(field, strings.Join(messages, ", "))
+	err = err.WithMeta("rules", strings.Join(v.rules, ","))
+	for _, rule := range v.rules {
+		err = err.WithMeta("rule."+rule, v.messages[rule])
+	}
+	return err
+}
+
+// normalizeUsername is the form usernames are stored and looked up in: NFKC
+// normalized and case folded, so "Eric" and "ｅｒｉｃ" are both "eric".
+// Folding can undo NFKC so it is applied again.
+func normalizeUsername(username string) string {
+	folded := cases.Fold().String(norm.NFKC.String(strings.TrimSpace(username)))
+	return norm.NFKC.String(folded)
+}
+
+func stringSet(list []string) map[string]bool {
+	set := make(map[string]bool, len(list))
+	for _, s := range list {
+		set[s] = true
+	}
+	return set
+}
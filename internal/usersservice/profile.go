@@ -0,0 +1,145 @@
+package usersservice
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/golang/protobuf/proto"
+	"github.com/twitchtv/twirp"
+)
+
+// Profile limits, enforced by UpdateProfile
+const (
+	MaxDisplayNameLength    = 100 // characters
+	MaxAvatarURLLength      = 2048
+	MaxAttributes           = 32
+	MaxAttributeKeyLength   = 64
+	MaxAttributeValueLength = 1024
+)
+
+var (
+	attributeKeyPattern = regexp.MustCompile(`^[a-z0-9_-]+$`)
+	localePattern       = regexp.MustCompile(`^[A-Za-z]{2,3}(-[A-Za-z0-9]{2,8})*$`)
+)
+
+func (us *userService) UpdateProfile(c context.Context, req *pb.UpdateProfileReq) (*pb.UpdateProfileResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if len(req.UpdateMask) == 0 {
+		return nil, twirp.RequiredArgumentError("UpdateProfileReq.update_mask")
+	}
+	update := req.Profile
+	if update == nil {
+		update = &pb.Profile{}
+	}
+
+	var updated *pb.PrivateUser
+	err = us.Store.UpdateUser(session.Username, func(user *pb.PrivateUser) error {
+		profile := &pb.Profile{}
+		if user.Profile != nil {
+			profile = proto.Clone(user.Profile).(*pb.Profile)
+		}
+		if err := applyProfileMask(profile, update, req.UpdateMask); err != nil {
+			return err
+		}
+		if err := validateProfile(profile); err != nil {
+			return err
+		}
+		user.Profile = profile
+		updated = user
+		return nil
+	})
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, session.Username+" not found")
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &pb.UpdateProfileResp{
+		User: publicUser(updated, true),
+	}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// applyProfileMask copies the fields of update named by mask into profile
+func applyProfileMask(profile, update *pb.Profile, mask []string) error {
+	for _, path := range mask {
+		switch path {
+		case "display_name":
+			profile.DisplayName = update.DisplayName
+		case "avatar_url":
+			profile.AvatarUrl = update.AvatarUrl
+		case "locale":
+			profile.Locale = update.Locale
+		case "timezone":
+			profile.Timezone = update.Timezone
+		case "attributes":
+			profile.Attributes = map[string]string{}
+			for key, value := range update.Attributes {
+				profile.Attributes[key] = value
+			}
+		default:
+			key := strings.TrimPrefix(path, "attributes.")
+			if key == path || key == "" {
+				return twirp.InvalidArgumentError("update_mask", fmt.Sprintf("unknown path %q", path))
+			}
+			if profile.Attributes == nil {
+				profile.Attributes = map[string]string{}
+			}
+			if value, ok := update.Attributes[key]; ok {
+				profile.Attributes[key] = value
+			} else {
+				delete(profile.Attributes, key)
+			}
+		}
+	}
+	return nil
+}
+
+// validateProfile checks the result of an update against the profile limits
+func validateProfile(profile *pb.Profile) error {
+	if !utf8.ValidString(profile.DisplayName) || utf8.RuneCountInString(profile.DisplayName) > MaxDisplayNameLength {
+		return twirp.InvalidArgumentError("profile.display_name", fmt.Sprintf("must be at most %d characters", MaxDisplayNameLength))
+	}
+
+	if profile.AvatarUrl != "" {
+		u, err := url.Parse(profile.AvatarUrl)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || len(profile.AvatarUrl) > MaxAvatarURLLength {
+			return twirp.InvalidArgumentError("profile.avatar_url", "must be an http or https url")
+		}
+	}
+
+	if profile.Locale != "" && !localePattern.MatchString(profile.Locale) {
+		return twirp.InvalidArgumentError("profile.locale", "must be a BCP 47 language tag")
+	}
+
+	if profile.Timezone != "" {
+		if _, err := time.LoadLocation(profile.Timezone); err != nil || profile.Timezone == "Local" {
+			return twirp.InvalidArgumentError("profile.timezone", "must be an IANA time zone")
+		}
+	}
+
+	if len(profile.Attributes) > MaxAttributes {
+		return twirp.InvalidArgumentError("profile.attributes", fmt.Sprintf("must have at most %d attributes", MaxAttributes))
+	}
+	for key, value := range profile.Attributes {
+		if len(key) > MaxAttributeKeyLength || !attributeKeyPattern.MatchString(key) {
+			return twirp.InvalidArgumentError("profile.attributes", fmt.Sprintf("key %q must be at most %d of [a-z0-9_-]", key, MaxAttributeKeyLength))
+		}
+		if len(value) > MaxAttributeValueLength || !utf8.ValidString(value) {
+			return twirp.InvalidArgumentError("profile.attributes", fmt.Sprintf("value of %q must be at most %d bytes of utf-8", key, MaxAttributeValueLength))
+		}
+	}
+	return nil
+}
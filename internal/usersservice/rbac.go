@@ -0,0 +1,278 @@
+package usersservice
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/twitchtv/twirp"
+)
+
+// PermissionAdmin allows calling the admin rpcs, ex: GrantRole
+const PermissionAdmin = "users.admin"
+
+// RoleAdmin is the role GrantAdmins grants
+const RoleAdmin = "admin"
+
+// DefaultRoles are the roles a server defines when none are configured
+var DefaultRoles = map[string][]string{
+	RoleAdmin: {"*"},
+}
+
+// LoadRoles reads role definitions from a JSON file mapping each role to its
+// permissions:
+//
+//	{"admin": ["*"], "editor": ["documents.*"], "viewer": ["documents.read"]}
+//
+// A permission of * grants every permission, one ending in .* grants every
+// permission with that prefix.
+func LoadRoles(path string) (map[string][]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	roles := map[string][]string{}
+	if err := json.Unmarshal(data, &roles); err != nil {
+		return nil, fmt.Errorf("%s: %v", path, err)
+	}
+	return roles, nil
+}
+
+func (us *userService) GrantRole(c context.Context, req *pb.GrantRoleReq) (*pb.GrantRoleResp, error) {
+	admin, err := us.requireAdmin(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if req.Username == "" && req.Group == "" {
+		return nil, twirp.RequiredArgumentError("GrantRoleReq.username")
+	}
+	if req.Role == "" {
+		return nil, twirp.RequiredArgumentError("GrantRoleReq.role")
+	}
+	if _, ok := us.Roles[req.Role]; !ok {
+		return nil, twirp.InvalidArgumentError("role", fmt.Sprintf("unknown role %q", req.Role))
+	}
+
+	grant := &pb.RoleGrant{Role: req.Role, Resource: req.Resource}
+	grantee, err := us.updateRoles(req.Username, req.Group, func(roles []*pb.RoleGrant) ([]*pb.RoleGrant, error) {
+		if findRoleGrant(roles, grant) < 0 {
+			roles = append(roles, grant)
+		}
+		return roles, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditGrantRole, admin.Username, grantee, AuditSuccess), "%s granted role %s%s to %s", admin.Username, grant.Role, onResource(grant.Resource), grantee)
+
+	return &pb.GrantRoleResp{}, nil
+}
+
+func (us *userService) RevokeRole(c context.Context, req *pb.RevokeRoleReq) (*pb.RevokeRoleResp, error) {
+	admin, err := us.requireAdmin(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if req.Username == "" && req.Group == "" {
+		return nil, twirp.RequiredArgumentError("RevokeRoleReq.username")
+	}
+	if req.Role == "" {
+		return nil, twirp.RequiredArgumentError("RevokeRoleReq.role")
+	}
+
+	grant := &pb.RoleGrant{Role: req.Role, Resource: req.Resource}
+	grantee, err := us.updateRoles(req.Username, req.Group, func(roles []*pb.RoleGrant) ([]*pb.RoleGrant, error) {
+		i := findRoleGrant(roles, grant)
+		if i < 0 {
+			return nil, twirp.NewError(twirp.NotFound, "role not granted")
+		}
+		return append(roles[:i], roles[i+1:]...), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditRevokeRole, admin.Username, grantee, AuditSuccess), "%s revoked role %s%s from %s", admin.Username, grant.Role, onResource(grant.Resource), grantee)
+
+	return &pb.RevokeRoleResp{}, nil
+}
+
+func (us *userService) Authorize(c context.Context, req *pb.AuthorizeReq) (*pb.AuthorizeResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if req.Permission == "" {
+		return nil, twirp.RequiredArgumentError("AuthorizeReq.permission")
+	}
+
+	allowed, reason, err := us.authorize(session.Username, req.Permission, req.Resource)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.AuthorizeResp{
+		Allowed:  allowed,
+		Reason:   reason,
+		Username: session.Username,
+	}, nil
+}
+
+// GrantAdmins grants the admin role to each of usernames that exists, it is
+// how the first admins are made. The usernames that do not exist are
+// returned, they are granted nothing.
+func (us *userService) GrantAdmins(usernames []string) ([]string, error) {
+	if _, ok := us.Roles[RoleAdmin]; !ok && len(usernames) > 0 {
+		return nil, fmt.Errorf("no %s role is defined", RoleAdmin)
+	}
+	grant := &pb.RoleGrant{Role: RoleAdmin}
+	missing := []string{}
+	for _, username := range usernames {
+		user, err := us.getUser(username)
+		if twerr, ok := err.(twirp.Error); ok && twerr.Code() == twirp.NotFound {
+			missing = append(missing, username)
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		granted := false
+		_, err = us.updateRoles(user.Username, "", func(roles []*pb.RoleGrant) ([]*pb.RoleGrant, error) {
+			if findRoleGrant(roles, grant) < 0 {
+				roles, granted = append(roles, grant), true
+			}
+			return roles, nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if granted {
+			us.recordEvent(context.Background(), auditEvent(AuditGrantRole, "", user.Username, AuditSuccess), "ADMINS granted role %s to %s", RoleAdmin, user.Username)
+		}
+	}
+	return missing, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// authorize decides whether username holds permission on resource and
+// explains the decision
+func (us *userService) authorize(username, permission, resource string) (bool, string, error) {
+	user, err := us.Store.GetUser(username)
+	if err == ErrNotFound {
+		return false, "no such user", nil
+	} else if err != nil {
+		return false, "", err
+	}
+
+	if grant := us.findPermission(user.Roles, permission, resource); grant != nil {
+		return true, fmt.Sprintf("allowed by role %s%s", grant.Role, onResource(grant.Resource)), nil
+	}
+
+	// Roles granted to the user's groups, including nested groups
+	groups, err := us.userGroups(username)
+	if err != nil {
+		return false, "", err
+	}
+	names := []string{}
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		group, err := us.Store.GetGroup(name)
+		if err == ErrNotFound {
+			continue
+		} else if err != nil {
+			return false, "", err
+		}
+		if grant := us.findPermission(group.Roles, permission, resource); grant != nil {
+			return true, fmt.Sprintf("allowed by role %s%s via group %s", grant.Role, onResource(grant.Resource), name), nil
+		}
+	}
+	return false, fmt.Sprintf("no role grants %s%s", permission, onResource(resource)), nil
+}
+
+// findPermission returns the first of grants whose role holds permission on
+// resource, or nil
+func (us *userService) findPermission(grants []*pb.RoleGrant, permission, resource string) *pb.RoleGrant {
+	for _, grant := range grants {
+		if !resourceMatches(grant.Resource, resource) {
+			continue
+		}
+		for _, granted := range us.Roles[grant.Role] {
+			if permissionMatches(granted, permission) {
+				return grant
+			}
+		}
+	}
+	return nil
+}
+
+// updateRoles applies fn to the role grants of a group if group is set,
+// otherwise of a user. It returns a description of the grantee for the
+// audit log.
+func (us *userService) updateRoles(username, group string, fn func(roles []*pb.RoleGrant) ([]*pb.RoleGrant, error)) (string, error) {
+	if group != "" {
+		err := us.Store.UpdateGroup(group, func(g *pb.PrivateGroup) error {
+			roles, err := fn(g.Roles)
+			g.Roles = roles
+			return err
+		})
+		if err == ErrNotFound {
+			return "", twirp.NewError(twirp.NotFound, "group "+group+" not found")
+		}
+		return "group " + group, err
+	}
+
+	// The stored username, not the one given
+	user, err := us.getUser(username)
+	if err != nil {
+		return "", err
+	}
+	username = user.Username
+	err = us.Store.UpdateUser(username, func(user *pb.PrivateUser) error {
+		roles, err := fn(user.Roles)
+		user.Roles = roles
+		return err
+	})
+	if err == ErrNotFound {
+		return "", twirp.NewError(twirp.NotFound, username+" not found")
+	}
+	return username, err
+}
+
+// permissionMatches reports whether a role's permission covers wanted
+func permissionMatches(granted, wanted string) bool {
+	if granted == "*" || granted == wanted {
+		return true
+	}
+	return strings.HasSuffix(granted, ".*") && strings.HasPrefix(wanted, strings.TrimSuffix(granted, "*"))
+}
+
+// resourceMatches reports whether a grant's resource covers resource
+func resourceMatches(granted, resource string) bool {
+	if granted == "" || granted == resource {
+		return true
+	}
+	return strings.HasSuffix(granted, "*") && strings.HasPrefix(resource, strings.TrimSuffix(granted, "*"))
+}
+
+func findRoleGrant(grants []*pb.RoleGrant, grant *pb.RoleGrant) int {
+	for i, g := range grants {
+		if g.Role == grant.Role && g.Resource == grant.Resource {
+			return i
+		}
+	}
+	return -1
+}
+
+func onResource(resource string) string {
+	if resource == "" {
+		return ""
+	}
+	return " on " + resource
+}
@@ -0,0 +1,154 @@
+package usersservice
+
+import (
+	"context"
+	"time"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/satori/go.uuid"
+	"github.com/twitchtv/twirp"
+)
+
+// DefaultRenameReservation is how long a username given up by a rename can
+// only be taken back by its previous owner
+const DefaultRenameReservation = 30 * 24 * time.Hour
+
+func (us *userService) RenameUser(c context.Context, req *pb.RenameUserReq) (*pb.RenameUserResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	username := req.Username
+	if username == "" {
+		username = session.Username
+	}
+	if normalizeUsername(username) != session.Username && username != session.Username {
+		if _, err := us.requireAdmin(c, req.Session); err != nil {
+			return nil, err
+		}
+	}
+	user, err := us.getUser(username)
+	if err != nil {
+		return nil, err
+	}
+	newUsername, err := us.Policy.ValidateUsername("RenameUserReq.new_username", req.NewUsername)
+	if err != nil {
+		return nil, err
+	}
+	if newUsername == user.Username {
+		return nil, twirp.InvalidArgumentError("new_username", "must be different from the current username")
+	}
+	// The reservation of the old name belongs to the id
+	if _, err := us.ensureUserID(user); err != nil {
+		return nil, err
+	}
+
+	now := us.Now()
+	rename := &pb.PrivateRenamedUser{
+		Username:      user.Username,
+		RenamedTo:     newUsername,
+		RenamedAt:     now.Unix(),
+		ReservedUntil: now.Add(us.RenameReservation).Unix(),
+	}
+	switch err := us.Store.RenameUser(rename); err {
+	case nil:
+	case ErrNotFound:
+		return nil, twirp.NewError(twirp.NotFound, user.Username+" not found")
+	case ErrAlreadyExists:
+		return nil, twirp.NewError(twirp.AlreadyExists, "Username: "+newUsername+" already exists")
+	case ErrUsernameReserved:
+		return nil, twirp.NewError(twirp.AlreadyExists, "Username: "+newUsername+" is reserved")
+	default:
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditRenameUser, session.Username, newUsername, AuditSuccess), "%s renamed %s to %s", session.Username, user.Username, newUsername)
+
+	renamed, err := us.getUser(newUsername)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.RenameUserResp{
+		User: publicUser(renamed, true),
+	}, nil
+}
+
+// NormalizeUsernames renames the users registered before usernames were
+// normalized to the normalized form, after which Register can not give the
+// same name in another case to someone else. A user whose normalized name is
+// already taken keeps theirs and is returned, an admin has to rename one of
+// the two.
+func (us *userService) NormalizeUsernames() (int, []string, error) {
+	legacy := []*pb.PrivateUser{}
+	err := us.Store.ForEachUser(func(user *pb.PrivateUser) error {
+		if normalizeUsername(user.Username) != user.Username {
+			legacy = append(legacy, user)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, nil, err
+	}
+
+	renamed, conflicts := 0, []string{}
+	for _, user := range legacy {
+		if _, err := us.ensureUserID(user); err != nil {
+			return renamed, conflicts, err
+		}
+		// Register normalizes, nobody can take the old name
+		now := us.Now().Unix()
+		err := us.Store.RenameUser(&pb.PrivateRenamedUser{
+			Username:      user.Username,
+			RenamedTo:     normalizeUsername(user.Username),
+			RenamedAt:     now,
+			ReservedUntil: now,
+		})
+		switch err {
+		case nil:
+			renamed++
+			us.recordEvent(context.Background(), auditEvent(AuditRenameUser, "", normalizeUsername(user.Username), AuditSuccess), "renamed %s to %s, usernames are normalized", user.Username, normalizeUsername(user.Username))
+		case ErrAlreadyExists, ErrUsernameReserved:
+			conflicts = append(conflicts, user.Username)
+		default:
+			return renamed, conflicts, err
+		}
+	}
+	return renamed, conflicts, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// ensureUserID returns the id of user, giving records older than ids one
+func (us *userService) ensureUserID(user *pb.PrivateUser) (string, error) {
+	if user.Id != "" {
+		return user.Id, nil
+	}
+	err := us.Store.UpdateUser(user.Username, func(current *pb.PrivateUser) error {
+		// Someone else got here first
+		if current.Id == "" {
+			current.Id = uuid.NewV4().String()
+		}
+		user.Id = current.Id
+		return nil
+	})
+	if err == ErrNotFound {
+		return "", twirp.NewError(twirp.NotFound, user.Username+" not found")
+	}
+	return user.Id, err
+}
+
+// previousOwner reports whether the user with userID renamed away from
+// username, whoever has it now
+func (us *userService) previousOwner(username, userID string) (bool, error) {
+	if userID == "" {
+		return false, nil
+	}
+	rename, err := us.Store.GetRenamedUser(username)
+	if err == ErrNotFound {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	return rename.UserId == userID, nil
+}
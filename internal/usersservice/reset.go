@@ -0,0 +1,155 @@
+package usersservice
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"log"
+	"time"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/twitchtv/twirp"
+)
+
+// DefaultResetTokenTTL is how long a password reset token can be used
+const DefaultResetTokenTTL = time.Hour
+
+// ReapResetTokens deletes every expired password reset token and returns how
+// many were deleted
+func (us *userService) ReapResetTokens() (int, error) {
+	return us.Store.DeleteExpiredResetTokens(us.Now().Unix())
+}
+
+func (us *userService) RequestPasswordReset(c context.Context, req *pb.RequestPasswordResetReq) (*pb.RequestPasswordResetResp, error) {
+	if req.UsernameOrEmail == "" {
+		return nil, twirp.RequiredArgumentError("RequestPasswordResetReq.username_or_email")
+	}
+
+	// Do not tell the caller whether the user exists, by the answer or by how
+	// long it takes. The token is stored and sent in the background, see
+	// WaitForNotifications.
+	user, err := us.getUser(req.UsernameOrEmail)
+	if twerr, ok := err.(twirp.Error); ok && twerr.Code() == twirp.NotFound {
+		user, err = us.Store.GetUserByEmail(normalizeEmail(req.UsernameOrEmail))
+	}
+	if err == ErrNotFound {
+		us.audit("password reset requested for unknown user %q", req.UsernameOrEmail)
+		return &pb.RequestPasswordResetResp{}, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := us.Now()
+	us.notifications.Add(1)
+	go func() {
+		defer us.notifications.Done()
+		if err := us.sendPasswordReset(user, now); err != nil {
+			log.Printf("password reset for %s: %s", user.Username, err)
+			Metrics.Add("notification_errors", 1)
+			return
+		}
+		us.audit("password reset requested for %s", user.Username)
+	}()
+
+	return &pb.RequestPasswordResetResp{}, nil
+}
+
+// WaitForNotifications waits for the password resets being stored and sent
+// in the background
+func (us *userService) WaitForNotifications() {
+	us.notifications.Wait()
+}
+
+func (us *userService) ResetPassword(c context.Context, req *pb.ResetPasswordReq) (*pb.ResetPasswordResp, error) {
+	if req.ResetToken == "" {
+		return nil, twirp.RequiredArgumentError("ResetPasswordReq.reset_token")
+	}
+	if err := us.validateNewPassword("ResetPasswordReq.new_password", req.NewPassword); err != nil {
+		return nil, err
+	}
+
+	// Taking the token uses it up, even if the rest of the reset fails
+	token, err := us.Store.TakeResetToken(hashSecretToken(req.ResetToken))
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid reset token")
+	} else if err != nil {
+		return nil, err
+	}
+	if us.Now().Unix() >= token.ExpiresAt {
+		return nil, twirp.NewError(twirp.PermissionDenied, "reset token expired")
+	}
+
+	passwordHash, err := us.Hasher.Hash(req.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+	err = us.Store.UpdateUser(token.Username, func(user *pb.PrivateUser) error {
+		user.PasswordHash = passwordHash
+		user.PasswordSha256 = nil
+		return nil
+	})
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid reset token")
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Whoever knew the old password is logged out
+	revoked, err := us.Store.DeleteUserSessions(token.Username)
+	if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditResetPassword, token.Username, token.Username, AuditSuccess), "%s reset password, %d sessions revoked", token.Username, revoked)
+
+	return &pb.ResetPasswordResp{
+		Revoked: int32(revoked),
+	}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// newSecretToken returns 32 random bytes, url safe encoded. Used for reset
+// and verification tokens.
+func newSecretToken() (string, error) {
+	token := make([]byte, 32)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
+
+// hashSecretToken is how reset and verification tokens are stored. The token
+// has 256 bits of entropy so an unsalted fast hash is enough.
+func hashSecretToken(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+// sendPasswordReset stores a new reset token for user and notifies them of it
+func (us *userService) sendPasswordReset(user *pb.PrivateUser, now time.Time) error {
+	token, err := newSecretToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := now.Add(us.ResetTokenTTL)
+	err = us.Store.PutResetToken(&pb.PrivateResetToken{
+		TokenHash: hashSecretToken(token),
+		Username:  user.Username,
+		CreatedAt: now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return err
+	}
+	return us.Notifier.Notify(&Notification{
+		Kind:      NotifyPasswordReset,
+		Username:  user.Username,
+		Email:     user.Email,
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
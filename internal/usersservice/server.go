@@ -3,206 +3,688 @@ package usersservice
 import (
 	"context"
 	"github.com/twitchtv/twirp"
-	"github.com/syndtr/goleveldb/leveldb"
 	pb "github.com/ericmoritz/twirp-users/rpc/users"
 	"crypto/sha256"
-	"github.com/golang/protobuf/proto"
+	"crypto/subtle"
 	"bytes"
+	"strings"
+	"time"
+	"log"
+	"sort"
+	"io"
+	"sync"
 	"github.com/satori/go.uuid"
 )
 
-// Create a new userService
-func New(dbPath string) (*userService, error) {
-	db, err := leveldb.OpenFile(dbPath, nil)
-	if err != nil {
-		return nil, err
-	}
-
-	return &userService{DB: db}, nil
-}
-
 type userService struct {
-	DB *leveldb.DB
+	Store  Store
+	Hasher PasswordHasher // used to hash new passwords
+	Now    func() time.Time
+
+	SessionIdleTimeout time.Duration // a session expires when unused for this long
+	SessionLifetime    time.Duration // a session expires this long after Login no matter what
+
+	AuditLog *log.Logger // security relevant events are written here, nil disables it
+
+	ResetTokenTTL        time.Duration // how long a password reset token can be used
+	VerificationTokenTTL time.Duration // how long an email verification token can be used
+	Notifier             Notifier      // delivers password reset and email verification tokens
+
+	Roles map[string][]string // role name to the permissions it grants
+
+	RenameReservation time.Duration // how long a username given up by a rename is kept for its previous owner
+
+	Policy   *Policy       // rules for new usernames and passwords
+	Breaches BreachChecker // new passwords found here are rejected, nil disables the check
+
+	SecretKey         []byte        // seals TOTP secrets, nil disables two-factor enrollment
+	TOTPIssuer        string        // names the service in authenticator apps
+	LoginChallengeTTL time.Duration // how long a Login has to be completed by VerifySecondFactor, and a WebAuthn ceremony
+
+	WebAuthn *WebAuthn // the relying party for passkeys, nil disables WebAuthn
+
+	LoginFreeAttempts        int           // failed logins for a username before backing off
+	LoginBackoff             time.Duration // the first backoff, it doubles with each further failure
+	MaxLoginBackoff          time.Duration // the longest backoff, 0 disables per-username throttling
+	LoginFailureWindow       time.Duration // failures are forgotten after this long without one
+	MaxLoginFailureUsernames int           // usernames with failures kept, past it the least recently tried unlocked one is forgotten
+	IPLoginRate              float64       // login attempts a second per client ip, 0 disables it
+	IPLoginBurst             int           // login attempts a client ip can make at once
+
+	// HideUsernames stops Login and User from telling whether a username
+	// exists. Login fails the same way for unknown users and wrong passwords
+	// and checks a dummy hash so both take as long, User needs a session.
+	// Register can not hide a taken username so it shares the per-ip limit
+	// of Login.
+	HideUsernames bool
+
+	throttle       loginThrottle
+	dummyHashOnce  sync.Once
+	dummyHash      string
+	auditMu        sync.Mutex
+	lastAuditNanos int64                       // of the last audit event id, ids never go back
+	loginFailures  map[string]*countedFailures // by reason, see recordLoginFailure
+	notifications  sync.WaitGroup              // password resets being sent, see RequestPasswordReset
+	opened         []io.Closer                 // by the options, closed if New fails
 }
 
 // Register registers a user
 func (us *userService) Register(c context.Context, req *pb.RegisterReq) (*pb.RegisterResp, error) {
 	// Validate the username
-	if req.Username == "" {
-		return nil, twirp.RequiredArgumentError("RegisterReq.username")
+	username, err := us.Policy.ValidateUsername("RegisterReq.username", req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := us.validateNewPassword("RegisterReq.password", req.Password); err != nil {
+		return nil, err
 	}
 
-	if req.Password == "" {
-		return nil, twirp.RequiredArgumentError("RegisterReq.password")
+	email := ""
+	if req.Email != "" {
+		if email, err = validateEmail("RegisterReq.email", req.Email); err != nil {
+			return nil, err
+		}
 	}
 
 	////
 	// Create the User
 	////
+	passwordHash, err := us.Hasher.Hash(req.Password)
+	if err != nil {
+		return nil, err
+	}
 	user := &pb.PrivateUser{
-		Username: req.Username,
-		PasswordSha256: hashPassword(req.Password),
+		Id: uuid.NewV4().String(),
+		Username: username,
+		PasswordHash: passwordHash,
+		Email: email,
+		CreatedAt: us.Now().Unix(),
 	}
 
 	////
 	// Store the user
 	////
-	if err := putUser(us.DB, user); err != nil {
+	event := auditEvent(AuditRegister, "", user.Username, AuditFailure)
+	if err := us.Store.CreateUser(user); err == ErrAlreadyExists {
+		us.recordEvent(c, event, "register %s failed: username taken", user.Username)
+		return nil, twirp.NewError(twirp.AlreadyExists, "Username: " + user.Username + " already exists")
+	} else if err == ErrUsernameReserved {
+		us.recordEvent(c, event, "register %s failed: username reserved", user.Username)
+		return nil, twirp.NewError(twirp.AlreadyExists, "Username: " + user.Username + " is reserved")
+	} else if err == ErrEmailInUse {
+		us.recordEvent(c, event, "register %s failed: email in use", user.Username)
+		return nil, twirp.NewError(twirp.AlreadyExists, "Email: " + user.Email + " already in use")
+	} else if err != nil {
 		return nil, err
 	}
+	event.Actor, event.Outcome = user.Username, AuditSuccess
+	us.recordEvent(c, event, "%s registered", user.Username)
+
+	// The user exists now, they can ask for another token if this one is lost
+	if user.Email != "" {
+		if err := us.sendVerification(user); err != nil {
+			log.Printf("email verification for %s: %s", user.Username, err)
+		}
+	}
 
 	// Return the response
 	return &pb.RegisterResp{
-		User: &pb.User{
-			Username: user.Username,
-		},
+		User: publicUser(user, true),
 	}, nil
 }
 
 func (us *userService) Login(c context.Context, req *pb.LoginReq) (*pb.LoginResp, error) {
+	// Back off after too many failures, whether or not the user exists. The
+	// attempt counts as failed until the password checks out.
+	throttleKey := normalizeUsername(req.Username)
+	attempt, wait := us.reserveLogin(throttleKey)
+	if wait > 0 {
+		us.recordLoginFailure(c, throttleKey, "too many attempts")
+		return nil, errRateLimited(c, wait)
+	}
+	defer attempt.release()
+
 	// Find the user
-	user, err := getUser(us.DB, req.Username)
+	user, err := us.getUser(req.Username)
+	if twerr, ok := err.(twirp.Error); ok && twerr.Code() == twirp.NotFound {
+		attempt.failed()
+		us.recordLoginFailure(c, throttleKey, "unknown user")
+		if us.HideUsernames {
+			us.checkDummyPassword(req.Password)
+			return nil, errBadLogin()
+		}
+	}
 	if err != nil {
 		return nil, err
 	}
 
 	// If the username in blank, the user does not exist
 	if user.Username == "" {
+		attempt.failed()
+		if us.HideUsernames {
+			us.checkDummyPassword(req.Password)
+			return nil, errBadLogin()
+		}
 		return nil, twirp.NewError(twirp.PermissionDenied, "bad username")
 	}
 
 	// Check the passwords
-	if bytes.Compare(user.PasswordSha256, hashPassword(req.Password)) != 0 {
+	ok, err := checkPassword(user, req.Password)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		attempt.failed()
+		us.recordEvent(c, loginEvent(user.Username, AuditFailure), "%s login failed: bad password", user.Username)
+		if us.HideUsernames {
+			// Legacy digests check in no time, take as long as an unknown user
+			if user.PasswordHash == "" {
+				us.checkDummyPassword(req.Password)
+			}
+			return nil, errBadLogin()
+		}
 		return nil, twirp.NewError(twirp.PermissionDenied, "bad password")
 	}
 
-	// Login successful, create a session token
-	session := &pb.Session{
-		Token: uuid.NewV4().String(),
-		Username: user.Username,
+	// Only tell the account is disabled to someone who knows the password
+	if user.Disabled {
+		us.recordEvent(c, loginEvent(user.Username, AuditFailure), "%s login failed: account disabled", user.Username)
+		return nil, errAccountDisabled()
 	}
-	// Store the session
-	if err := putSession(us.DB, session); err != nil {
+
+	// Move legacy records to the current hasher now that we know the password
+	if user.PasswordHash == "" {
+		passwordHash, err := us.Hasher.Hash(req.Password)
+		if err != nil {
+			return nil, err
+		}
+		if err := upgradePasswordHash(us.Store, user, passwordHash); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sessions and login challenges reference the user by id
+	if _, err := us.ensureUserID(user); err != nil {
 		return nil, err
 	}
 
+	// The session waits for VerifySecondFactor
+	if user.TotpEnabled {
+		challenge, err := us.newLoginChallenge(user)
+		if err != nil {
+			return nil, err
+		}
+		return &pb.LoginResp{
+			SecondFactorChallenge: challenge,
+		}, nil
+	}
+
+	// Login successful, create a session token
+	session, err := us.startSession(c, user)
+	if err != nil {
+		return nil, err
+	}
+	attempt.succeeded()
+	us.recordEvent(c, loginEvent(user.Username, AuditSuccess), "%s logged in", user.Username)
 	return &pb.LoginResp{
 		Session: session,
 	}, nil
 }
 
 func (us *userService) User(c context.Context, req *pb.UserReq) (*pb.UserResp, error) {
-	user, err := getUser(us.DB, req.Username)
+	viewer := ""
+	if us.HideUsernames || (req.Session != nil && req.Session.Token != "") {
+		session, err := us.validateSession(c, req.Session)
+		if err != nil {
+			return nil, err
+		}
+		viewer = session.Username
+	}
+
+	user, err := us.getUser(req.Username)
 	if err != nil {
 		return nil, err
 	}
 
+	// The user themselves and admins see the private fields
+	self := viewer != "" && viewer == user.Username
+	if viewer != "" && !self {
+		if self, _, err = us.authorize(viewer, PermissionAdmin, ""); err != nil {
+			return nil, err
+		}
+	}
+
 	return &pb.UserResp{
-		User: &pb.User{
-			Username: user.Username,
-		},
+		User: publicUser(user, self),
 	}, nil
 }
 
 func (us *userService) CurrentUser(c context.Context, req *pb.CurrentUserReq) (*pb.CurrentUserResp, error) {
-	session, err := validateSession(us.DB, req.Session)
+	session, err := us.validateSession(c, req.Session)
 	if err != nil {
 		return nil, err
 	}
-	user, err := getUser(us.DB, session.Username)
+	if err := us.renewSession(session); err != nil {
+		return nil, err
+	}
+	user, err := us.getUser(session.Username)
 	if err != nil {
 		return nil, err
 	}
+	if user.Disabled {
+		return nil, errAccountDisabled()
+	}
 	return &pb.CurrentUserResp{
-		User: &pb.User{
-			Username: user.Username,
-		},
+		User: publicUser(user, true),
 	}, nil
 }
 
-///////////////////////////////////////////////////////////////////////////////
-// Internal
-///////////////////////////////////////////////////////////////////////////////
-func validateSession(db *leveldb.DB, session *pb.Session) (*pb.Session, error) {
-	_, err := getSession(db, session.Token)
-	if err == leveldb.ErrNotFound {
-		return nil, twirp.NewError(twirp.PermissionDenied, "invalid session token")
-	} else if err != nil {
+func (us *userService) Logout(c context.Context, req *pb.LogoutReq) (*pb.LogoutResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
 		return nil, err
 	}
-	return session, nil
+	if err := us.Store.DeleteSession(session); err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditLogout, session.Username, session.Username, AuditSuccess), "%s logged out", session.Username)
+	return &pb.LogoutResp{}, nil
 }
 
-func hashPassword(password string) []byte {
-	// Sha the password
-	h := sha256.New()
-	h.Write([]byte(password))
-	return h.Sum(nil)
+func (us *userService) LogoutAll(c context.Context, req *pb.LogoutAllReq) (*pb.LogoutAllResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	revoked, err := us.Store.DeleteUserSessions(session.Username)
+	if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditLogout, session.Username, session.Username, AuditSuccess), "%s logged out %d sessions", session.Username, revoked)
+
+	return &pb.LogoutAllResp{
+		Revoked: int32(revoked),
+	}, nil
 }
 
+func (us *userService) RevokeSession(c context.Context, req *pb.RevokeSessionReq) (*pb.RevokeSessionResp, error) {
+	if req.SessionId != "" {
+		return us.revokeOwnSession(c, req.Session, req.SessionId)
+	}
 
-func getUser(db *leveldb.DB, username string) (*pb.PrivateUser, error) {
-	bytes, err := db.Get(userKey(username), nil)
-	if err == leveldb.ErrNotFound {
-		return nil, twirp.NewError(twirp.NotFound, username + " not found")
+	admin, err := us.requireAdmin(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if req.Token == "" {
+		return nil, twirp.RequiredArgumentError("RevokeSessionReq.token")
+	}
+
+	session, err := us.Store.GetSession(req.Token)
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, "session not found")
 	} else if err != nil {
 		return nil, err
 	}
+	if err := us.Store.DeleteSession(session); err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditRevokeSession, admin.Username, session.Username, AuditSuccess), "%s revoked a session of %s", admin.Username, session.Username)
 
-	user := &pb.PrivateUser{}
-	if err := proto.Unmarshal(bytes, user); err != nil {
+	return &pb.RevokeSessionResp{}, nil
+}
+
+func (us *userService) ListSessions(c context.Context, req *pb.ListSessionsReq) (*pb.ListSessionsResp, error) {
+	current, err := us.validateSession(c, req.Session)
+	if err != nil {
 		return nil, err
 	}
-	return user, nil
+	sessions, err := us.userSessions(current.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	// Most recently used first
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeenAt > sessions[j].LastSeenAt
+	})
+
+	infos := make([]*pb.SessionInfo, len(sessions))
+	for i, session := range sessions {
+		infos[i] = sessionInfo(session)
+		infos[i].Current = session.Token == current.Token
+	}
+	return &pb.ListSessionsResp{
+		Sessions: infos,
+	}, nil
 }
 
+func (us *userService) ChangePassword(c context.Context, req *pb.ChangePasswordReq) (*pb.ChangePasswordResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if req.OldPassword == "" {
+		return nil, twirp.RequiredArgumentError("ChangePasswordReq.old_password")
+	}
+	if err := us.validateNewPassword("ChangePasswordReq.new_password", req.NewPassword); err != nil {
+		return nil, err
+	}
+	if req.NewPassword == req.OldPassword {
+		return nil, twirp.InvalidArgumentError("ChangePasswordReq.new_password", "must differ from old_password")
+	}
 
-func putUser(db *leveldb.DB, user *pb.PrivateUser) error {
-	exists, err := db.Has(userKey(user.Username), nil)
+	// Re-verify the current password, a stolen session is not enough
+	user, err := us.getUser(session.Username)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if exists == true {
-		return twirp.NewError(twirp.AlreadyExists, "Username: " + user.Username + " already exists")
+	ok, err := checkPassword(user, req.OldPassword)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		us.recordEvent(c, auditEvent(AuditChangePassword, session.Username, session.Username, AuditFailure), "%s failed to change password: bad password", session.Username)
+		return nil, twirp.NewError(twirp.PermissionDenied, "bad password")
 	}
 
-	// Store the user into the db
-	bytes, err := proto.Marshal(user)
+	passwordHash, err := us.Hasher.Hash(req.NewPassword)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	err = us.Store.UpdateUser(user.Username, func(current *pb.PrivateUser) error {
+		current.PasswordHash = passwordHash
+		current.PasswordSha256 = nil
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	revoked := 0
+	if req.RevokeOtherSessions {
+		sessions, err := us.Store.UserSessions(session.Username)
+		if err != nil {
+			return nil, err
+		}
+		for _, other := range sessions {
+			if other.Token == session.Token {
+				continue
+			}
+			if err := us.Store.DeleteSession(other); err != nil {
+				return nil, err
+			}
+			revoked++
+		}
 	}
+	us.recordEvent(c, auditEvent(AuditChangePassword, session.Username, session.Username, AuditSuccess), "%s changed password, %d other sessions revoked", session.Username, revoked)
 
-	return db.Put(userKey(user.Username), bytes, nil)
+	return &pb.ChangePasswordResp{
+		Revoked: int32(revoked),
+	}, nil
 }
 
+// PasswordReport counts the stored users by password hash scheme
+type PasswordReport struct {
+	Total   int
+	Legacy  int            // users still on the unsalted sha256 digest
+	Schemes map[string]int // users by hash scheme, ex: argon2id
+}
 
-func putSession(db *leveldb.DB, session *pb.Session) error {
-	bytes, err := proto.Marshal(session)
+// PasswordReport reports how many users remain on the legacy password scheme
+func (us *userService) PasswordReport() (*PasswordReport, error) {
+	report := &PasswordReport{Schemes: map[string]int{}}
+
+	err := us.Store.ForEachUser(func(user *pb.PrivateUser) error {
+		report.Total++
+		if user.PasswordHash == "" {
+			report.Legacy++
+		} else {
+			report.Schemes[hashScheme(user.PasswordHash)]++
+		}
+		return nil
+	})
 	if err != nil {
-		return err
+		return nil, err
 	}
+	return report, nil
+}
 
-	return db.Put(sessionKey(session.Token), bytes, nil)
+// Close waits for the notifications being sent and closes the underlying Store
+func (us *userService) Close() error {
+	us.WaitForNotifications()
+	if closer, ok := us.Breaches.(io.Closer); ok {
+		closer.Close()
+	}
+	return us.Store.Close()
 }
 
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+// validateSession looks up the stored session for a client's session. Only
+// the token is trusted, everything else comes from the stored session.
+func (us *userService) validateSession(c context.Context, session *pb.Session) (*pb.PrivateSession, error) {
+	if session == nil || session.Token == "" {
+		return nil, twirp.RequiredArgumentError("session.token")
+	}
 
-func getSession(db *leveldb.DB, token string) (*pb.Session, error) {
-	bytes, err := db.Get(sessionKey(token), nil)
+	stored, err := us.Store.GetSession(session.Token)
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid session token")
+	} else if err != nil {
+		return nil, err
+	}
+
+	if sessionExpired(stored, us.Now().Unix()) {
+		if err := us.Store.DeleteSession(stored); err != nil {
+			return nil, err
+		}
+		return nil, twirp.NewError(twirp.PermissionDenied, "session expired")
+	}
+
+	// The user may have been renamed since the session was stored
+	if stored.UserId != "" {
+		user, err := us.Store.GetUserByID(stored.UserId)
+		if err == ErrNotFound {
+			return nil, twirp.NewError(twirp.PermissionDenied, "invalid session token")
+		} else if err != nil {
+			return nil, err
+		}
+		stored.Username = user.Username
+	}
+
+	// Older clients echo the whole Session back, make sure they didn't edit it
+	if session.Username != "" && session.Username != stored.Username {
+		renamed, err := us.previousOwner(session.Username, stored.UserId)
+		if err != nil {
+			return nil, err
+		}
+		if !renamed {
+			us.recordEvent(c, auditEvent(AuditForgedSession, "", stored.Username, AuditFailure), "session username mismatch: token for %q presented as %q", stored.Username, session.Username)
+			return nil, twirp.NewError(twirp.PermissionDenied, "invalid session")
+		}
+	}
+	return stored, nil
+}
+
+// revokeOwnSession ends the caller's session with the id from ListSessions
+func (us *userService) revokeOwnSession(c context.Context, caller *pb.Session, id string) (*pb.RevokeSessionResp, error) {
+	current, err := us.validateSession(c, caller)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := us.userSessions(current.Username)
 	if err != nil {
 		return nil, err
 	}
 
-	session := &pb.Session{}
-	if err := proto.Unmarshal(bytes, session); err != nil {
+	for _, session := range sessions {
+		if sessionID(session.Token) == id {
+			if err := us.Store.DeleteSession(session); err != nil {
+				return nil, err
+			}
+			us.recordEvent(c, auditEvent(AuditRevokeSession, current.Username, current.Username, AuditSuccess), "%s revoked their session %s", current.Username, id)
+			return &pb.RevokeSessionResp{}, nil
+		}
+	}
+	return nil, twirp.NewError(twirp.NotFound, "session not found")
+}
+
+// requireAdmin validates session and checks that its user holds
+// PermissionAdmin
+func (us *userService) requireAdmin(c context.Context, session *pb.Session) (*pb.PrivateSession, error) {
+	stored, err := us.validateSession(c, session)
+	if err != nil {
+		return nil, err
+	}
+	allowed, _, err := us.authorize(stored.Username, PermissionAdmin, "")
+	if err != nil {
+		return nil, err
+	}
+	if !allowed {
+		us.recordEvent(c, auditEvent(AuditAdminAccess, stored.Username, stored.Username, AuditFailure), "%s denied admin access", stored.Username)
+		return nil, twirp.NewError(twirp.PermissionDenied, "admin only")
+	}
+	return stored, nil
+}
+
+// audit writes a security relevant event to the audit log
+func (us *userService) audit(format string, v ...interface{}) {
+	if us.AuditLog != nil {
+		us.AuditLog.Printf(format, v...)
+	}
+}
+
+// startSession stores a new session for an authenticated user, who must have
+// an id
+func (us *userService) startSession(c context.Context, user *pb.PrivateUser) (*pb.Session, error) {
+	session := us.newSession(c, uuid.NewV4().String(), user.Username)
+	session.UserId = user.Id
+	// Store the session
+	if err := us.Store.PutSession(session); err != nil {
+		return nil, err
+	}
+
+	// DisableUser marks the user then deletes their sessions. Checking again
+	// after storing ours means a racing DisableUser either deletes it or is
+	// seen here.
+	current, err := us.getUser(user.Username)
+	if err == nil && current.Disabled {
+		err = errAccountDisabled()
+	}
+	if err != nil {
+		us.Store.DeleteSession(session)
 		return nil, err
 	}
-	return session, nil
+	return publicSession(session), nil
 }
 
-func userKey(username string) []byte {
-	return []byte("users/" + username)
+// validateNewPassword applies the policy and the breach check to a new
+// password
+func (us *userService) validateNewPassword(field, password string) error {
+	if err := us.Policy.ValidatePassword(field, password); err != nil {
+		return err
+	}
+	if us.Breaches == nil {
+		return nil
+	}
+
+	breached, err := us.Breaches.Breached(password)
+	if err != nil {
+		return err
+	}
+	if breached {
+		Metrics.Add("breached_password_rejections", 1)
+		violations := newViolations()
+		violations.add("breached", "has appeared in a data breach")
+		return violations.err(field)
+	}
+	return nil
+}
+
+// errBadLogin is the Login error for both unknown users and wrong passwords
+// when HideUsernames is set
+func errBadLogin() error {
+	return twirp.NewError(twirp.PermissionDenied, "bad username or password")
+}
+
+// checkDummyPassword spends as long on password as checking a hash made by
+// Hasher would, so a Login for an unknown username or a legacy record can
+// not be told apart by its timing. Hashes made by another hasher or cost
+// still take as long as they take.
+func (us *userService) checkDummyPassword(password string) {
+	us.dummyHashOnce.Do(func() {
+		dummyHash, err := us.Hasher.Hash(uuid.NewV4().String())
+		if err != nil {
+			log.Printf("dummy password hash: %s", err)
+			return
+		}
+		us.dummyHash = dummyHash
+	})
+	if us.dummyHash != "" {
+		us.Hasher.Verify(password, us.dummyHash)
+	}
 }
 
-func sessionKey(token string) []byte {
-	return []byte("sessions/" + token)
+// checkPassword verifies password against the user's stored hash
+func checkPassword(user *pb.PrivateUser, password string) (bool, error) {
+	if user.PasswordHash == "" {
+		// Records created before PasswordHasher only have a sha256 digest
+		return subtle.ConstantTimeCompare(user.PasswordSha256, legacyHashPassword(password)) == 1, nil
+	}
+	return VerifyPassword(password, user.PasswordHash)
+}
+
+// upgradePasswordHash replaces the legacy digest of user with passwordHash.
+// The record is re-read inside UpdateUser so a concurrent upgrade is not
+// clobbered.
+func upgradePasswordHash(store Store, user *pb.PrivateUser, passwordHash string) error {
+	return store.UpdateUser(user.Username, func(current *pb.PrivateUser) error {
+		// Someone else got here first
+		if current.PasswordHash != "" || !bytes.Equal(current.PasswordSha256, user.PasswordSha256) {
+			return nil
+		}
+
+		current.PasswordHash = passwordHash
+		current.PasswordSha256 = nil
+		return nil
+	})
+}
+
+// hashScheme returns the scheme name of an encoded hash
+func hashScheme(encoded string) string {
+	if strings.HasPrefix(encoded, "$2") {
+		return "bcrypt"
+	}
+	parts := strings.SplitN(encoded, "$", 3)
+	if len(parts) < 3 {
+		return "unknown"
+	}
+	return parts[1]
+}
+
+// legacyHashPassword is the unsalted digest used by old PrivateUser records
+func legacyHashPassword(password string) []byte {
+	// Sha the password
+	h := sha256.New()
+	h.Write([]byte(password))
+	return h.Sum(nil)
+}
+
+
+// getUser finds a user, returning a twirp NotFound error if it does not exist
+func (us *userService) getUser(username string) (*pb.PrivateUser, error) {
+	user, err := us.Store.GetUser(normalizeUsername(username))
+	if err == ErrNotFound && normalizeUsername(username) != username {
+		// Users registered before normalization keep their name as given
+		user, err = us.Store.GetUser(username)
+	}
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, username + " not found")
+	} else if err != nil {
+		return nil, err
+	}
+	return user, nil
 }
@@ -0,0 +1,153 @@
+package usersservice
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/twitchtv/twirp"
+)
+
+const (
+	// DefaultSessionIdleTimeout is how long a session lives without being used
+	DefaultSessionIdleTimeout = 24 * time.Hour
+
+	// DefaultSessionLifetime is how long a session lives no matter how often
+	// it is used
+	DefaultSessionLifetime = 30 * 24 * time.Hour
+)
+
+// ReapSessions deletes every expired session and returns how many were
+// deleted
+func (us *userService) ReapSessions() (int, error) {
+	return us.Store.DeleteExpiredSessions(us.Now().Unix())
+}
+
+// StartSessionReaper deletes expired sessions, tokens and login challenges
+// every interval in the background until the returned stop func is called
+func (us *userService) StartSessionReaper(interval time.Duration) (stop func()) {
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-ticker.C:
+				if _, err := us.ReapSessions(); err != nil {
+					log.Printf("session reaper: %s", err)
+				}
+				if _, err := us.ReapResetTokens(); err != nil {
+					log.Printf("session reaper: %s", err)
+				}
+				if _, err := us.ReapVerificationTokens(); err != nil {
+					log.Printf("session reaper: %s", err)
+				}
+				if _, err := us.ReapLoginChallenges(); err != nil {
+					log.Printf("session reaper: %s", err)
+				}
+			case <-done:
+				ticker.Stop()
+				return
+			}
+		}
+	}()
+
+	return func() { close(done) }
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// newSession creates a session for username that starts now
+func (us *userService) newSession(c context.Context, token, username string) *pb.PrivateSession {
+	now := us.Now().Unix()
+	client := ClientInfoFromContext(c)
+	session := &pb.PrivateSession{
+		Token:      token,
+		Username:   username,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ClientIp:   client.IP,
+		UserAgent:  client.UserAgent,
+	}
+	session.ExpiresAt = us.sessionExpiry(session)
+	return session
+}
+
+// renewSession slides the idle timeout of a stored session forward. A
+// session revoked since it was read stays revoked.
+func (us *userService) renewSession(session *pb.PrivateSession) error {
+	session.LastSeenAt = us.Now().Unix()
+	session.ExpiresAt = us.sessionExpiry(session)
+	err := us.Store.RenewSession(session)
+	if err == ErrNotFound {
+		return twirp.NewError(twirp.PermissionDenied, "invalid session token")
+	}
+	return err
+}
+
+// sessionExpiry is the idle timeout capped by the absolute lifetime
+func (us *userService) sessionExpiry(session *pb.PrivateSession) int64 {
+	idle := session.LastSeenAt + int64(us.SessionIdleTimeout/time.Second)
+	absolute := session.CreatedAt + int64(us.SessionLifetime/time.Second)
+	if absolute < idle {
+		return absolute
+	}
+	return idle
+}
+
+// sessionExpired checks a stored session. Sessions stored before expiry was
+// tracked have no ExpiresAt and are treated as expired.
+func sessionExpired(session *pb.PrivateSession, now int64) bool {
+	return now >= session.ExpiresAt
+}
+
+// userSessions returns the active sessions of username
+func (us *userService) userSessions(username string) ([]*pb.PrivateSession, error) {
+	stored, err := us.Store.UserSessions(username)
+	if err != nil {
+		return nil, err
+	}
+
+	now := us.Now().Unix()
+	sessions := []*pb.PrivateSession{}
+	for _, session := range stored {
+		if !sessionExpired(session, now) {
+			sessions = append(sessions, session)
+		}
+	}
+	return sessions, nil
+}
+
+// sessionID is a stable id for a session that does not reveal its token
+func sessionID(token string) string {
+	sum := sha256.Sum256([]byte("session-id/" + token))
+	return hex.EncodeToString(sum[:12])
+}
+
+// sessionInfo describes a stored session without its token
+func sessionInfo(session *pb.PrivateSession) *pb.SessionInfo {
+	return &pb.SessionInfo{
+		Id:         sessionID(session.Token),
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+		ClientIp:   session.ClientIp,
+		UserAgent:  session.UserAgent,
+	}
+}
+
+// publicSession is the client facing copy of a stored session
+func publicSession(session *pb.PrivateSession) *pb.Session {
+	return &pb.Session{
+		Token:      session.Token,
+		Username:   session.Username,
+		CreatedAt:  session.CreatedAt,
+		LastSeenAt: session.LastSeenAt,
+		ExpiresAt:  session.ExpiresAt,
+	}
+}
@@ -0,0 +1,243 @@
+package usersservice
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+)
+
+var (
+	// ErrNotFound is returned by a Store when a record does not exist
+	ErrNotFound = errors.New("not found")
+
+	// ErrAlreadyExists is returned by a Store when a record would be overwritten
+	ErrAlreadyExists = errors.New("already exists")
+
+	// ErrEmailInUse is returned by a Store when a user's email belongs to
+	// another user
+	ErrEmailInUse = errors.New("email in use")
+
+	// ErrUsernameReserved is returned by a Store when a username was given up
+	// by a rename and is still reserved for its previous owner
+	ErrUsernameReserved = errors.New("username reserved")
+
+	// ErrCredentialInUse is returned by a Store when a user's WebAuthn
+	// credential id is registered to another user
+	ErrCredentialInUse = errors.New("credential in use")
+)
+
+// Store persists users and sessions. Implementations must be safe for
+// concurrent use.
+type Store interface {
+	////
+	// Users
+	////
+
+	// GetUser returns ErrNotFound if the user does not exist
+	GetUser(username string) (*pb.PrivateUser, error)
+
+	// GetUserByEmail finds a user by normalized email, it returns ErrNotFound
+	// if no user has verified it
+	GetUserByEmail(email string) (*pb.PrivateUser, error)
+
+	// GetUserByID returns ErrNotFound if no user has the id
+	GetUserByID(id string) (*pb.PrivateUser, error)
+
+	// CreateUser returns ErrAlreadyExists if the username is taken,
+	// ErrUsernameReserved if it is reserved after user.CreatedAt,
+	// ErrEmailInUse if another user verified the email, verified or not, and
+	// ErrCredentialInUse if another user has one of its WebAuthn credentials.
+	// Only a verified email is reserved for the user.
+	CreateUser(user *pb.PrivateUser) error
+
+	// UpdateUser atomically reads a user, passes it to fn and writes it back.
+	// Nothing is written if fn returns an error, the error is returned as is.
+	// It returns ErrEmailInUse if fn verified an email another user verified
+	// and ErrCredentialInUse if fn added a WebAuthn credential another user
+	// has.
+	UpdateUser(username string, fn func(user *pb.PrivateUser) error) error
+
+	// ForEachUser calls fn with every user, ordered by username
+	ForEachUser(fn func(user *pb.PrivateUser) error) error
+
+	// ListUsers returns up to limit users whose username starts with prefix
+	// and sorts after after, ordered by username
+	ListUsers(prefix, after string, limit int) ([]*pb.PrivateUser, error)
+
+	// DeleteUser atomically removes a user with their email, sessions,
+	// tokens and group memberships, returning ErrNotFound if there is no
+	// such user
+	DeleteUser(username string) error
+
+	// RenameUser atomically moves the user rename.Username to
+	// rename.RenamedTo along with everything stored under the username, and
+	// records rename, with its UserId set, to reserve the old username. It
+	// returns ErrNotFound if the user does not exist, ErrAlreadyExists if the
+	// new username is taken and ErrUsernameReserved if it is reserved for
+	// another user after rename.RenamedAt.
+	RenameUser(rename *pb.PrivateRenamedUser) error
+
+	// GetRenamedUser returns the last rename away from username, or
+	// ErrNotFound
+	GetRenamedUser(username string) (*pb.PrivateRenamedUser, error)
+
+	////
+	// Groups
+	////
+
+	// GetGroup returns ErrNotFound if the group does not exist
+	GetGroup(name string) (*pb.PrivateGroup, error)
+
+	// CreateGroup returns ErrAlreadyExists if the name is taken
+	CreateGroup(group *pb.PrivateGroup) error
+
+	// UpdateGroup atomically reads a group, passes it to fn and writes it
+	// back, like UpdateUser
+	UpdateGroup(name string, fn func(group *pb.PrivateGroup) error) error
+
+	// AddGroupMember adds member to a group, it is not an error if it is
+	// already a member. It returns ErrNotFound if the group does not exist.
+	AddGroupMember(group string, member *pb.Member) error
+
+	// RemoveGroupMember returns ErrNotFound if member is not in the group
+	RemoveGroupMember(group string, member *pb.Member) error
+
+	// GroupMembers returns the direct members of a group, users first, each
+	// sorted by name
+	GroupMembers(group string) ([]*pb.Member, error)
+
+	// MemberGroups returns the names of the groups member is directly in,
+	// sorted
+	MemberGroups(member *pb.Member) ([]string, error)
+
+	////
+	// Sessions
+	////
+
+	// GetSession returns ErrNotFound if the session does not exist
+	GetSession(token string) (*pb.PrivateSession, error)
+
+	// PutSession creates or replaces a session
+	PutSession(session *pb.PrivateSession) error
+
+	// RenewSession atomically updates LastSeenAt and ExpiresAt of a stored
+	// session. It returns ErrNotFound if the session was deleted, it never
+	// brings one back.
+	RenewSession(session *pb.PrivateSession) error
+
+	// DeleteSession removes a session, it is not an error if it is already gone
+	DeleteSession(session *pb.PrivateSession) error
+
+	// UserSessions returns every stored session of a user, including
+	// expired sessions that have not been reaped yet
+	UserSessions(username string) ([]*pb.PrivateSession, error)
+
+	// DeleteUserSessions removes every session of a user and returns how
+	// many were removed
+	DeleteUserSessions(username string) (int, error)
+
+	// DeleteExpiredSessions removes every session expired at now (unix
+	// seconds) and returns how many were removed
+	DeleteExpiredSessions(now int64) (int, error)
+
+	////
+	// Password reset tokens
+	////
+
+	// PutResetToken stores a pending password reset
+	PutResetToken(token *pb.PrivateResetToken) error
+
+	// TakeResetToken atomically removes and returns the reset token with
+	// tokenHash, so a token can only be used once. It returns ErrNotFound if
+	// the token does not exist or was already taken.
+	TakeResetToken(tokenHash string) (*pb.PrivateResetToken, error)
+
+	// DeleteExpiredResetTokens removes every reset token expired at now
+	// (unix seconds) and returns how many were removed
+	DeleteExpiredResetTokens(now int64) (int, error)
+
+	////
+	// Email verification tokens
+	////
+
+	// PutVerificationToken stores a pending email verification
+	PutVerificationToken(token *pb.PrivateVerificationToken) error
+
+	// TakeVerificationToken atomically removes and returns the verification
+	// token with tokenHash. It returns ErrNotFound if the token does not
+	// exist or was already taken.
+	TakeVerificationToken(tokenHash string) (*pb.PrivateVerificationToken, error)
+
+	// DeleteExpiredVerificationTokens removes every verification token
+	// expired at now (unix seconds) and returns how many were removed
+	DeleteExpiredVerificationTokens(now int64) (int, error)
+
+	////
+	// Second factor login challenges
+	////
+
+	// PutLoginChallenge creates or replaces a login challenge
+	PutLoginChallenge(challenge *pb.PrivateLoginChallenge) error
+
+	// TakeLoginChallenge atomically removes and returns the challenge with
+	// challengeHash. It returns ErrNotFound if the challenge does not exist
+	// or was already taken.
+	TakeLoginChallenge(challengeHash string) (*pb.PrivateLoginChallenge, error)
+
+	// DeleteExpiredLoginChallenges removes every login challenge expired at
+	// now (unix seconds) and returns how many were removed
+	DeleteExpiredLoginChallenges(now int64) (int, error)
+
+	////
+	// Audit events
+	////
+
+	// AppendAuditEvent stores an event under its id. Events are never
+	// changed or removed, it returns ErrAlreadyExists if the id is taken.
+	AppendAuditEvent(event *pb.AuditEvent) error
+
+	// ListAuditEvents returns up to limit events whose id sorts after after,
+	// ordered by id
+	ListAuditEvents(after string, limit int) ([]*pb.AuditEvent, error)
+
+	// Close releases the store's resources
+	Close() error
+}
+
+// indexedEmail is the email user holds in the email index. Only a verified
+// address is reserved, otherwise anyone could squat someone else's.
+func indexedEmail(user *pb.PrivateUser) string {
+	if !user.EmailVerified {
+		return ""
+	}
+	return user.Email
+}
+
+// memberKey identifies a group member in indexes, ex: user:eric, group:eng
+func memberKey(member *pb.Member) string {
+	if member.Group != "" {
+		return "group:" + member.Group
+	}
+	return "user:" + member.Username
+}
+
+// parseMemberKey is the inverse of memberKey
+func parseMemberKey(key string) *pb.Member {
+	if strings.HasPrefix(key, "group:") {
+		return &pb.Member{Group: strings.TrimPrefix(key, "group:")}
+	}
+	return &pb.Member{Username: strings.TrimPrefix(key, "user:")}
+}
+
+// sortMembers sorts users first, then groups, each by name
+func sortMembers(members []*pb.Member) {
+	sort.Slice(members, func(i, j int) bool {
+		a, b := members[i], members[j]
+		if (a.Group == "") != (b.Group == "") {
+			return a.Group == ""
+		}
+		return a.Username+a.Group < b.Username+b.Group
+	})
+}
@@ -0,0 +1,1091 @@
+package usersservice
+
+import (
+	"strings"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/golang/protobuf/proto"
+	"github.com/syndtr/goleveldb/leveldb"
+	"github.com/syndtr/goleveldb/leveldb/util"
+)
+
+// LevelDBStore is a Store backed by a LevelDB directory.
+//
+// Keys:
+//
+//	users/<username>                    PrivateUser
+//	sessions/<token>                    PrivateSession
+//	user_sessions/<username>/<token>    empty, indexes sessions by user
+//	emails/<email>                      username, indexes users by verified email
+//	user_ids/<id>                       username, indexes users by id
+//	webauthn_credentials/<id>           username, indexes users by WebAuthn credential id
+//	renamed_users/<username>            PrivateRenamedUser
+//	groups/<name>                       PrivateGroup
+//	group_members/<group>/<member key>  empty, indexes members by group
+//	member_groups/<member key>/<group>  empty, indexes groups by member
+//	reset_tokens/<token hash>           PrivateResetToken
+//	verification_tokens/<token hash>    PrivateVerificationToken
+//	login_challenges/<challenge hash>   PrivateLoginChallenge
+//	audit_events/<id>                   AuditEvent, ids sort by time
+type LevelDBStore struct {
+	DB *leveldb.DB
+}
+
+// NewLevelDBStore opens or creates the LevelDB database at path
+func NewLevelDBStore(path string) (*LevelDBStore, error) {
+	db, err := leveldb.OpenFile(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &LevelDBStore{DB: db}, nil
+}
+
+func (s *LevelDBStore) Close() error {
+	return s.DB.Close()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Users
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *LevelDBStore) GetUser(username string) (*pb.PrivateUser, error) {
+	user := &pb.PrivateUser{}
+	if err := getProto(s.DB, userKey(username), user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *LevelDBStore) GetUserByEmail(email string) (*pb.PrivateUser, error) {
+	username, err := s.DB.Get(emailKey(email), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	user, err := s.GetUser(string(username))
+	if err != nil {
+		return nil, err
+	}
+	// Older databases indexed unverified emails too
+	if indexedEmail(user) != email {
+		return nil, ErrNotFound
+	}
+	return user, nil
+}
+
+func (s *LevelDBStore) GetUserByID(id string) (*pb.PrivateUser, error) {
+	username, err := s.DB.Get(userIDKey(id), nil)
+	if err == leveldb.ErrNotFound {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return s.GetUser(string(username))
+}
+
+// CreateUser checks and writes the user inside a transaction, which blocks
+// every other write, so two concurrent registrations of the same username
+// can not both succeed
+func (s *LevelDBStore) CreateUser(user *pb.PrivateUser) error {
+	bytes, err := proto.Marshal(user)
+	if err != nil {
+		return err
+	}
+
+	tr, err := s.DB.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	defer tr.Discard()
+
+	exists, err := tr.Has(userKey(user.Username), nil)
+	if err != nil {
+		return err
+	}
+	if exists == true {
+		return ErrAlreadyExists
+	}
+	if err := checkReserved(tr, user.Username, user.Id, user.CreatedAt); err != nil {
+		return err
+	}
+	if err := checkEmailFree(tr, user.Username, user.Email); err != nil {
+		return err
+	}
+	if err := claimEmail(tr, user.Username, "", indexedEmail(user)); err != nil {
+		return err
+	}
+	if err := claimCredentials(tr, user.Username, nil, user.WebauthnCredentials); err != nil {
+		return err
+	}
+
+	// Store the user into the db
+	if err := tr.Put(userKey(user.Username), bytes, nil); err != nil {
+		return err
+	}
+	if user.Id != "" {
+		if err := tr.Put(userIDKey(user.Id), []byte(user.Username), nil); err != nil {
+			return err
+		}
+	}
+	return tr.Commit()
+}
+
+func (s *LevelDBStore) UpdateUser(username string, fn func(user *pb.PrivateUser) error) error {
+	tr, err := s.DB.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	defer tr.Discard()
+
+	data, err := tr.Get(userKey(username), nil)
+	if err == leveldb.ErrNotFound {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	user := &pb.PrivateUser{}
+	if err := proto.Unmarshal(data, user); err != nil {
+		return err
+	}
+	oldEmail, oldID := indexedEmail(user), user.Id
+	oldCredentials := user.WebauthnCredentials
+
+	if err := fn(user); err != nil {
+		return err
+	}
+	if err := claimEmail(tr, username, oldEmail, indexedEmail(user)); err != nil {
+		return err
+	}
+	if err := claimCredentials(tr, username, oldCredentials, user.WebauthnCredentials); err != nil {
+		return err
+	}
+	// Records older than ids are given one when they are next updated
+	if user.Id != oldID && user.Id != "" {
+		if err := tr.Put(userIDKey(user.Id), []byte(username), nil); err != nil {
+			return err
+		}
+	}
+
+	data, err = proto.Marshal(user)
+	if err != nil {
+		return err
+	}
+	if err := tr.Put(userKey(username), data, nil); err != nil {
+		return err
+	}
+	return tr.Commit()
+}
+
+func (s *LevelDBStore) ForEachUser(fn func(user *pb.PrivateUser) error) error {
+	iter := s.DB.NewIterator(util.BytesPrefix(userKey("")), nil)
+	defer iter.Release()
+	for iter.Next() {
+		user := &pb.PrivateUser{}
+		if err := proto.Unmarshal(iter.Value(), user); err != nil {
+			return err
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+func (s *LevelDBStore) ListUsers(prefix, after string, limit int) ([]*pb.PrivateUser, error) {
+	users := []*pb.PrivateUser{}
+	iter := s.DB.NewIterator(util.BytesPrefix(userKey(prefix)), nil)
+	defer iter.Release()
+
+	// Start at the first key after the previous page
+	ok := iter.First()
+	if after != "" {
+		ok = iter.Seek(userKey(after))
+		if ok && string(iter.Key()) == string(userKey(after)) {
+			ok = iter.Next()
+		}
+	}
+	for ; ok && len(users) < limit; ok = iter.Next() {
+		user := &pb.PrivateUser{}
+		if err := proto.Unmarshal(iter.Value(), user); err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, iter.Error()
+}
+
+func (s *LevelDBStore) DeleteUser(username string) error {
+	tr, err := s.DB.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	defer tr.Discard()
+
+	data, err := tr.Get(userKey(username), nil)
+	if err == leveldb.ErrNotFound {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	user := &pb.PrivateUser{}
+	if err := proto.Unmarshal(data, user); err != nil {
+		return err
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Delete(userKey(username))
+	if email := indexedEmail(user); email != "" {
+		batch.Delete(emailKey(email))
+	}
+	if user.Id != "" {
+		batch.Delete(userIDKey(user.Id))
+	}
+	for _, credential := range user.WebauthnCredentials {
+		batch.Delete(credentialKey(credential.Id))
+	}
+
+	// Sessions and group memberships through their indexes
+	member := memberKey(&pb.Member{Username: username})
+	err = scanKeys(tr, userSessionKey(username, ""), func(token string, value []byte) error {
+		deleteSessionKeys(batch, username, token)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = scanKeys(tr, memberGroupKey(member, ""), func(group string, value []byte) error {
+		batch.Delete(memberGroupKey(member, group))
+		batch.Delete(groupMemberKey(group, member))
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Tokens are not indexed by user, they are few and short lived
+	err = scanKeys(tr, resetTokenKey(""), func(hash string, value []byte) error {
+		token := &pb.PrivateResetToken{}
+		if err := proto.Unmarshal(value, token); err != nil {
+			return err
+		}
+		if token.Username == username {
+			batch.Delete(resetTokenKey(hash))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	err = scanKeys(tr, verificationTokenKey(""), func(hash string, value []byte) error {
+		token := &pb.PrivateVerificationToken{}
+		if err := proto.Unmarshal(value, token); err != nil {
+			return err
+		}
+		if token.Username == username {
+			batch.Delete(verificationTokenKey(hash))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tr.Write(batch, nil); err != nil {
+		return err
+	}
+	return tr.Commit()
+}
+
+func (s *LevelDBStore) RenameUser(rename *pb.PrivateRenamedUser) error {
+	oldName, newName := rename.Username, rename.RenamedTo
+
+	tr, err := s.DB.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	defer tr.Discard()
+
+	data, err := tr.Get(userKey(oldName), nil)
+	if err == leveldb.ErrNotFound {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	user := &pb.PrivateUser{}
+	if err := proto.Unmarshal(data, user); err != nil {
+		return err
+	}
+	exists, err := tr.Has(userKey(newName), nil)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrAlreadyExists
+	}
+	if err := checkReserved(tr, newName, user.Id, rename.RenamedAt); err != nil {
+		return err
+	}
+	rename.UserId = user.Id
+
+	batch := new(leveldb.Batch)
+
+	// The user and its indexes
+	user.Username = newName
+	if err := putProto(batch, userKey(newName), user); err != nil {
+		return err
+	}
+	batch.Delete(userKey(oldName))
+	if email := indexedEmail(user); email != "" {
+		batch.Put(emailKey(email), []byte(newName))
+	}
+	if user.Id != "" {
+		batch.Put(userIDKey(user.Id), []byte(newName))
+	}
+	for _, credential := range user.WebauthnCredentials {
+		batch.Put(credentialKey(credential.Id), []byte(newName))
+	}
+	if err := putProto(batch, renamedUserKey(oldName), rename); err != nil {
+		return err
+	}
+	// Taking back an old name ends its reservation
+	batch.Delete(renamedUserKey(newName))
+
+	// Sessions and group memberships
+	err = scanKeys(tr, userSessionKey(oldName, ""), func(token string, value []byte) error {
+		session := &pb.PrivateSession{}
+		data, err := tr.Get(sessionKey(token), nil)
+		if err == leveldb.ErrNotFound {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		if err := proto.Unmarshal(data, session); err != nil {
+			return err
+		}
+		deleteSessionKeys(batch, oldName, token)
+		session.Username = newName
+		batch.Put(userSessionKey(newName, token), nil)
+		return putProto(batch, sessionKey(token), session)
+	})
+	if err != nil {
+		return err
+	}
+	oldMember := memberKey(&pb.Member{Username: oldName})
+	newMember := memberKey(&pb.Member{Username: newName})
+	err = scanKeys(tr, memberGroupKey(oldMember, ""), func(group string, value []byte) error {
+		batch.Delete(memberGroupKey(oldMember, group))
+		batch.Delete(groupMemberKey(group, oldMember))
+		batch.Put(memberGroupKey(newMember, group), nil)
+		batch.Put(groupMemberKey(group, newMember), nil)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Outstanding tokens
+	err = scanKeys(tr, resetTokenKey(""), func(hash string, value []byte) error {
+		token := &pb.PrivateResetToken{}
+		if err := proto.Unmarshal(value, token); err != nil {
+			return err
+		}
+		if token.Username != oldName {
+			return nil
+		}
+		token.Username = newName
+		return putProto(batch, resetTokenKey(hash), token)
+	})
+	if err != nil {
+		return err
+	}
+	err = scanKeys(tr, verificationTokenKey(""), func(hash string, value []byte) error {
+		token := &pb.PrivateVerificationToken{}
+		if err := proto.Unmarshal(value, token); err != nil {
+			return err
+		}
+		if token.Username != oldName {
+			return nil
+		}
+		token.Username = newName
+		return putProto(batch, verificationTokenKey(hash), token)
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := tr.Write(batch, nil); err != nil {
+		return err
+	}
+	return tr.Commit()
+}
+
+func (s *LevelDBStore) GetRenamedUser(username string) (*pb.PrivateRenamedUser, error) {
+	rename := &pb.PrivateRenamedUser{}
+	if err := getProto(s.DB, renamedUserKey(username), rename); err != nil {
+		return nil, err
+	}
+	return rename, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Groups
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *LevelDBStore) GetGroup(name string) (*pb.PrivateGroup, error) {
+	group := &pb.PrivateGroup{}
+	if err := getProto(s.DB, groupKey(name), group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (s *LevelDBStore) CreateGroup(group *pb.PrivateGroup) error {
+	bytes, err := proto.Marshal(group)
+	if err != nil {
+		return err
+	}
+
+	tr, err := s.DB.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	defer tr.Discard()
+
+	exists, err := tr.Has(groupKey(group.Name), nil)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return ErrAlreadyExists
+	}
+	if err := tr.Put(groupKey(group.Name), bytes, nil); err != nil {
+		return err
+	}
+	return tr.Commit()
+}
+
+func (s *LevelDBStore) UpdateGroup(name string, fn func(group *pb.PrivateGroup) error) error {
+	tr, err := s.DB.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	defer tr.Discard()
+
+	data, err := tr.Get(groupKey(name), nil)
+	if err == leveldb.ErrNotFound {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	group := &pb.PrivateGroup{}
+	if err := proto.Unmarshal(data, group); err != nil {
+		return err
+	}
+
+	if err := fn(group); err != nil {
+		return err
+	}
+
+	data, err = proto.Marshal(group)
+	if err != nil {
+		return err
+	}
+	if err := tr.Put(groupKey(name), data, nil); err != nil {
+		return err
+	}
+	return tr.Commit()
+}
+
+func (s *LevelDBStore) AddGroupMember(group string, member *pb.Member) error {
+	exists, err := s.DB.Has(groupKey(group), nil)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put(groupMemberKey(group, memberKey(member)), nil)
+	batch.Put(memberGroupKey(memberKey(member), group), nil)
+	return s.DB.Write(batch, nil)
+}
+
+func (s *LevelDBStore) RemoveGroupMember(group string, member *pb.Member) error {
+	exists, err := s.DB.Has(groupMemberKey(group, memberKey(member)), nil)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrNotFound
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Delete(groupMemberKey(group, memberKey(member)))
+	batch.Delete(memberGroupKey(memberKey(member), group))
+	return s.DB.Write(batch, nil)
+}
+
+func (s *LevelDBStore) GroupMembers(group string) ([]*pb.Member, error) {
+	exists, err := s.DB.Has(groupKey(group), nil)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrNotFound
+	}
+
+	members := []*pb.Member{}
+	prefix := groupMemberKey(group, "")
+	iter := s.DB.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		members = append(members, parseMemberKey(string(iter.Key()[len(prefix):])))
+	}
+	if err := iter.Error(); err != nil {
+		return nil, err
+	}
+	sortMembers(members)
+	return members, nil
+}
+
+func (s *LevelDBStore) MemberGroups(member *pb.Member) ([]string, error) {
+	groups := []string{}
+	prefix := memberGroupKey(memberKey(member), "")
+	iter := s.DB.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		group := string(iter.Key()[len(prefix):])
+		// Skip the groups of a user whose name has this username as a prefix, ex: "eric/x"
+		if strings.Contains(group, "/") {
+			continue
+		}
+		groups = append(groups, group)
+	}
+	return groups, iter.Error()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Sessions
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *LevelDBStore) GetSession(token string) (*pb.PrivateSession, error) {
+	session := &pb.PrivateSession{}
+	if err := getProto(s.DB, sessionKey(token), session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// PutSession stores session and indexes it under its user
+func (s *LevelDBStore) PutSession(session *pb.PrivateSession) error {
+	bytes, err := proto.Marshal(session)
+	if err != nil {
+		return err
+	}
+
+	batch := new(leveldb.Batch)
+	batch.Put(sessionKey(session.Token), bytes)
+	batch.Put(userSessionKey(session.Username, session.Token), nil)
+	return s.DB.Write(batch, nil)
+}
+
+func (s *LevelDBStore) RenewSession(session *pb.PrivateSession) error {
+	tr, err := s.DB.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	defer tr.Discard()
+
+	bytes, err := tr.Get(sessionKey(session.Token), nil)
+	if err == leveldb.ErrNotFound {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	stored := &pb.PrivateSession{}
+	if err := proto.Unmarshal(bytes, stored); err != nil {
+		return err
+	}
+	stored.LastSeenAt = session.LastSeenAt
+	stored.ExpiresAt = session.ExpiresAt
+	if bytes, err = proto.Marshal(stored); err != nil {
+		return err
+	}
+	if err := tr.Put(sessionKey(session.Token), bytes, nil); err != nil {
+		return err
+	}
+	return tr.Commit()
+}
+
+func (s *LevelDBStore) DeleteSession(session *pb.PrivateSession) error {
+	batch := new(leveldb.Batch)
+	deleteSessionKeys(batch, session.Username, session.Token)
+	return s.DB.Write(batch, nil)
+}
+
+func (s *LevelDBStore) UserSessions(username string) ([]*pb.PrivateSession, error) {
+	tokens, err := s.userSessionTokens(username)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions := []*pb.PrivateSession{}
+	for _, token := range tokens {
+		session, err := s.GetSession(token)
+		if err == ErrNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, nil
+}
+
+func (s *LevelDBStore) DeleteUserSessions(username string) (int, error) {
+	tokens, err := s.userSessionTokens(username)
+	if err != nil {
+		return 0, err
+	}
+
+	batch := new(leveldb.Batch)
+	for _, token := range tokens {
+		deleteSessionKeys(batch, username, token)
+	}
+	return len(tokens), s.DB.Write(batch, nil)
+}
+
+func (s *LevelDBStore) DeleteExpiredSessions(now int64) (int, error) {
+	batch := new(leveldb.Batch)
+	count := 0
+
+	iter := s.DB.NewIterator(util.BytesPrefix(sessionKey("")), nil)
+	for iter.Next() {
+		session := &pb.PrivateSession{}
+		if err := proto.Unmarshal(iter.Value(), session); err != nil {
+			iter.Release()
+			return 0, err
+		}
+		if sessionExpired(session, now) {
+			deleteSessionKeys(batch, session.Username, session.Token)
+			count++
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+	return count, s.DB.Write(batch, nil)
+}
+
+// userSessionTokens lists the tokens of every session of username using the
+// user_sessions/ index
+func (s *LevelDBStore) userSessionTokens(username string) ([]string, error) {
+	prefix := userSessionKey(username, "")
+	tokens := []string{}
+
+	iter := s.DB.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		token := string(iter.Key()[len(prefix):])
+		// Skip the sessions of a user whose name has this username as a prefix, ex: "eric/x"
+		if strings.Contains(token, "/") {
+			continue
+		}
+		tokens = append(tokens, token)
+	}
+	return tokens, iter.Error()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Password reset tokens
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *LevelDBStore) PutResetToken(token *pb.PrivateResetToken) error {
+	bytes, err := proto.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return s.DB.Put(resetTokenKey(token.TokenHash), bytes, nil)
+}
+
+// TakeResetToken reads and deletes the token in a transaction so two
+// concurrent resets can not both use it
+func (s *LevelDBStore) TakeResetToken(tokenHash string) (*pb.PrivateResetToken, error) {
+	token := &pb.PrivateResetToken{}
+	if err := s.takeProto(resetTokenKey(tokenHash), token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *LevelDBStore) DeleteExpiredResetTokens(now int64) (int, error) {
+	return s.deleteExpired(resetTokenKey(""), now, func(value []byte) (int64, error) {
+		token := &pb.PrivateResetToken{}
+		err := proto.Unmarshal(value, token)
+		return token.ExpiresAt, err
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Email verification tokens
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *LevelDBStore) PutVerificationToken(token *pb.PrivateVerificationToken) error {
+	bytes, err := proto.Marshal(token)
+	if err != nil {
+		return err
+	}
+	return s.DB.Put(verificationTokenKey(token.TokenHash), bytes, nil)
+}
+
+func (s *LevelDBStore) TakeVerificationToken(tokenHash string) (*pb.PrivateVerificationToken, error) {
+	token := &pb.PrivateVerificationToken{}
+	if err := s.takeProto(verificationTokenKey(tokenHash), token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+func (s *LevelDBStore) DeleteExpiredVerificationTokens(now int64) (int, error) {
+	return s.deleteExpired(verificationTokenKey(""), now, func(value []byte) (int64, error) {
+		token := &pb.PrivateVerificationToken{}
+		err := proto.Unmarshal(value, token)
+		return token.ExpiresAt, err
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Second factor login challenges
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *LevelDBStore) PutLoginChallenge(challenge *pb.PrivateLoginChallenge) error {
+	bytes, err := proto.Marshal(challenge)
+	if err != nil {
+		return err
+	}
+	return s.DB.Put(loginChallengeKey(challenge.ChallengeHash), bytes, nil)
+}
+
+func (s *LevelDBStore) TakeLoginChallenge(challengeHash string) (*pb.PrivateLoginChallenge, error) {
+	challenge := &pb.PrivateLoginChallenge{}
+	if err := s.takeProto(loginChallengeKey(challengeHash), challenge); err != nil {
+		return nil, err
+	}
+	return challenge, nil
+}
+
+func (s *LevelDBStore) DeleteExpiredLoginChallenges(now int64) (int, error) {
+	return s.deleteExpired(loginChallengeKey(""), now, func(value []byte) (int64, error) {
+		challenge := &pb.PrivateLoginChallenge{}
+		err := proto.Unmarshal(value, challenge)
+		return challenge.ExpiresAt, err
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Audit events
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *LevelDBStore) AppendAuditEvent(event *pb.AuditEvent) error {
+	bytes, err := proto.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	tr, err := s.DB.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	defer tr.Discard()
+	if exists, err := tr.Has(auditEventKey(event.Id), nil); err != nil {
+		return err
+	} else if exists {
+		return ErrAlreadyExists
+	}
+	if err := tr.Put(auditEventKey(event.Id), bytes, nil); err != nil {
+		return err
+	}
+	return tr.Commit()
+}
+
+func (s *LevelDBStore) ListAuditEvents(after string, limit int) ([]*pb.AuditEvent, error) {
+	events := []*pb.AuditEvent{}
+	iter := s.DB.NewIterator(util.BytesPrefix(auditEventKey("")), nil)
+	defer iter.Release()
+
+	ok := iter.First()
+	if after != "" {
+		ok = iter.Seek(auditEventKey(after))
+		if ok && string(iter.Key()) == string(auditEventKey(after)) {
+			ok = iter.Next()
+		}
+	}
+	for ; ok && len(events) < limit; ok = iter.Next() {
+		event := &pb.AuditEvent{}
+		if err := proto.Unmarshal(iter.Value(), event); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, iter.Error()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// getProto reads and unmarshals key, returning ErrNotFound if it is missing
+func getProto(db *leveldb.DB, key []byte, msg proto.Message) error {
+	bytes, err := db.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	return proto.Unmarshal(bytes, msg)
+}
+
+// takeProto reads, unmarshals and deletes key in a transaction, returning
+// ErrNotFound if it is missing
+func (s *LevelDBStore) takeProto(key []byte, msg proto.Message) error {
+	tr, err := s.DB.OpenTransaction()
+	if err != nil {
+		return err
+	}
+	defer tr.Discard()
+
+	data, err := tr.Get(key, nil)
+	if err == leveldb.ErrNotFound {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+	if err := proto.Unmarshal(data, msg); err != nil {
+		return err
+	}
+
+	if err := tr.Delete(key, nil); err != nil {
+		return err
+	}
+	return tr.Commit()
+}
+
+// deleteExpired deletes every key under prefix whose value expires at or
+// before now, expiresAt decodes the expiry of a value
+func (s *LevelDBStore) deleteExpired(prefix []byte, now int64, expiresAt func(value []byte) (int64, error)) (int, error) {
+	batch := new(leveldb.Batch)
+	count := 0
+
+	iter := s.DB.NewIterator(util.BytesPrefix(prefix), nil)
+	for iter.Next() {
+		expires, err := expiresAt(iter.Value())
+		if err != nil {
+			iter.Release()
+			return 0, err
+		}
+		if now >= expires {
+			batch.Delete(append([]byte{}, iter.Key()...))
+			count++
+		}
+	}
+	iter.Release()
+	if err := iter.Error(); err != nil {
+		return 0, err
+	}
+
+	if count == 0 {
+		return 0, nil
+	}
+	return count, s.DB.Write(batch, nil)
+}
+
+// putProto adds a put of msg at key to batch
+func putProto(batch *leveldb.Batch, key []byte, msg proto.Message) error {
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	batch.Put(key, data)
+	return nil
+}
+
+// checkReserved returns ErrUsernameReserved if username was given up by a
+// rename and is still reserved at now for a user other than userID
+func checkReserved(tr *leveldb.Transaction, username, userID string, now int64) error {
+	data, err := tr.Get(renamedUserKey(username), nil)
+	if err == leveldb.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	rename := &pb.PrivateRenamedUser{}
+	if err := proto.Unmarshal(data, rename); err != nil {
+		return err
+	}
+	if now < rename.ReservedUntil && (userID == "" || userID != rename.UserId) {
+		return ErrUsernameReserved
+	}
+	return nil
+}
+
+// scanKeys calls fn with the rest of the key and the value of every key under
+// prefix. Keys with a "/" after the prefix are skipped, they belong to a name
+// that has the prefix's name as a prefix, ex: "eric/x".
+func scanKeys(tr *leveldb.Transaction, prefix []byte, fn func(rest string, value []byte) error) error {
+	iter := tr.NewIterator(util.BytesPrefix(prefix), nil)
+	defer iter.Release()
+	for iter.Next() {
+		rest := string(iter.Key()[len(prefix):])
+		if strings.Contains(rest, "/") {
+			continue
+		}
+		if err := fn(rest, iter.Value()); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// claimEmail moves username's entry in the email index from oldEmail to
+// newEmail, returning ErrEmailInUse if someone else verified newEmail
+func claimEmail(tr *leveldb.Transaction, username, oldEmail, newEmail string) error {
+	if newEmail == oldEmail {
+		return nil
+	}
+	if newEmail != "" {
+		if err := checkEmailFree(tr, username, newEmail); err != nil {
+			return err
+		}
+		if err := tr.Put(emailKey(newEmail), []byte(username), nil); err != nil {
+			return err
+		}
+	}
+	if oldEmail != "" {
+		return tr.Delete(emailKey(oldEmail), nil)
+	}
+	return nil
+}
+
+// checkEmailFree returns ErrEmailInUse if someone other than username
+// verified email
+func checkEmailFree(tr *leveldb.Transaction, username, email string) error {
+	if email == "" {
+		return nil
+	}
+	owner, err := tr.Get(emailKey(email), nil)
+	if err == leveldb.ErrNotFound || (err == nil && string(owner) == username) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	// Older databases indexed unverified emails too, such an entry is taken
+	// over
+	data, err := tr.Get(userKey(string(owner)), nil)
+	if err != nil && err != leveldb.ErrNotFound {
+		return err
+	}
+	ownerUser := &pb.PrivateUser{}
+	if err := proto.Unmarshal(data, ownerUser); err != nil {
+		return err
+	}
+	if indexedEmail(ownerUser) == email {
+		return ErrEmailInUse
+	}
+	return nil
+}
+
+// claimCredentials moves username's entries in the WebAuthn credential index
+// from the credentials in old to those in new, returning ErrCredentialInUse
+// if another user has one of them
+func claimCredentials(tr *leveldb.Transaction, username string, old, new []*pb.PrivateWebAuthnCredential) error {
+	kept := map[string]bool{}
+	for _, credential := range new {
+		owner, err := tr.Get(credentialKey(credential.Id), nil)
+		if err == nil && string(owner) != username {
+			return ErrCredentialInUse
+		} else if err != nil && err != leveldb.ErrNotFound {
+			return err
+		}
+		if err := tr.Put(credentialKey(credential.Id), []byte(username), nil); err != nil {
+			return err
+		}
+		kept[string(credential.Id)] = true
+	}
+	for _, credential := range old {
+		if kept[string(credential.Id)] {
+			continue
+		}
+		if err := tr.Delete(credentialKey(credential.Id), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func deleteSessionKeys(batch *leveldb.Batch, username, token string) {
+	batch.Delete(sessionKey(token))
+	batch.Delete(userSessionKey(username, token))
+}
+
+func userKey(username string) []byte {
+	return []byte("users/" + username)
+}
+
+func userIDKey(id string) []byte {
+	return []byte("user_ids/" + id)
+}
+
+func credentialKey(id []byte) []byte {
+	return append([]byte("webauthn_credentials/"), id...)
+}
+
+func renamedUserKey(username string) []byte {
+	return []byte("renamed_users/" + username)
+}
+
+func sessionKey(token string) []byte {
+	return []byte("sessions/" + token)
+}
+
+func userSessionKey(username, token string) []byte {
+	return []byte("user_sessions/" + username + "/" + token)
+}
+
+func resetTokenKey(tokenHash string) []byte {
+	return []byte("reset_tokens/" + tokenHash)
+}
+
+func emailKey(email string) []byte {
+	return []byte("emails/" + email)
+}
+
+func verificationTokenKey(tokenHash string) []byte {
+	return []byte("verification_tokens/" + tokenHash)
+}
+
+func loginChallengeKey(challengeHash string) []byte {
+	return []byte("login_challenges/" + challengeHash)
+}
+
+func auditEventKey(id string) []byte {
+	return []byte("audit_events/" + id)
+}
+
+func groupKey(name string) []byte {
+	return []byte("groups/" + name)
+}
+
+func groupMemberKey(group, memberKey string) []byte {
+	return []byte("group_members/" + group + "/" + memberKey)
+}
+
+func memberGroupKey(memberKey, group string) []byte {
+	return []byte("member_groups/" + memberKey + "/" + group)
+}
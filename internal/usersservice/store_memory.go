@@ -0,0 +1,665 @@
+package usersservice
+
+import (
+	"sort"
+	"strings"
+	"sync"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/golang/protobuf/proto"
+)
+
+// MemoryStore is a Store that keeps everything in memory. It is meant for
+// tests and demos, everything is lost when the process exits.
+type MemoryStore struct {
+	mu            sync.RWMutex
+	users         map[string]*pb.PrivateUser
+	emails        map[string]string // verified email to username
+	ids           map[string]string // user id to username
+	credentials   map[string]string // WebAuthn credential id to username
+	renames       map[string]*pb.PrivateRenamedUser
+	sessions      map[string]*pb.PrivateSession
+	resets        map[string]*pb.PrivateResetToken
+	verifications map[string]*pb.PrivateVerificationToken
+	challenges    map[string]*pb.PrivateLoginChallenge
+	auditEvents   []*pb.AuditEvent // ordered by id
+	groups        map[string]*pb.PrivateGroup
+	members       map[string]map[string]bool // group to memberKey set
+}
+
+// NewMemoryStore creates an empty MemoryStore
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		users:         map[string]*pb.PrivateUser{},
+		emails:        map[string]string{},
+		ids:           map[string]string{},
+		credentials:   map[string]string{},
+		renames:       map[string]*pb.PrivateRenamedUser{},
+		sessions:      map[string]*pb.PrivateSession{},
+		resets:        map[string]*pb.PrivateResetToken{},
+		verifications: map[string]*pb.PrivateVerificationToken{},
+		challenges:    map[string]*pb.PrivateLoginChallenge{},
+		groups:        map[string]*pb.PrivateGroup{},
+		members:       map[string]map[string]bool{},
+	}
+}
+
+func (s *MemoryStore) Close() error {
+	return nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Users
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *MemoryStore) GetUser(username string) (*pb.PrivateUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[username]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneUser(user), nil
+}
+
+func (s *MemoryStore) GetUserByEmail(email string) (*pb.PrivateUser, error) {
+	s.mu.RLock()
+	username, ok := s.emails[email]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s.GetUser(username)
+}
+
+func (s *MemoryStore) GetUserByID(id string) (*pb.PrivateUser, error) {
+	s.mu.RLock()
+	username, ok := s.ids[id]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return s.GetUser(username)
+}
+
+func (s *MemoryStore) CreateUser(user *pb.PrivateUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.users[user.Username]; ok {
+		return ErrAlreadyExists
+	}
+	if s.reserved(user.Username, user.Id, user.CreatedAt) {
+		return ErrUsernameReserved
+	}
+	if _, ok := s.emails[user.Email]; ok && user.Email != "" {
+		return ErrEmailInUse
+	}
+	email := indexedEmail(user)
+	for _, credential := range user.WebauthnCredentials {
+		if _, ok := s.credentials[string(credential.Id)]; ok {
+			return ErrCredentialInUse
+		}
+	}
+	s.users[user.Username] = cloneUser(user)
+	if email != "" {
+		s.emails[email] = user.Username
+	}
+	if user.Id != "" {
+		s.ids[user.Id] = user.Username
+	}
+	for _, credential := range user.WebauthnCredentials {
+		s.credentials[string(credential.Id)] = user.Username
+	}
+	return nil
+}
+
+func (s *MemoryStore) UpdateUser(username string, fn func(user *pb.PrivateUser) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.users[username]
+	if !ok {
+		return ErrNotFound
+	}
+	user := cloneUser(current)
+	if err := fn(user); err != nil {
+		return err
+	}
+
+	for _, credential := range user.WebauthnCredentials {
+		if owner, ok := s.credentials[string(credential.Id)]; ok && owner != username {
+			return ErrCredentialInUse
+		}
+	}
+	if email, oldEmail := indexedEmail(user), indexedEmail(current); email != oldEmail {
+		if owner, ok := s.emails[email]; ok && email != "" && owner != username {
+			return ErrEmailInUse
+		}
+		if oldEmail != "" {
+			delete(s.emails, oldEmail)
+		}
+		if email != "" {
+			s.emails[email] = username
+		}
+	}
+	if user.Id != "" {
+		s.ids[user.Id] = username
+	}
+	for _, credential := range current.WebauthnCredentials {
+		delete(s.credentials, string(credential.Id))
+	}
+	for _, credential := range user.WebauthnCredentials {
+		s.credentials[string(credential.Id)] = username
+	}
+	s.users[username] = user
+	return nil
+}
+
+func (s *MemoryStore) ForEachUser(fn func(user *pb.PrivateUser) error) error {
+	s.mu.RLock()
+	usernames := make([]string, 0, len(s.users))
+	for username := range s.users {
+		usernames = append(usernames, username)
+	}
+	s.mu.RUnlock()
+	sort.Strings(usernames)
+
+	for _, username := range usernames {
+		user, err := s.GetUser(username)
+		if err == ErrNotFound {
+			continue
+		} else if err != nil {
+			return err
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) ListUsers(prefix, after string, limit int) ([]*pb.PrivateUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	usernames := []string{}
+	for username := range s.users {
+		if strings.HasPrefix(username, prefix) && username > after {
+			usernames = append(usernames, username)
+		}
+	}
+	sort.Strings(usernames)
+	if len(usernames) > limit {
+		usernames = usernames[:limit]
+	}
+
+	users := make([]*pb.PrivateUser, len(usernames))
+	for i, username := range usernames {
+		users[i] = cloneUser(s.users[username])
+	}
+	return users, nil
+}
+
+func (s *MemoryStore) DeleteUser(username string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	user, ok := s.users[username]
+	if !ok {
+		return ErrNotFound
+	}
+	delete(s.users, username)
+	if email := indexedEmail(user); email != "" {
+		delete(s.emails, email)
+	}
+	if user.Id != "" {
+		delete(s.ids, user.Id)
+	}
+	for _, credential := range user.WebauthnCredentials {
+		delete(s.credentials, string(credential.Id))
+	}
+
+	for token, session := range s.sessions {
+		if session.Username == username {
+			delete(s.sessions, token)
+		}
+	}
+	for hash, token := range s.resets {
+		if token.Username == username {
+			delete(s.resets, hash)
+		}
+	}
+	for hash, token := range s.verifications {
+		if token.Username == username {
+			delete(s.verifications, hash)
+		}
+	}
+	member := memberKey(&pb.Member{Username: username})
+	for _, members := range s.members {
+		delete(members, member)
+	}
+	return nil
+}
+
+func (s *MemoryStore) RenameUser(rename *pb.PrivateRenamedUser) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	oldName, newName := rename.Username, rename.RenamedTo
+	user, ok := s.users[oldName]
+	if !ok {
+		return ErrNotFound
+	}
+	if _, ok := s.users[newName]; ok {
+		return ErrAlreadyExists
+	}
+	if s.reserved(newName, user.Id, rename.RenamedAt) {
+		return ErrUsernameReserved
+	}
+	rename.UserId = user.Id
+
+	user = cloneUser(user)
+	user.Username = newName
+	delete(s.users, oldName)
+	s.users[newName] = user
+	if email := indexedEmail(user); email != "" {
+		s.emails[email] = newName
+	}
+	if user.Id != "" {
+		s.ids[user.Id] = newName
+	}
+	for _, credential := range user.WebauthnCredentials {
+		s.credentials[string(credential.Id)] = newName
+	}
+	s.renames[oldName] = proto.Clone(rename).(*pb.PrivateRenamedUser)
+	delete(s.renames, newName)
+
+	for token, session := range s.sessions {
+		if session.Username == oldName {
+			session = cloneSession(session)
+			session.Username = newName
+			s.sessions[token] = session
+		}
+	}
+	for _, token := range s.resets {
+		if token.Username == oldName {
+			token.Username = newName
+		}
+	}
+	for _, token := range s.verifications {
+		if token.Username == oldName {
+			token.Username = newName
+		}
+	}
+	oldMember := memberKey(&pb.Member{Username: oldName})
+	newMember := memberKey(&pb.Member{Username: newName})
+	for _, members := range s.members {
+		if members[oldMember] {
+			delete(members, oldMember)
+			members[newMember] = true
+		}
+	}
+	return nil
+}
+
+func (s *MemoryStore) GetRenamedUser(username string) (*pb.PrivateRenamedUser, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rename, ok := s.renames[username]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return proto.Clone(rename).(*pb.PrivateRenamedUser), nil
+}
+
+// reserved reports whether username is reserved at now for a user other than
+// userID. s.mu must be held.
+func (s *MemoryStore) reserved(username, userID string, now int64) bool {
+	rename, ok := s.renames[username]
+	return ok && now < rename.ReservedUntil && (userID == "" || userID != rename.UserId)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Groups
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *MemoryStore) GetGroup(name string) (*pb.PrivateGroup, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	group, ok := s.groups[name]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneGroup(group), nil
+}
+
+func (s *MemoryStore) CreateGroup(group *pb.PrivateGroup) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.groups[group.Name]; ok {
+		return ErrAlreadyExists
+	}
+	s.groups[group.Name] = cloneGroup(group)
+	s.members[group.Name] = map[string]bool{}
+	return nil
+}
+
+func (s *MemoryStore) UpdateGroup(name string, fn func(group *pb.PrivateGroup) error) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current, ok := s.groups[name]
+	if !ok {
+		return ErrNotFound
+	}
+	group := cloneGroup(current)
+	if err := fn(group); err != nil {
+		return err
+	}
+	s.groups[name] = group
+	return nil
+}
+
+func (s *MemoryStore) AddGroupMember(group string, member *pb.Member) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	members, ok := s.members[group]
+	if !ok {
+		return ErrNotFound
+	}
+	members[memberKey(member)] = true
+	return nil
+}
+
+func (s *MemoryStore) RemoveGroupMember(group string, member *pb.Member) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := memberKey(member)
+	if !s.members[group][key] {
+		return ErrNotFound
+	}
+	delete(s.members[group], key)
+	return nil
+}
+
+func (s *MemoryStore) GroupMembers(group string) ([]*pb.Member, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	members, ok := s.members[group]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	list := []*pb.Member{}
+	for key := range members {
+		list = append(list, parseMemberKey(key))
+	}
+	sortMembers(list)
+	return list, nil
+}
+
+func (s *MemoryStore) MemberGroups(member *pb.Member) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	key := memberKey(member)
+	groups := []string{}
+	for group, members := range s.members {
+		if members[key] {
+			groups = append(groups, group)
+		}
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Sessions
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *MemoryStore) GetSession(token string) (*pb.PrivateSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	session, ok := s.sessions[token]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return cloneSession(session), nil
+}
+
+func (s *MemoryStore) PutSession(session *pb.PrivateSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sessions[session.Token] = cloneSession(session)
+	return nil
+}
+
+func (s *MemoryStore) RenewSession(session *pb.PrivateSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.sessions[session.Token]
+	if !ok {
+		return ErrNotFound
+	}
+	stored.LastSeenAt = session.LastSeenAt
+	stored.ExpiresAt = session.ExpiresAt
+	return nil
+}
+
+func (s *MemoryStore) DeleteSession(session *pb.PrivateSession) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.sessions, session.Token)
+	return nil
+}
+
+func (s *MemoryStore) UserSessions(username string) ([]*pb.PrivateSession, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	sessions := []*pb.PrivateSession{}
+	for _, session := range s.sessions {
+		if session.Username == username {
+			sessions = append(sessions, cloneSession(session))
+		}
+	}
+	return sessions, nil
+}
+
+func (s *MemoryStore) DeleteUserSessions(username string) (int, error) {
+	return s.deleteSessions(func(session *pb.PrivateSession) bool {
+		return session.Username == username
+	})
+}
+
+func (s *MemoryStore) DeleteExpiredSessions(now int64) (int, error) {
+	return s.deleteSessions(func(session *pb.PrivateSession) bool {
+		return sessionExpired(session, now)
+	})
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Password reset tokens
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *MemoryStore) PutResetToken(token *pb.PrivateResetToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.resets[token.TokenHash] = proto.Clone(token).(*pb.PrivateResetToken)
+	return nil
+}
+
+func (s *MemoryStore) TakeResetToken(tokenHash string) (*pb.PrivateResetToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.resets[tokenHash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	delete(s.resets, tokenHash)
+	return token, nil
+}
+
+func (s *MemoryStore) DeleteExpiredResetTokens(now int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for tokenHash, token := range s.resets {
+		if now >= token.ExpiresAt {
+			delete(s.resets, tokenHash)
+			count++
+		}
+	}
+	return count, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Email verification tokens
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *MemoryStore) PutVerificationToken(token *pb.PrivateVerificationToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.verifications[token.TokenHash] = proto.Clone(token).(*pb.PrivateVerificationToken)
+	return nil
+}
+
+func (s *MemoryStore) TakeVerificationToken(tokenHash string) (*pb.PrivateVerificationToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	token, ok := s.verifications[tokenHash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	delete(s.verifications, tokenHash)
+	return token, nil
+}
+
+func (s *MemoryStore) DeleteExpiredVerificationTokens(now int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for tokenHash, token := range s.verifications {
+		if now >= token.ExpiresAt {
+			delete(s.verifications, tokenHash)
+			count++
+		}
+	}
+	return count, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Second factor login challenges
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *MemoryStore) PutLoginChallenge(challenge *pb.PrivateLoginChallenge) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.challenges[challenge.ChallengeHash] = proto.Clone(challenge).(*pb.PrivateLoginChallenge)
+	return nil
+}
+
+func (s *MemoryStore) TakeLoginChallenge(challengeHash string) (*pb.PrivateLoginChallenge, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	challenge, ok := s.challenges[challengeHash]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	delete(s.challenges, challengeHash)
+	return challenge, nil
+}
+
+func (s *MemoryStore) DeleteExpiredLoginChallenges(now int64) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for challengeHash, challenge := range s.challenges {
+		if now >= challenge.ExpiresAt {
+			delete(s.challenges, challengeHash)
+			count++
+		}
+	}
+	return count, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Audit events
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *MemoryStore) AppendAuditEvent(event *pb.AuditEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Ids are mostly appended in order, but insert in place when they are not
+	i := sort.Search(len(s.auditEvents), func(i int) bool { return s.auditEvents[i].Id >= event.Id })
+	if i < len(s.auditEvents) && s.auditEvents[i].Id == event.Id {
+		return ErrAlreadyExists
+	}
+	s.auditEvents = append(s.auditEvents, nil)
+	copy(s.auditEvents[i+1:], s.auditEvents[i:])
+	s.auditEvents[i] = proto.Clone(event).(*pb.AuditEvent)
+	return nil
+}
+
+func (s *MemoryStore) ListAuditEvents(after string, limit int) ([]*pb.AuditEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	i := sort.Search(len(s.auditEvents), func(i int) bool { return s.auditEvents[i].Id > after })
+	events := []*pb.AuditEvent{}
+	for ; i < len(s.auditEvents) && len(events) < limit; i++ {
+		events = append(events, proto.Clone(s.auditEvents[i]).(*pb.AuditEvent))
+	}
+	return events, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *MemoryStore) deleteSessions(match func(session *pb.PrivateSession) bool) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	count := 0
+	for token, session := range s.sessions {
+		if match(session) {
+			delete(s.sessions, token)
+			count++
+		}
+	}
+	return count, nil
+}
+
+func cloneUser(user *pb.PrivateUser) *pb.PrivateUser {
+	return proto.Clone(user).(*pb.PrivateUser)
+}
+
+func cloneSession(session *pb.PrivateSession) *pb.PrivateSession {
+	return proto.Clone(session).(*pb.PrivateSession)
+}
+
+func cloneGroup(group *pb.PrivateGroup) *pb.PrivateGroup {
+	return proto.Clone(group).(*pb.PrivateGroup)
+}
@@ -0,0 +1,1190 @@
+package usersservice
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	// pure Go driver, registers "sqlite"
+	_ "modernc.org/sqlite"
+)
+
+// SQLiteStore is a Store backed by a SQLite database. Every field is stored
+// in its own column so the data can be inspected with standard SQL tools:
+//
+//	twirp-users -store sqlite -db ./users.sqlite
+//	sqlite3 ./users.sqlite 'SELECT username, client_ip FROM sessions'
+//
+// The schema is created and upgraded by the migrations below when the store
+// is opened.
+type SQLiteStore struct {
+	DB *sql.DB
+}
+
+// sqliteMigrations are applied in order, migration i brings the schema to
+// version i+1. Migrations are forward-only: never edit or remove an entry
+// that has shipped, append a new one instead.
+var sqliteMigrations = []string{
+	// 1: users and sessions
+	`CREATE TABLE users (
+		username        TEXT NOT NULL PRIMARY KEY,
+		password_sha256 BLOB,
+		password_hash   TEXT NOT NULL DEFAULT ''
+	);
+	CREATE TABLE sessions (
+		token        TEXT NOT NULL PRIMARY KEY,
+		username     TEXT NOT NULL REFERENCES users (username) ON UPDATE CASCADE ON DELETE CASCADE,
+		created_at   INTEGER NOT NULL,
+		last_seen_at INTEGER NOT NULL,
+		expires_at   INTEGER NOT NULL,
+		client_ip    TEXT NOT NULL DEFAULT '',
+		user_agent   TEXT NOT NULL DEFAULT ''
+	);
+	CREATE INDEX sessions_username ON sessions (username);
+	CREATE INDEX sessions_expires_at ON sessions (expires_at);`,
+
+	// 2: password reset tokens
+	`CREATE TABLE reset_tokens (
+		token_hash TEXT NOT NULL PRIMARY KEY,
+		username   TEXT NOT NULL REFERENCES users (username) ON UPDATE CASCADE ON DELETE CASCADE,
+		created_at INTEGER NOT NULL,
+		expires_at INTEGER NOT NULL
+	);
+	CREATE INDEX reset_tokens_expires_at ON reset_tokens (expires_at);`,
+
+	// 3: email and email verification tokens
+	`ALTER TABLE users ADD COLUMN email TEXT;
+	ALTER TABLE users ADD COLUMN email_verified INTEGER NOT NULL DEFAULT 0;
+	CREATE UNIQUE INDEX users_email ON users (email);
+	CREATE TABLE verification_tokens (
+		token_hash TEXT NOT NULL PRIMARY KEY,
+		username   TEXT NOT NULL REFERENCES users (username) ON UPDATE CASCADE ON DELETE CASCADE,
+		email      TEXT NOT NULL,
+		created_at INTEGER NOT NULL,
+		expires_at INTEGER NOT NULL
+	);
+	CREATE INDEX verification_tokens_expires_at ON verification_tokens (expires_at);`,
+
+	// 4: profiles
+	`ALTER TABLE users ADD COLUMN display_name TEXT NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN avatar_url TEXT NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN locale TEXT NOT NULL DEFAULT '';
+	ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+	CREATE TABLE user_attributes (
+		username TEXT NOT NULL REFERENCES users (username) ON UPDATE CASCADE ON DELETE CASCADE,
+		key      TEXT NOT NULL,
+		value    TEXT NOT NULL,
+		PRIMARY KEY (username, key)
+	);`,
+
+	// 5: roles
+	`CREATE TABLE user_roles (
+		username TEXT NOT NULL REFERENCES users (username) ON UPDATE CASCADE ON DELETE CASCADE,
+		role     TEXT NOT NULL,
+		resource TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (username, role, resource)
+	);
+	CREATE INDEX user_roles_role ON user_roles (role);`,
+
+	// 6: groups
+	`CREATE TABLE groups (
+		name        TEXT NOT NULL PRIMARY KEY,
+		description TEXT NOT NULL DEFAULT '',
+		created_at  INTEGER NOT NULL
+	);
+	CREATE TABLE group_roles (
+		group_name TEXT NOT NULL REFERENCES groups (name) ON UPDATE CASCADE ON DELETE CASCADE,
+		role       TEXT NOT NULL,
+		resource   TEXT NOT NULL DEFAULT '',
+		PRIMARY KEY (group_name, role, resource)
+	);
+	CREATE TABLE group_members (
+		group_name  TEXT NOT NULL REFERENCES groups (name) ON UPDATE CASCADE ON DELETE CASCADE,
+		member_kind TEXT NOT NULL CHECK (member_kind IN ('user', 'group')),
+		member_name TEXT NOT NULL,
+		PRIMARY KEY (group_name, member_kind, member_name)
+	);
+	CREATE INDEX group_members_member ON group_members (member_kind, member_name);`,
+
+	// 7: disabled accounts
+	`ALTER TABLE users ADD COLUMN disabled INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE users ADD COLUMN disabled_at INTEGER NOT NULL DEFAULT 0;`,
+
+	// 8: user ids and renames
+	`ALTER TABLE users ADD COLUMN id TEXT;
+	ALTER TABLE users ADD COLUMN created_at INTEGER NOT NULL DEFAULT 0;
+	CREATE UNIQUE INDEX users_id ON users (id);
+	ALTER TABLE sessions ADD COLUMN user_id TEXT NOT NULL DEFAULT '';
+	CREATE TABLE renamed_users (
+		username       TEXT NOT NULL PRIMARY KEY,
+		user_id        TEXT NOT NULL,
+		renamed_to     TEXT NOT NULL,
+		renamed_at     INTEGER NOT NULL,
+		reserved_until INTEGER NOT NULL
+	);`,
+
+	// 9: two-factor authentication
+	`ALTER TABLE users ADD COLUMN totp_secret BLOB;
+	ALTER TABLE users ADD COLUMN totp_enabled INTEGER NOT NULL DEFAULT 0;
+	ALTER TABLE users ADD COLUMN totp_last_step INTEGER NOT NULL DEFAULT 0;
+	CREATE TABLE user_recovery_codes (
+		username  TEXT NOT NULL REFERENCES users (username) ON UPDATE CASCADE ON DELETE CASCADE,
+		code_hash TEXT NOT NULL,
+		PRIMARY KEY (username, code_hash)
+	);
+	CREATE TABLE login_challenges (
+		challenge_hash TEXT NOT NULL PRIMARY KEY,
+		user_id        TEXT NOT NULL,
+		created_at     INTEGER NOT NULL,
+		expires_at     INTEGER NOT NULL,
+		failures       INTEGER NOT NULL DEFAULT 0
+	);
+	CREATE INDEX login_challenges_expires_at ON login_challenges (expires_at);`,
+
+	// 10: WebAuthn credentials
+	`CREATE TABLE user_webauthn_credentials (
+		username      TEXT NOT NULL REFERENCES users (username) ON UPDATE CASCADE ON DELETE CASCADE,
+		credential_id BLOB NOT NULL,
+		public_key    BLOB NOT NULL,
+		sign_count    INTEGER NOT NULL,
+		name          TEXT NOT NULL,
+		created_at    INTEGER NOT NULL,
+		last_used_at  INTEGER NOT NULL,
+		PRIMARY KEY (username, credential_id)
+	);
+	ALTER TABLE login_challenges ADD COLUMN kind TEXT NOT NULL DEFAULT '';`,
+
+	// 11: audit events
+	`CREATE TABLE audit_events (
+		id        TEXT NOT NULL PRIMARY KEY,
+		time      INTEGER NOT NULL,
+		action    TEXT NOT NULL,
+		actor     TEXT NOT NULL,
+		subject   TEXT NOT NULL,
+		client_ip TEXT NOT NULL,
+		outcome   TEXT NOT NULL,
+		detail    TEXT NOT NULL
+	);`,
+
+	// 12: only verified emails are unique, an unverified one reserves nothing
+	`DROP INDEX users_email;
+	CREATE UNIQUE INDEX users_email ON users (email) WHERE email_verified = 1;`,
+
+	// 13: a WebAuthn credential belongs to one user
+	`CREATE UNIQUE INDEX user_webauthn_credentials_id ON user_webauthn_credentials (credential_id);`,
+}
+
+// NewSQLiteStore opens or creates the SQLite database at path and migrates
+// it to the latest schema
+func NewSQLiteStore(path string) (*SQLiteStore, error) {
+	// The pragmas are in the DSN so every connection database/sql opens
+	// gets them, DeleteUser relies on foreign key cascades. The path is
+	// escaped so a ? or # in it is not read as the start of the query.
+	dsn := url.URL{
+		Scheme: "file",
+		Opaque: url.PathEscape(path),
+		RawQuery: url.Values{
+			"_pragma": {"foreign_keys(1)", "journal_mode(WAL)", "busy_timeout(5000)"},
+		}.Encode(),
+	}
+	db, err := sql.Open("sqlite", dsn.String())
+	if err != nil {
+		return nil, err
+	}
+	// A single connection serializes writers instead of failing with
+	// SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	store := &SQLiteStore{DB: db}
+	if err := store.migrate(); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return store, nil
+}
+
+// SchemaVersion returns the version of the last migration applied to the
+// database
+func (s *SQLiteStore) SchemaVersion() (int, error) {
+	var version int
+	err := s.DB.QueryRow(`SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version)
+	return version, err
+}
+
+func (s *SQLiteStore) Close() error {
+	return s.DB.Close()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Users
+///////////////////////////////////////////////////////////////////////////////
+
+// userColumns are the users columns, in the order of userValues and scanUser
+var userColumns = []string{
+	"username", "password_sha256", "password_hash", "email", "email_verified",
+	"display_name", "avatar_url", "locale", "timezone", "disabled", "disabled_at",
+	"id", "created_at", "totp_secret", "totp_enabled", "totp_last_step",
+}
+
+var (
+	selectUser = `SELECT ` + strings.Join(userColumns, ", ") + ` FROM users`
+	insertUser = `INSERT INTO users (` + strings.Join(userColumns, ", ") + `) VALUES (?` + strings.Repeat(", ?", len(userColumns)-1) + `)`
+	updateUser = `UPDATE users SET ` + strings.Join(userColumns[1:], " = ?, ") + ` = ? WHERE username = ?`
+)
+
+func (s *SQLiteStore) GetUser(username string) (*pb.PrivateUser, error) {
+	return readUser(s.DB, `WHERE username = ?`, username)
+}
+
+func (s *SQLiteStore) GetUserByEmail(email string) (*pb.PrivateUser, error) {
+	return readUser(s.DB, `WHERE email = ? AND email_verified = 1`, email)
+}
+
+func (s *SQLiteStore) GetUserByID(id string) (*pb.PrivateUser, error) {
+	return readUser(s.DB, `WHERE id = ?`, id)
+}
+
+func (s *SQLiteStore) CreateUser(user *pb.PrivateUser) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := checkReservedSQL(tx, user.Username, user.Id, user.CreatedAt); err != nil {
+		return err
+	}
+	// The unique index only covers verified emails, an unverified one must
+	// not be someone else's verified email either
+	if user.Email != "" && !user.EmailVerified {
+		var taken int
+		err := tx.QueryRow(`SELECT COUNT(*) FROM users WHERE email = ? AND email_verified = 1`, user.Email).Scan(&taken)
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			return ErrEmailInUse
+		}
+	}
+	if _, err := tx.Exec(insertUser, userValues(user)...); err != nil {
+		return userConstraintError(err)
+	}
+	if err := writeUserRelations(tx, user); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) UpdateUser(username string, fn func(user *pb.PrivateUser) error) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	user, err := readUser(tx, `WHERE username = ?`, username)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(user); err != nil {
+		return err
+	}
+
+	values := append(userValues(user)[1:], username)
+	if _, err := tx.Exec(updateUser, values...); err != nil {
+		return userConstraintError(err)
+	}
+	if err := writeUserRelations(tx, user); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) ForEachUser(fn func(user *pb.PrivateUser) error) error {
+	// Collect first, fn may call back into the store and there is only one
+	// connection
+	rows, err := s.DB.Query(selectUser + ` ORDER BY username`)
+	if err != nil {
+		return err
+	}
+	users := []*pb.PrivateUser{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			rows.Close()
+			return err
+		}
+		users = append(users, user)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, user := range users {
+		if err := readUserRelations(s.DB, user); err != nil {
+			return err
+		}
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *SQLiteStore) ListUsers(prefix, after string, limit int) ([]*pb.PrivateUser, error) {
+	// Usernames with the prefix sort together, starting at the prefix itself
+	rows, err := s.DB.Query(
+		selectUser+` WHERE username >= ? AND username > ? ORDER BY username LIMIT ?`,
+		prefix, after, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	users := []*pb.PrivateUser{}
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if !strings.HasPrefix(user.Username, prefix) {
+			break
+		}
+		users = append(users, user)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, user := range users {
+		if err := readUserRelations(s.DB, user); err != nil {
+			return nil, err
+		}
+	}
+	return users, nil
+}
+
+// DeleteUser relies on ON DELETE CASCADE for the rows that reference users,
+// group_members has no foreign key to users so it is cleaned up here
+func (s *SQLiteStore) DeleteUser(username string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	kind, name := memberColumns(&pb.Member{Username: username})
+	if _, err := tx.Exec(`DELETE FROM group_members WHERE member_kind = ? AND member_name = ?`, kind, name); err != nil {
+		return err
+	}
+	count, err := execCount(tx, `DELETE FROM users WHERE username = ?`, username)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return tx.Commit()
+}
+
+// RenameUser relies on ON UPDATE CASCADE to move the rows that reference
+// users, group_members has no foreign key to users so it is moved here
+func (s *SQLiteStore) RenameUser(rename *pb.PrivateRenamedUser) error {
+	oldName, newName := rename.Username, rename.RenamedTo
+
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	user, err := scanUser(tx.QueryRow(selectUser+` WHERE username = ?`, oldName))
+	if err != nil {
+		return err
+	}
+	if err := checkReservedSQL(tx, newName, user.Id, rename.RenamedAt); err != nil {
+		return err
+	}
+	rename.UserId = user.Id
+
+	if _, err := tx.Exec(`UPDATE users SET username = ? WHERE username = ?`, newName, oldName); err != nil {
+		return userConstraintError(err)
+	}
+	kind, _ := memberColumns(&pb.Member{Username: oldName})
+	_, err = tx.Exec(
+		`UPDATE group_members SET member_name = ? WHERE member_kind = ? AND member_name = ?`,
+		newName, kind, oldName,
+	)
+	if err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM renamed_users WHERE username = ?`, newName); err != nil {
+		return err
+	}
+	_, err = tx.Exec(
+		`INSERT OR REPLACE INTO renamed_users (username, user_id, renamed_to, renamed_at, reserved_until) VALUES (?, ?, ?, ?, ?)`,
+		rename.Username, rename.UserId, rename.RenamedTo, rename.RenamedAt, rename.ReservedUntil,
+	)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) GetRenamedUser(username string) (*pb.PrivateRenamedUser, error) {
+	return readRenamedUser(s.DB, username)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Groups
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *SQLiteStore) GetGroup(name string) (*pb.PrivateGroup, error) {
+	return readGroup(s.DB, name)
+}
+
+func (s *SQLiteStore) CreateGroup(group *pb.PrivateGroup) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(
+		`INSERT INTO groups (name, description, created_at) VALUES (?, ?, ?)`,
+		group.Name, group.Description, group.CreatedAt,
+	)
+	if isUniqueViolation(err) {
+		return ErrAlreadyExists
+	} else if err != nil {
+		return err
+	}
+	if err := writeGroupRoles(tx, group); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) UpdateGroup(name string, fn func(group *pb.PrivateGroup) error) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	group, err := readGroup(tx, name)
+	if err != nil {
+		return err
+	}
+
+	if err := fn(group); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`UPDATE groups SET description = ? WHERE name = ?`, group.Description, name); err != nil {
+		return err
+	}
+	if err := writeGroupRoles(tx, group); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *SQLiteStore) AddGroupMember(group string, member *pb.Member) error {
+	kind, name := memberColumns(member)
+	_, err := s.DB.Exec(
+		`INSERT OR IGNORE INTO group_members (group_name, member_kind, member_name) VALUES (?, ?, ?)`,
+		group, kind, name,
+	)
+	if err != nil && strings.Contains(err.Error(), "FOREIGN KEY constraint failed") {
+		return ErrNotFound
+	}
+	return err
+}
+
+func (s *SQLiteStore) RemoveGroupMember(group string, member *pb.Member) error {
+	kind, name := memberColumns(member)
+	count, err := execCount(s.DB,
+		`DELETE FROM group_members WHERE group_name = ? AND member_kind = ? AND member_name = ?`,
+		group, kind, name,
+	)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) GroupMembers(group string) ([]*pb.Member, error) {
+	if _, err := s.GetGroup(group); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.DB.Query(`SELECT member_kind, member_name FROM group_members WHERE group_name = ?`, group)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	members := []*pb.Member{}
+	for rows.Next() {
+		var kind, name string
+		if err := rows.Scan(&kind, &name); err != nil {
+			return nil, err
+		}
+		members = append(members, parseMemberKey(kind+":"+name))
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	sortMembers(members)
+	return members, nil
+}
+
+func (s *SQLiteStore) MemberGroups(member *pb.Member) ([]string, error) {
+	kind, name := memberColumns(member)
+	rows, err := s.DB.Query(
+		`SELECT group_name FROM group_members WHERE member_kind = ? AND member_name = ? ORDER BY group_name`,
+		kind, name,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	groups := []string{}
+	for rows.Next() {
+		var group string
+		if err := rows.Scan(&group); err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, rows.Err()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Sessions
+///////////////////////////////////////////////////////////////////////////////
+
+const sessionColumns = `token, username, created_at, last_seen_at, expires_at, client_ip, user_agent, user_id`
+
+func (s *SQLiteStore) GetSession(token string) (*pb.PrivateSession, error) {
+	row := s.DB.QueryRow(`SELECT `+sessionColumns+` FROM sessions WHERE token = ?`, token)
+	return scanSession(row)
+}
+
+func (s *SQLiteStore) PutSession(session *pb.PrivateSession) error {
+	_, err := s.DB.Exec(
+		`INSERT OR REPLACE INTO sessions (`+sessionColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		session.Token, session.Username, session.CreatedAt, session.LastSeenAt,
+		session.ExpiresAt, session.ClientIp, session.UserAgent, session.UserId,
+	)
+	return err
+}
+
+func (s *SQLiteStore) RenewSession(session *pb.PrivateSession) error {
+	count, err := execCount(s.DB,
+		`UPDATE sessions SET last_seen_at = ?, expires_at = ? WHERE token = ?`,
+		session.LastSeenAt, session.ExpiresAt, session.Token,
+	)
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (s *SQLiteStore) DeleteSession(session *pb.PrivateSession) error {
+	_, err := s.DB.Exec(`DELETE FROM sessions WHERE token = ?`, session.Token)
+	return err
+}
+
+func (s *SQLiteStore) UserSessions(username string) ([]*pb.PrivateSession, error) {
+	rows, err := s.DB.Query(`SELECT `+sessionColumns+` FROM sessions WHERE username = ?`, username)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	sessions := []*pb.PrivateSession{}
+	for rows.Next() {
+		session, err := scanSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+func (s *SQLiteStore) DeleteUserSessions(username string) (int, error) {
+	return execCount(s.DB, `DELETE FROM sessions WHERE username = ?`, username)
+}
+
+func (s *SQLiteStore) DeleteExpiredSessions(now int64) (int, error) {
+	return execCount(s.DB, `DELETE FROM sessions WHERE expires_at <= ?`, now)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Password reset tokens
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *SQLiteStore) PutResetToken(token *pb.PrivateResetToken) error {
+	_, err := s.DB.Exec(
+		`INSERT OR REPLACE INTO reset_tokens (token_hash, username, created_at, expires_at) VALUES (?, ?, ?, ?)`,
+		token.TokenHash, token.Username, token.CreatedAt, token.ExpiresAt,
+	)
+	return err
+}
+
+func (s *SQLiteStore) TakeResetToken(tokenHash string) (*pb.PrivateResetToken, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	token := &pb.PrivateResetToken{}
+	err = tx.QueryRow(
+		`SELECT token_hash, username, created_at, expires_at FROM reset_tokens WHERE token_hash = ?`, tokenHash,
+	).Scan(&token.TokenHash, &token.Username, &token.CreatedAt, &token.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM reset_tokens WHERE token_hash = ?`, tokenHash); err != nil {
+		return nil, err
+	}
+	return token, tx.Commit()
+}
+
+func (s *SQLiteStore) DeleteExpiredResetTokens(now int64) (int, error) {
+	return execCount(s.DB, `DELETE FROM reset_tokens WHERE expires_at <= ?`, now)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Email verification tokens
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *SQLiteStore) PutVerificationToken(token *pb.PrivateVerificationToken) error {
+	_, err := s.DB.Exec(
+		`INSERT OR REPLACE INTO verification_tokens (token_hash, username, email, created_at, expires_at) VALUES (?, ?, ?, ?, ?)`,
+		token.TokenHash, token.Username, token.Email, token.CreatedAt, token.ExpiresAt,
+	)
+	return err
+}
+
+func (s *SQLiteStore) TakeVerificationToken(tokenHash string) (*pb.PrivateVerificationToken, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	token := &pb.PrivateVerificationToken{}
+	err = tx.QueryRow(
+		`SELECT token_hash, username, email, created_at, expires_at FROM verification_tokens WHERE token_hash = ?`, tokenHash,
+	).Scan(&token.TokenHash, &token.Username, &token.Email, &token.CreatedAt, &token.ExpiresAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM verification_tokens WHERE token_hash = ?`, tokenHash); err != nil {
+		return nil, err
+	}
+	return token, tx.Commit()
+}
+
+func (s *SQLiteStore) DeleteExpiredVerificationTokens(now int64) (int, error) {
+	return execCount(s.DB, `DELETE FROM verification_tokens WHERE expires_at <= ?`, now)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Second factor login challenges
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *SQLiteStore) PutLoginChallenge(challenge *pb.PrivateLoginChallenge) error {
+	_, err := s.DB.Exec(
+		`INSERT OR REPLACE INTO login_challenges (challenge_hash, user_id, created_at, expires_at, failures, kind) VALUES (?, ?, ?, ?, ?, ?)`,
+		challenge.ChallengeHash, challenge.UserId, challenge.CreatedAt, challenge.ExpiresAt, challenge.Failures, challenge.Kind,
+	)
+	return err
+}
+
+func (s *SQLiteStore) TakeLoginChallenge(challengeHash string) (*pb.PrivateLoginChallenge, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	challenge := &pb.PrivateLoginChallenge{}
+	err = tx.QueryRow(
+		`SELECT challenge_hash, user_id, created_at, expires_at, failures, kind FROM login_challenges WHERE challenge_hash = ?`, challengeHash,
+	).Scan(&challenge.ChallengeHash, &challenge.UserId, &challenge.CreatedAt, &challenge.ExpiresAt, &challenge.Failures, &challenge.Kind)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	if _, err := tx.Exec(`DELETE FROM login_challenges WHERE challenge_hash = ?`, challengeHash); err != nil {
+		return nil, err
+	}
+	return challenge, tx.Commit()
+}
+
+func (s *SQLiteStore) DeleteExpiredLoginChallenges(now int64) (int, error) {
+	return execCount(s.DB, `DELETE FROM login_challenges WHERE expires_at <= ?`, now)
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Audit events
+///////////////////////////////////////////////////////////////////////////////
+
+func (s *SQLiteStore) AppendAuditEvent(event *pb.AuditEvent) error {
+	_, err := s.DB.Exec(
+		`INSERT INTO audit_events (id, time, action, actor, subject, client_ip, outcome, detail) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		event.Id, event.Time, event.Action, event.Actor, event.Subject, event.ClientIp, event.Outcome, event.Detail,
+	)
+	if isUniqueViolation(err) {
+		return ErrAlreadyExists
+	}
+	return err
+}
+
+func (s *SQLiteStore) ListAuditEvents(after string, limit int) ([]*pb.AuditEvent, error) {
+	rows, err := s.DB.Query(
+		`SELECT id, time, action, actor, subject, client_ip, outcome, detail FROM audit_events WHERE id > ? ORDER BY id LIMIT ?`,
+		after, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []*pb.AuditEvent{}
+	for rows.Next() {
+		event := &pb.AuditEvent{}
+		if err := rows.Scan(&event.Id, &event.Time, &event.Action, &event.Actor, &event.Subject, &event.ClientIp, &event.Outcome, &event.Detail); err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// migrate applies every migration newer than the database's schema version,
+// each in its own transaction
+func (s *SQLiteStore) migrate() error {
+	_, err := s.DB.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version    INTEGER NOT NULL PRIMARY KEY,
+		applied_at INTEGER NOT NULL DEFAULT (strftime('%s', 'now'))
+	)`)
+	if err != nil {
+		return err
+	}
+
+	version, err := s.SchemaVersion()
+	if err != nil {
+		return err
+	}
+	if version > len(sqliteMigrations) {
+		return fmt.Errorf("sqlite schema version %d is newer than this build supports (%d)", version, len(sqliteMigrations))
+	}
+
+	for i := version; i < len(sqliteMigrations); i++ {
+		tx, err := s.DB.Begin()
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("sqlite migration %d: %v", i+1, err)
+		}
+		if _, err := tx.Exec(`INSERT INTO schema_migrations (version) VALUES (?)`, i+1); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanner is satisfied by *sql.Row and *sql.Rows
+type scanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// querier is satisfied by *sql.DB and *sql.Tx
+type querier interface {
+	Exec(query string, args ...interface{}) (sql.Result, error)
+	Query(query string, args ...interface{}) (*sql.Rows, error)
+	QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+// readUser reads the user matching where and the rows of other tables that
+// belong to it
+func readUser(q querier, where string, args ...interface{}) (*pb.PrivateUser, error) {
+	user, err := scanUser(q.QueryRow(selectUser+` `+where, args...))
+	if err != nil {
+		return nil, err
+	}
+	if err := readUserRelations(q, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func scanUser(row scanner) (*pb.PrivateUser, error) {
+	user := &pb.PrivateUser{}
+	profile := &pb.Profile{}
+	var email, id sql.NullString
+	err := row.Scan(
+		&user.Username, &user.PasswordSha256, &user.PasswordHash, &email, &user.EmailVerified,
+		&profile.DisplayName, &profile.AvatarUrl, &profile.Locale, &profile.Timezone,
+		&user.Disabled, &user.DisabledAt, &id, &user.CreatedAt,
+		&user.TotpSecret, &user.TotpEnabled, &user.TotpLastStep,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	user.Email = email.String
+	user.Id = id.String
+	if profile.DisplayName != "" || profile.AvatarUrl != "" || profile.Locale != "" || profile.Timezone != "" {
+		user.Profile = profile
+	}
+	return user, nil
+}
+
+// userValues are the column values of user, in the order of userColumns
+func userValues(user *pb.PrivateUser) []interface{} {
+	profile := user.Profile
+	if profile == nil {
+		profile = &pb.Profile{}
+	}
+	return []interface{}{
+		user.Username, user.PasswordSha256, user.PasswordHash, nullString(user.Email), user.EmailVerified,
+		profile.DisplayName, profile.AvatarUrl, profile.Locale, profile.Timezone,
+		user.Disabled, user.DisabledAt, nullString(user.Id), user.CreatedAt,
+		user.TotpSecret, user.TotpEnabled, user.TotpLastStep,
+	}
+}
+
+// readUserRelations reads the attributes, roles, recovery codes and WebAuthn
+// credentials of user
+func readUserRelations(q querier, user *pb.PrivateUser) error {
+	if err := readAttributes(q, user); err != nil {
+		return err
+	}
+	if err := readRoles(q, user); err != nil {
+		return err
+	}
+	if err := readRecoveryCodes(q, user); err != nil {
+		return err
+	}
+	return readWebAuthnCredentials(q, user)
+}
+
+// writeUserRelations replaces the stored attributes, roles, recovery codes
+// and WebAuthn credentials of user
+func writeUserRelations(q querier, user *pb.PrivateUser) error {
+	if err := writeAttributes(q, user); err != nil {
+		return err
+	}
+	if err := writeRoles(q, user); err != nil {
+		return err
+	}
+	if err := writeRecoveryCodes(q, user); err != nil {
+		return err
+	}
+	return writeWebAuthnCredentials(q, user)
+}
+
+func readAttributes(q querier, user *pb.PrivateUser) error {
+	rows, err := q.Query(`SELECT key, value FROM user_attributes WHERE username = ?`, user.Username)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key, value string
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+		if user.Profile == nil {
+			user.Profile = &pb.Profile{}
+		}
+		if user.Profile.Attributes == nil {
+			user.Profile.Attributes = map[string]string{}
+		}
+		user.Profile.Attributes[key] = value
+	}
+	return rows.Err()
+}
+
+// writeAttributes replaces the stored attributes of user
+func writeAttributes(q querier, user *pb.PrivateUser) error {
+	if _, err := q.Exec(`DELETE FROM user_attributes WHERE username = ?`, user.Username); err != nil {
+		return err
+	}
+	if user.Profile == nil {
+		return nil
+	}
+	for key, value := range user.Profile.Attributes {
+		_, err := q.Exec(`INSERT INTO user_attributes (username, key, value) VALUES (?, ?, ?)`, user.Username, key, value)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readRenamedUser(q querier, username string) (*pb.PrivateRenamedUser, error) {
+	rename := &pb.PrivateRenamedUser{}
+	err := q.QueryRow(
+		`SELECT username, user_id, renamed_to, renamed_at, reserved_until FROM renamed_users WHERE username = ?`,
+		username,
+	).Scan(&rename.Username, &rename.UserId, &rename.RenamedTo, &rename.RenamedAt, &rename.ReservedUntil)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return rename, nil
+}
+
+// checkReservedSQL returns ErrUsernameReserved if username was given up by a
+// rename and is still reserved at now for a user other than userID
+func checkReservedSQL(q querier, username, userID string, now int64) error {
+	rename, err := readRenamedUser(q, username)
+	if err == ErrNotFound {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if now < rename.ReservedUntil && (userID == "" || userID != rename.UserId) {
+		return ErrUsernameReserved
+	}
+	return nil
+}
+
+// nullString stores "" as NULL so the unique indexes on users.email and
+// users.id ignore users without one
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// userConstraintError maps a unique constraint failure on users to the
+// matching Store error
+func userConstraintError(err error) error {
+	if !isUniqueViolation(err) {
+		return err
+	}
+	if strings.Contains(err.Error(), "users.email") {
+		return ErrEmailInUse
+	}
+	return ErrAlreadyExists
+}
+
+func scanSession(row scanner) (*pb.PrivateSession, error) {
+	session := &pb.PrivateSession{}
+	err := row.Scan(
+		&session.Token, &session.Username, &session.CreatedAt, &session.LastSeenAt,
+		&session.ExpiresAt, &session.ClientIp, &session.UserAgent, &session.UserId,
+	)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func execCount(q querier, query string, args ...interface{}) (int, error) {
+	result, err := q.Exec(query, args...)
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	return int(count), err
+}
+
+// isUniqueViolation reports whether err is a SQLite UNIQUE or PRIMARY KEY
+// constraint failure. The driver's error type is not matched so the store
+// does not depend on driver internals.
+func isUniqueViolation(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+func readRoles(q querier, user *pb.PrivateUser) error {
+	rows, err := q.Query(`SELECT role, resource FROM user_roles WHERE username = ? ORDER BY rowid`, user.Username)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		grant := &pb.RoleGrant{}
+		if err := rows.Scan(&grant.Role, &grant.Resource); err != nil {
+			return err
+		}
+		user.Roles = append(user.Roles, grant)
+	}
+	return rows.Err()
+}
+
+func writeRoles(q querier, user *pb.PrivateUser) error {
+	if _, err := q.Exec(`DELETE FROM user_roles WHERE username = ?`, user.Username); err != nil {
+		return err
+	}
+	for _, grant := range user.Roles {
+		_, err := q.Exec(`INSERT INTO user_roles (username, role, resource) VALUES (?, ?, ?)`, user.Username, grant.Role, grant.Resource)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readRecoveryCodes(q querier, user *pb.PrivateUser) error {
+	rows, err := q.Query(`SELECT code_hash FROM user_recovery_codes WHERE username = ? ORDER BY rowid`, user.Username)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var codeHash string
+		if err := rows.Scan(&codeHash); err != nil {
+			return err
+		}
+		user.RecoveryCodeHashes = append(user.RecoveryCodeHashes, codeHash)
+	}
+	return rows.Err()
+}
+
+func writeRecoveryCodes(q querier, user *pb.PrivateUser) error {
+	if _, err := q.Exec(`DELETE FROM user_recovery_codes WHERE username = ?`, user.Username); err != nil {
+		return err
+	}
+	for _, codeHash := range user.RecoveryCodeHashes {
+		if _, err := q.Exec(`INSERT INTO user_recovery_codes (username, code_hash) VALUES (?, ?)`, user.Username, codeHash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readWebAuthnCredentials(q querier, user *pb.PrivateUser) error {
+	rows, err := q.Query(
+		`SELECT credential_id, public_key, sign_count, name, created_at, last_used_at
+		FROM user_webauthn_credentials WHERE username = ? ORDER BY rowid`, user.Username,
+	)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		credential := &pb.PrivateWebAuthnCredential{}
+		err := rows.Scan(
+			&credential.Id, &credential.PublicKey, &credential.SignCount,
+			&credential.Name, &credential.CreatedAt, &credential.LastUsedAt,
+		)
+		if err != nil {
+			return err
+		}
+		user.WebauthnCredentials = append(user.WebauthnCredentials, credential)
+	}
+	return rows.Err()
+}
+
+func writeWebAuthnCredentials(q querier, user *pb.PrivateUser) error {
+	if _, err := q.Exec(`DELETE FROM user_webauthn_credentials WHERE username = ?`, user.Username); err != nil {
+		return err
+	}
+	for _, credential := range user.WebauthnCredentials {
+		_, err := q.Exec(
+			`INSERT INTO user_webauthn_credentials (username, credential_id, public_key, sign_count, name, created_at, last_used_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			user.Username, credential.Id, credential.PublicKey, credential.SignCount,
+			credential.Name, credential.CreatedAt, credential.LastUsedAt,
+		)
+		if isUniqueViolation(err) {
+			return ErrCredentialInUse
+		} else if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readGroup(q querier, name string) (*pb.PrivateGroup, error) {
+	group := &pb.PrivateGroup{}
+	err := q.QueryRow(`SELECT name, description, created_at FROM groups WHERE name = ?`, name).
+		Scan(&group.Name, &group.Description, &group.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	rows, err := q.Query(`SELECT role, resource FROM group_roles WHERE group_name = ? ORDER BY rowid`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		grant := &pb.RoleGrant{}
+		if err := rows.Scan(&grant.Role, &grant.Resource); err != nil {
+			return nil, err
+		}
+		group.Roles = append(group.Roles, grant)
+	}
+	return group, rows.Err()
+}
+
+func writeGroupRoles(q querier, group *pb.PrivateGroup) error {
+	if _, err := q.Exec(`DELETE FROM group_roles WHERE group_name = ?`, group.Name); err != nil {
+		return err
+	}
+	for _, grant := range group.Roles {
+		_, err := q.Exec(`INSERT INTO group_roles (group_name, role, resource) VALUES (?, ?, ?)`, group.Name, grant.Role, grant.Resource)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// memberColumns splits a member into the member_kind and member_name columns
+func memberColumns(member *pb.Member) (string, string) {
+	if member.Group != "" {
+		return "group", member.Group
+	}
+	return "user", member.Username
+}
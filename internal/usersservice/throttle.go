@@ -0,0 +1,289 @@
+package usersservice
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/twitchtv/twirp"
+)
+
+// Login throttling defaults. After DefaultLoginFreeAttempts failed passwords
+// for a username each further attempt has to wait twice as long as the last,
+// from DefaultLoginBackoff up to DefaultMaxLoginBackoff. Failures are
+// forgotten after DefaultLoginFailureWindow without one, or sooner once
+// DefaultMaxLoginFailureUsernames usernames have failures.
+const (
+	DefaultLoginFreeAttempts        = 5
+	DefaultLoginBackoff             = time.Second
+	DefaultMaxLoginBackoff          = 15 * time.Minute
+	DefaultLoginFailureWindow       = 24 * time.Hour
+	DefaultMaxLoginFailureUsernames = 100000
+
+	// Each client ip may make DefaultIPLoginRate login attempts a second
+	// with bursts of DefaultIPLoginBurst
+	DefaultIPLoginRate  = 1.0
+	DefaultIPLoginBurst = 20
+)
+
+// maxEvictionTries bounds how many locked usernames a new one looks past for
+// one to forget
+const maxEvictionTries = 8
+
+// ipLimitedMethods are the rpcs that guess at credentials or send mail to
+// whoever is named, they share the per-ip token bucket
+var ipLimitedMethods = map[string]bool{
+	"Login":                true,
+	"VerifySecondFactor":   true,
+	"FinishWebAuthnLogin":  true,
+	"DisableTOTP":          true,
+	"RequestPasswordReset": true,
+}
+
+// ServerHooks rate limits logins per client ip. The ip comes from
+// WithClientInfo, requests without one are not limited.
+//
+//	handler := usersservice.WithClientInfo(pb.NewUsersServer(server, server.ServerHooks()))
+func (us *userService) ServerHooks() *twirp.ServerHooks {
+	return &twirp.ServerHooks{
+		RequestRouted: func(ctx context.Context) (context.Context, error) {
+			method, _ := twirp.MethodName(ctx)
+			ip := ClientInfoFromContext(ctx).IP
+			limited := ipLimitedMethods[method] || (method == "Register" && us.HideUsernames)
+			if !limited || ip == "" || us.IPLoginRate <= 0 {
+				return ctx, nil
+			}
+			if wait := us.throttle.takeToken(ip, us.Now(), us.IPLoginRate, us.IPLoginBurst); wait > 0 {
+				return ctx, errRateLimited(ctx, wait)
+			}
+			return ctx, nil
+		},
+	}
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+// loginThrottle holds the failed login counters and per-ip token buckets.
+// They are kept in memory, each server process throttles on its own.
+type loginThrottle struct {
+	mu        sync.Mutex
+	failures  map[string]*loginFailures // by normalized username
+	recent    *list.List                // of the failures, the last attempted first
+	buckets   map[string]*tokenBucket   // by client ip
+	lastPrune time.Time
+}
+
+type loginFailures struct {
+	username string
+	count    int           // failed attempts, and ones still being checked
+	pending  int           // attempts still being checked
+	last     time.Time     // of the last attempt
+	until    time.Time     // no attempts before this
+	element  *list.Element // in recent
+}
+
+type tokenBucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// loginAttempt is a login attempt reserved by reserveLogin. It counts as a
+// failure from the start, so concurrent guesses can not all get in before
+// the first is checked, until succeeded or release takes it back.
+type loginAttempt struct {
+	us       *userService
+	username string
+	reserved bool
+}
+
+// reserveLogin reserves a login attempt for username, or returns how long
+// username has to wait for one
+func (us *userService) reserveLogin(username string) (*loginAttempt, time.Duration) {
+	attempt := &loginAttempt{us: us, username: username}
+	if us.MaxLoginBackoff <= 0 {
+		return attempt, 0
+	}
+	t := &us.throttle
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := us.Now()
+	t.prune(now, us.IPLoginRate, us.IPLoginBurst)
+	t.forgetFailures(now, us.LoginFailureWindow)
+	failures, ok := t.failures[username]
+	if ok && now.Before(failures.until) {
+		return nil, failures.until.Sub(now)
+	}
+	if !ok {
+		if t.failures == nil {
+			t.failures = map[string]*loginFailures{}
+			t.recent = list.New()
+		}
+		if len(t.failures) >= us.MaxLoginFailureUsernames {
+			t.evict(now)
+		}
+		failures = &loginFailures{username: username}
+		failures.element = t.recent.PushFront(failures)
+		t.failures[username] = failures
+	}
+	t.recent.MoveToFront(failures.element)
+	failures.count++
+	failures.pending++
+	failures.last = now
+	failures.until = now.Add(us.loginBackoff(failures.count))
+	attempt.reserved = true
+	return attempt, 0
+}
+
+// failed leaves the attempt counted as a failed password or second factor
+// code
+func (a *loginAttempt) failed() {
+	if !a.reserved {
+		return
+	}
+	a.reserved = false
+	t := &a.us.throttle
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	failures, ok := t.failures[a.username]
+	if !ok {
+		return
+	}
+	failures.pending--
+	if backoff := failures.until.Sub(failures.last); backoff > 0 {
+		a.us.audit("%d failed logins for %s, locked for %s", failures.count, a.username, backoff)
+	}
+}
+
+// succeeded forgets the failures of the username, the whole login is done
+func (a *loginAttempt) succeeded() {
+	a.reserved = false
+	t := &a.us.throttle
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if failures, ok := t.failures[a.username]; ok {
+		t.forget(failures)
+	}
+}
+
+// release takes the attempt back if it was neither failed nor succeeded,
+// for a right password with a second factor still to come or an error that
+// says nothing about the guess
+func (a *loginAttempt) release() {
+	if !a.reserved {
+		return
+	}
+	a.reserved = false
+	t := &a.us.throttle
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	failures, ok := t.failures[a.username]
+	if !ok {
+		return
+	}
+	failures.pending--
+	failures.count--
+	if failures.count <= 0 && failures.pending <= 0 {
+		t.forget(failures)
+		return
+	}
+	failures.until = failures.last.Add(a.us.loginBackoff(failures.count))
+}
+
+// loginBackoff is how long to wait after count failures
+func (us *userService) loginBackoff(count int) time.Duration {
+	over := count - us.LoginFreeAttempts
+	if over <= 0 {
+		return 0
+	}
+	if over >= 32 {
+		return us.MaxLoginBackoff
+	}
+	return time.Duration(math.Min(float64(us.LoginBackoff)*math.Pow(2, float64(over-1)), float64(us.MaxLoginBackoff)))
+}
+
+// takeToken takes a token from the bucket of ip, returning how long until
+// one is available if it is empty
+func (t *loginThrottle) takeToken(ip string, now time.Time, rate float64, burst int) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.buckets == nil {
+		t.buckets = map[string]*tokenBucket{}
+	}
+	bucket, ok := t.buckets[ip]
+	if !ok {
+		bucket = &tokenBucket{tokens: float64(burst), last: now}
+		t.buckets[ip] = bucket
+	}
+	bucket.tokens = math.Min(float64(burst), bucket.tokens+now.Sub(bucket.last).Seconds()*rate)
+	bucket.last = now
+	if bucket.tokens < 1 {
+		return time.Duration((1 - bucket.tokens) / rate * float64(time.Second))
+	}
+	bucket.tokens--
+	return 0
+}
+
+// prune drops full buckets, at most once a minute
+func (t *loginThrottle) prune(now time.Time, rate float64, burst int) {
+	if now.Sub(t.lastPrune) < time.Minute {
+		return
+	}
+	t.lastPrune = now
+	for ip, bucket := range t.buckets {
+		if bucket.tokens+now.Sub(bucket.last).Seconds()*rate >= float64(burst) {
+			delete(t.buckets, ip)
+		}
+	}
+}
+
+// forgetFailures drops the failures last attempted window ago or earlier,
+// they are at the back of recent
+func (t *loginThrottle) forgetFailures(now time.Time, window time.Duration) {
+	for t.recent != nil && t.recent.Len() > 0 {
+		failures := t.recent.Back().Value.(*loginFailures)
+		if now.Sub(failures.last) < window || failures.pending > 0 {
+			return
+		}
+		t.forget(failures)
+	}
+}
+
+// evict forgets the least recently attempted username to make room for a
+// new one. Locked usernames and ones with attempts being checked are never
+// forgotten, so spraying usernames can not lift a lockout. If the few it
+// looks at are all locked the map grows past its bound until they unlock.
+func (t *loginThrottle) evict(now time.Time) {
+	for i := 0; i < maxEvictionTries && t.recent.Len() > 0; i++ {
+		failures := t.recent.Back().Value.(*loginFailures)
+		if failures.pending == 0 && !now.Before(failures.until) {
+			t.forget(failures)
+			return
+		}
+		t.recent.MoveToFront(failures.element)
+	}
+}
+
+// forget drops failures
+func (t *loginThrottle) forget(failures *loginFailures) {
+	t.recent.Remove(failures.element)
+	delete(t.failures, failures.username)
+}
+
+// errRateLimited is a ResourceExhausted error telling the client to retry
+// after wait, in whole seconds in the retry_after meta and the Retry-After
+// header
+func errRateLimited(ctx context.Context, wait time.Duration) error {
+	seconds := fmt.Sprint(int64(math.Ceil(wait.Seconds())))
+	twirp.SetHTTPResponseHeader(ctx, "Retry-After", seconds)
+	return twirp.NewError(twirp.ResourceExhausted, "too many login attempts").WithMeta("retry_after", seconds)
+}
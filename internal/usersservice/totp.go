@@ -0,0 +1,442 @@
+package usersservice
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strings"
+	"time"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/twitchtv/twirp"
+)
+
+// Two-factor authentication follows RFC 6238: 6 digit codes from HMAC-SHA1
+// over 30 second time steps. Codes from one step either side of now are
+// accepted for clock drift.
+const (
+	TOTPDigits = 6
+	TOTPPeriod = 30 * time.Second
+	TOTPSkew   = 1
+
+	// DefaultLoginChallengeTTL is how long a Login has to be completed by
+	// VerifySecondFactor
+	DefaultLoginChallengeTTL = 5 * time.Minute
+
+	// MaxSecondFactorFailures is how many bad codes a login challenge
+	// survives
+	MaxSecondFactorFailures = 5
+
+	// RecoveryCodeCount is how many recovery codes ConfirmTOTP hands out
+	RecoveryCodeCount = 10
+)
+
+// SecretKeySize is the length of the key that seals TOTP secrets, AES-256
+const SecretKeySize = 32
+
+// LoadSecretKey reads a hex encoded key from a file, ex: one made by
+// `openssl rand -hex 32`
+func LoadSecretKey(path string) ([]byte, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := hex.DecodeString(strings.TrimSpace(string(data)))
+	if err != nil || len(key) != SecretKeySize {
+		return nil, fmt.Errorf("%s: not a hex encoded %d byte key", path, SecretKeySize)
+	}
+	return key, nil
+}
+
+// ReapLoginChallenges deletes every expired login challenge and returns how
+// many were deleted
+func (us *userService) ReapLoginChallenges() (int, error) {
+	return us.Store.DeleteExpiredLoginChallenges(us.Now().Unix())
+}
+
+func (us *userService) EnrollTOTP(c context.Context, req *pb.EnrollTOTPReq) (*pb.EnrollTOTPResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if us.SecretKey == nil {
+		return nil, twirp.NewError(twirp.FailedPrecondition, "two-factor authentication is not configured")
+	}
+
+	// The secret is sealed to the user's id, it does not change on a rename
+	user, err := us.getUser(session.Username)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := us.ensureUserID(user)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 20)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	sealed, err := us.sealSecret(userID, secret)
+	if err != nil {
+		return nil, err
+	}
+
+	err = us.Store.UpdateUser(session.Username, func(user *pb.PrivateUser) error {
+		if user.TotpEnabled {
+			return errTOTPEnabled()
+		}
+		user.TotpSecret = sealed
+		user.TotpLastStep = 0
+		return nil
+	})
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, session.Username+" not found")
+	} else if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditEnrollTOTP, session.Username, session.Username, AuditSuccess), "%s enrolled a TOTP secret", session.Username)
+
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(secret)
+	label := url.PathEscape(us.TOTPIssuer + ":" + session.Username)
+	query := url.Values{
+		"secret":    {encoded},
+		"issuer":    {us.TOTPIssuer},
+		"algorithm": {"SHA1"},
+		"digits":    {fmt.Sprint(TOTPDigits)},
+		"period":    {fmt.Sprint(int(TOTPPeriod / time.Second))},
+	}
+	return &pb.EnrollTOTPResp{
+		Secret:     encoded,
+		OtpauthUrl: "otpauth://totp/" + label + "?" + query.Encode(),
+	}, nil
+}
+
+func (us *userService) ConfirmTOTP(c context.Context, req *pb.ConfirmTOTPReq) (*pb.ConfirmTOTPResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if req.Code == "" {
+		return nil, twirp.RequiredArgumentError("ConfirmTOTPReq.code")
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+	err = us.Store.UpdateUser(session.Username, func(user *pb.PrivateUser) error {
+		if user.TotpEnabled {
+			return errTOTPEnabled()
+		}
+		if user.TotpSecret == nil {
+			return twirp.NewError(twirp.FailedPrecondition, "no TOTP secret is enrolled")
+		}
+		step, err := us.checkTOTP(user, req.Code)
+		if err != nil {
+			return err
+		}
+		if step == 0 {
+			return twirp.InvalidArgumentError("code", "is not a current code")
+		}
+		user.TotpEnabled = true
+		user.TotpLastStep = step
+		user.RecoveryCodeHashes = hashes
+		return nil
+	})
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, session.Username+" not found")
+	} else if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditEnableTOTP, session.Username, session.Username, AuditSuccess), "%s turned on two-factor authentication", session.Username)
+
+	return &pb.ConfirmTOTPResp{RecoveryCodes: codes}, nil
+}
+
+func (us *userService) DisableTOTP(c context.Context, req *pb.DisableTOTPReq) (*pb.DisableTOTPResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if req.Code == "" {
+		return nil, twirp.RequiredArgumentError("DisableTOTPReq.code")
+	}
+
+	err = us.Store.UpdateUser(session.Username, func(user *pb.PrivateUser) error {
+		if !user.TotpEnabled {
+			return twirp.NewError(twirp.FailedPrecondition, "two-factor authentication is not enabled")
+		}
+		ok, err := us.checkSecondFactor(user, req.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return twirp.InvalidArgumentError("code", "is not a current code or an unused recovery code")
+		}
+		user.TotpSecret = nil
+		user.TotpEnabled = false
+		user.TotpLastStep = 0
+		user.RecoveryCodeHashes = nil
+		return nil
+	})
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, session.Username+" not found")
+	} else if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditDisableTOTP, session.Username, session.Username, AuditSuccess), "%s turned off two-factor authentication", session.Username)
+
+	return &pb.DisableTOTPResp{}, nil
+}
+
+func (us *userService) VerifySecondFactor(c context.Context, req *pb.VerifySecondFactorReq) (*pb.VerifySecondFactorResp, error) {
+	if req.Challenge == "" {
+		return nil, twirp.RequiredArgumentError("VerifySecondFactorReq.challenge")
+	}
+	if req.Code == "" {
+		return nil, twirp.RequiredArgumentError("VerifySecondFactorReq.code")
+	}
+
+	// Taking the challenge means concurrent guesses can not share it
+	challenge, err := us.Store.TakeLoginChallenge(hashSecretToken(req.Challenge))
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid challenge")
+	} else if err != nil {
+		return nil, err
+	}
+	if challenge.Kind != "" {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid challenge")
+	}
+	if us.Now().Unix() >= challenge.ExpiresAt {
+		return nil, twirp.NewError(twirp.PermissionDenied, "challenge expired")
+	}
+	user, err := us.Store.GetUserByID(challenge.UserId)
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid challenge")
+	} else if err != nil {
+		return nil, err
+	}
+
+	// Bad codes count with bad passwords, challenges taken before the
+	// backoff started wait it out and stay usable after
+	attempt, wait := us.reserveLogin(normalizeUsername(user.Username))
+	if wait > 0 {
+		if err := us.Store.PutLoginChallenge(challenge); err != nil {
+			return nil, err
+		}
+		us.recordEvent(c, loginEvent(user.Username, AuditFailure), "%s login failed: too many attempts", user.Username)
+		return nil, errRateLimited(c, wait)
+	}
+	defer attempt.release()
+
+	recoveryCodes := len(user.RecoveryCodeHashes)
+	err = us.Store.UpdateUser(user.Username, func(current *pb.PrivateUser) error {
+		ok, err := us.checkSecondFactor(current, req.Code)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			return errBadSecondFactor
+		}
+		user = current
+		return nil
+	})
+	if err == errBadSecondFactor {
+		attempt.failed()
+		challenge.Failures++
+		if challenge.Failures < MaxSecondFactorFailures {
+			if err := us.Store.PutLoginChallenge(challenge); err != nil {
+				return nil, err
+			}
+		} else {
+			us.audit("%s login challenge dropped after %d bad codes", user.Username, challenge.Failures)
+		}
+		us.recordEvent(c, loginEvent(user.Username, AuditFailure), "%s login failed: bad second factor code", user.Username)
+		return nil, twirp.NewError(twirp.PermissionDenied, "bad code")
+	} else if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid challenge")
+	} else if err != nil {
+		return nil, err
+	}
+	if user.Disabled {
+		us.recordEvent(c, loginEvent(user.Username, AuditFailure), "%s login failed: account disabled", user.Username)
+		return nil, errAccountDisabled()
+	}
+	session, err := us.startSession(c, user)
+	if err != nil {
+		return nil, err
+	}
+	attempt.succeeded()
+	if left := len(user.RecoveryCodeHashes); left < recoveryCodes {
+		us.recordEvent(c, loginEvent(user.Username, AuditSuccess), "%s logged in with a recovery code, %d left", user.Username, left)
+	} else {
+		us.recordEvent(c, loginEvent(user.Username, AuditSuccess), "%s logged in with a TOTP code", user.Username)
+	}
+	return &pb.VerifySecondFactorResp{
+		Session:           session,
+		RecoveryCodesLeft: int32(len(user.RecoveryCodeHashes)),
+	}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+var errBadSecondFactor = errors.New("bad second factor")
+
+func errTOTPEnabled() error {
+	return twirp.NewError(twirp.FailedPrecondition, "two-factor authentication is already enabled")
+}
+
+// newLoginChallenge stores a challenge for user to complete with
+// VerifySecondFactor and returns it
+func (us *userService) newLoginChallenge(user *pb.PrivateUser) (string, error) {
+	challenge, err := newSecretToken()
+	if err != nil {
+		return "", err
+	}
+	now := us.Now()
+	err = us.Store.PutLoginChallenge(&pb.PrivateLoginChallenge{
+		ChallengeHash: hashSecretToken(challenge),
+		UserId:        user.Id,
+		CreatedAt:     now.Unix(),
+		ExpiresAt:     now.Add(us.LoginChallengeTTL).Unix(),
+	})
+	return challenge, err
+}
+
+// checkSecondFactor checks code against user's TOTP secret, or failing that
+// their recovery codes. A match is used up on user: the TOTP time step can
+// not be used again and the recovery code is removed. The caller writes user
+// back.
+func (us *userService) checkSecondFactor(user *pb.PrivateUser, code string) (bool, error) {
+	step, err := us.checkTOTP(user, code)
+	if err != nil {
+		return false, err
+	}
+	if step != 0 {
+		user.TotpLastStep = step
+		return true, nil
+	}
+
+	codeHash := hashSecretToken(normalizeRecoveryCode(code))
+	for i, hash := range user.RecoveryCodeHashes {
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(codeHash)) == 1 {
+			user.RecoveryCodeHashes = append(user.RecoveryCodeHashes[:i:i], user.RecoveryCodeHashes[i+1:]...)
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// checkTOTP returns the time step code is valid for, or 0 if it is not a
+// current code. Steps up to user.TotpLastStep were already used.
+func (us *userService) checkTOTP(user *pb.PrivateUser, code string) (int64, error) {
+	if len(code) != TOTPDigits || user.TotpSecret == nil {
+		return 0, nil
+	}
+	secret, err := us.openSecret(user.Id, user.TotpSecret)
+	if err != nil {
+		return 0, err
+	}
+
+	now := us.Now().Unix() / int64(TOTPPeriod/time.Second)
+	for step := now - TOTPSkew; step <= now+TOTPSkew; step++ {
+		if step <= user.TotpLastStep {
+			continue
+		}
+		if subtle.ConstantTimeCompare([]byte(totpCode(secret, step)), []byte(code)) == 1 {
+			return step, nil
+		}
+	}
+	return 0, nil
+}
+
+// totpCode is the RFC 4226 HOTP value of secret at counter step
+func totpCode(secret []byte, step int64) string {
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(step))
+	mac := hmac.New(sha1.New, secret)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0xf
+	value := binary.BigEndian.Uint32(sum[offset:]) & 0x7fffffff
+	return fmt.Sprintf("%06d", value%1000000)
+}
+
+// newRecoveryCodes returns RecoveryCodeCount codes formatted for people, ex:
+// abcd-efgh-ijkl-mnop, and the hashes to store
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	hashes := make([]string, RecoveryCodeCount)
+	encoding := base32.StdEncoding.WithPadding(base32.NoPadding)
+	for i := range codes {
+		random := make([]byte, 10)
+		if _, err := rand.Read(random); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(encoding.EncodeToString(random))
+		codes[i] = code[0:4] + "-" + code[4:8] + "-" + code[8:12] + "-" + code[12:16]
+		hashes[i] = hashSecretToken(code)
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode ignores case, spaces and dashes
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(code)
+	return strings.NewReplacer("-", "", " ", "").Replace(code)
+}
+
+// sealSecret encrypts a TOTP secret with the server's key, AES-GCM with the
+// nonce prepended. The user id is the associated data so a sealed secret
+// copied to another user's record does not open.
+func (us *userService) sealSecret(userID string, secret []byte) ([]byte, error) {
+	aead, err := us.secretAEAD()
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, secret, []byte(userID)), nil
+}
+
+// openSecret is the inverse of sealSecret, it fails if sealed was sealed for
+// another user
+func (us *userService) openSecret(userID string, sealed []byte) ([]byte, error) {
+	aead, err := us.secretAEAD()
+	if err != nil {
+		return nil, err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return nil, errors.New("sealed secret is too short")
+	}
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	return aead.Open(nil, nonce, ciphertext, []byte(userID))
+}
+
+func (us *userService) secretAEAD() (cipher.AEAD, error) {
+	if us.SecretKey == nil {
+		return nil, errors.New("no secret key is configured")
+	}
+	block, err := aes.NewCipher(us.SecretKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
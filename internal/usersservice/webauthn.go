@@ -0,0 +1,528 @@
+package usersservice
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"time"
+
+	pb "github.com/ericmoritz/twirp-users/rpc/users"
+	"github.com/twitchtv/twirp"
+)
+
+// WebAuthn ceremonies, the type in the client data and the kind of their
+// stored challenge
+const (
+	webAuthnCreate = "webauthn.create"
+	webAuthnGet    = "webauthn.get"
+)
+
+// COSE algorithms a credential may use, ES256 and RS256
+const (
+	coseES256 = -7
+	coseRS256 = -257
+)
+
+// Authenticator data flags
+const (
+	authDataUserPresent  = 0x01
+	authDataUserVerified = 0x04
+	authDataAttested     = 0x40
+)
+
+// WebAuthn configures the relying party for WebAuthn. Only credentials
+// created for RPID and client data from one of Origins are accepted.
+type WebAuthn struct {
+	RPID    string   // the site's domain, ex: example.com
+	RPName  string   // shown by authenticators, ex: Example
+	Origins []string // ex: https://example.com, https://login.example.com
+}
+
+func (us *userService) BeginWebAuthnRegistration(c context.Context, req *pb.BeginWebAuthnRegistrationReq) (*pb.BeginWebAuthnRegistrationResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if us.WebAuthn == nil {
+		return nil, errWebAuthnNotConfigured()
+	}
+	user, err := us.getUser(session.Username)
+	if err != nil {
+		return nil, err
+	}
+	// The user handle is the id, it survives renames
+	userID, err := us.ensureUserID(user)
+	if err != nil {
+		return nil, err
+	}
+
+	challenge, err := us.newWebAuthnChallenge(webAuthnCreate, userID)
+	if err != nil {
+		return nil, err
+	}
+	exclude := []webAuthnDescriptor{}
+	for _, credential := range user.WebauthnCredentials {
+		exclude = append(exclude, webAuthnDescriptor{Type: "public-key", ID: base64URL(credential.Id)})
+	}
+	options, err := json.Marshal(map[string]interface{}{
+		"publicKey": map[string]interface{}{
+			"rp": map[string]string{
+				"id":   us.WebAuthn.RPID,
+				"name": us.WebAuthn.RPName,
+			},
+			"user": map[string]string{
+				"id":          base64URL([]byte(userID)),
+				"name":        user.Username,
+				"displayName": user.Username,
+			},
+			"challenge": challenge,
+			"pubKeyCredParams": []map[string]interface{}{
+				{"type": "public-key", "alg": coseES256},
+				{"type": "public-key", "alg": coseRS256},
+			},
+			"timeout":            int64(us.LoginChallengeTTL / time.Millisecond),
+			"excludeCredentials": exclude,
+			"authenticatorSelection": map[string]string{
+				"residentKey":      "preferred",
+				"userVerification": "required",
+			},
+			"attestation": "none",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BeginWebAuthnRegistrationResp{Options: string(options)}, nil
+}
+
+func (us *userService) FinishWebAuthnRegistration(c context.Context, req *pb.FinishWebAuthnRegistrationReq) (*pb.FinishWebAuthnRegistrationResp, error) {
+	session, err := us.validateSession(c, req.Session)
+	if err != nil {
+		return nil, err
+	}
+	if us.WebAuthn == nil {
+		return nil, errWebAuthnNotConfigured()
+	}
+	if len(req.ClientDataJson) == 0 {
+		return nil, twirp.RequiredArgumentError("FinishWebAuthnRegistrationReq.client_data_json")
+	}
+	if len(req.AttestationObject) == 0 {
+		return nil, twirp.RequiredArgumentError("FinishWebAuthnRegistrationReq.attestation_object")
+	}
+
+	challenge, err := us.takeWebAuthnChallenge(webAuthnCreate, req.ClientDataJson)
+	if err != nil {
+		return nil, err
+	}
+	user, err := us.getUser(session.Username)
+	if err != nil {
+		return nil, err
+	}
+	if challenge.UserId != user.Id {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid challenge")
+	}
+
+	// Attestation statements are not checked, as if the authenticator had
+	// honored the "none" attestation asked for
+	authData, err := parseAttestationObject(req.AttestationObject)
+	if err != nil {
+		return nil, err
+	}
+	parsed, err := us.parseAuthenticatorData(authData)
+	if err != nil {
+		return nil, err
+	}
+	if parsed.credentialID == nil {
+		return nil, twirp.InvalidArgumentError("attestation_object", "has no attested credential")
+	}
+
+	now := us.Now().Unix()
+	credential := &pb.PrivateWebAuthnCredential{
+		Id:        parsed.credentialID,
+		PublicKey: parsed.publicKey,
+		SignCount: parsed.signCount,
+		Name:      req.Name,
+		CreatedAt: now,
+	}
+	err = us.Store.UpdateUser(user.Username, func(user *pb.PrivateUser) error {
+		if findWebAuthnCredential(user, credential.Id) != nil {
+			return twirp.NewError(twirp.AlreadyExists, "credential already registered")
+		}
+		user.WebauthnCredentials = append(user.WebauthnCredentials, credential)
+		return nil
+	})
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.NotFound, session.Username+" not found")
+	} else if err == ErrCredentialInUse {
+		return nil, twirp.NewError(twirp.AlreadyExists, "credential already registered")
+	} else if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, auditEvent(AuditAddWebAuthn, session.Username, session.Username, AuditSuccess), "%s registered WebAuthn credential %s", session.Username, base64URL(credential.Id))
+
+	return &pb.FinishWebAuthnRegistrationResp{
+		Credential: publicWebAuthnCredential(credential),
+	}, nil
+}
+
+func (us *userService) BeginWebAuthnLogin(c context.Context, req *pb.BeginWebAuthnLoginReq) (*pb.BeginWebAuthnLoginResp, error) {
+	if us.WebAuthn == nil {
+		return nil, errWebAuthnNotConfigured()
+	}
+
+	// An unknown username gets the same options as no username, so they do
+	// not tell who has an account
+	userID := ""
+	allow := []webAuthnDescriptor{}
+	if req.Username != "" {
+		user, err := us.Store.GetUser(normalizeUsername(req.Username))
+		if err != nil && err != ErrNotFound {
+			return nil, err
+		}
+		if err == nil && user.Id != "" && len(user.WebauthnCredentials) > 0 {
+			userID = user.Id
+			for _, credential := range user.WebauthnCredentials {
+				allow = append(allow, webAuthnDescriptor{Type: "public-key", ID: base64URL(credential.Id)})
+			}
+		}
+	}
+
+	challenge, err := us.newWebAuthnChallenge(webAuthnGet, userID)
+	if err != nil {
+		return nil, err
+	}
+	options, err := json.Marshal(map[string]interface{}{
+		"publicKey": map[string]interface{}{
+			"challenge":        challenge,
+			"timeout":          int64(us.LoginChallengeTTL / time.Millisecond),
+			"rpId":             us.WebAuthn.RPID,
+			"allowCredentials": allow,
+			"userVerification": "required",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &pb.BeginWebAuthnLoginResp{Options: string(options)}, nil
+}
+
+func (us *userService) FinishWebAuthnLogin(c context.Context, req *pb.FinishWebAuthnLoginReq) (*pb.FinishWebAuthnLoginResp, error) {
+	if us.WebAuthn == nil {
+		return nil, errWebAuthnNotConfigured()
+	}
+	if len(req.CredentialId) == 0 {
+		return nil, twirp.RequiredArgumentError("FinishWebAuthnLoginReq.credential_id")
+	}
+	if len(req.ClientDataJson) == 0 {
+		return nil, twirp.RequiredArgumentError("FinishWebAuthnLoginReq.client_data_json")
+	}
+	if len(req.AuthenticatorData) == 0 {
+		return nil, twirp.RequiredArgumentError("FinishWebAuthnLoginReq.authenticator_data")
+	}
+	if len(req.Signature) == 0 {
+		return nil, twirp.RequiredArgumentError("FinishWebAuthnLoginReq.signature")
+	}
+
+	challenge, err := us.takeWebAuthnChallenge(webAuthnGet, req.ClientDataJson)
+	if err != nil {
+		return nil, err
+	}
+
+	// A challenge for a username is bound to its user, otherwise the
+	// discoverable credential names its user
+	userID := challenge.UserId
+	if userID == "" {
+		userID = string(req.UserHandle)
+	}
+	if userID == "" {
+		return nil, twirp.RequiredArgumentError("FinishWebAuthnLoginReq.user_handle")
+	}
+	if len(req.UserHandle) > 0 && string(req.UserHandle) != userID {
+		return nil, twirp.NewError(twirp.PermissionDenied, "unknown credential")
+	}
+	user, err := us.Store.GetUserByID(userID)
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "unknown credential")
+	} else if err != nil {
+		return nil, err
+	}
+	credential := findWebAuthnCredential(user, req.CredentialId)
+	if credential == nil {
+		return nil, twirp.NewError(twirp.PermissionDenied, "unknown credential")
+	}
+
+	parsed, err := us.parseAuthenticatorData(req.AuthenticatorData)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(req.ClientDataJson)
+	signed := append(append([]byte(nil), req.AuthenticatorData...), clientDataHash[:]...)
+	if !verifyCOSESignature(credential.PublicKey, signed, req.Signature) {
+		us.recordEvent(c, loginEvent(user.Username, AuditFailure), "%s login failed: bad WebAuthn signature", user.Username)
+		return nil, twirp.NewError(twirp.PermissionDenied, "bad signature")
+	}
+
+	// A counter that does not go up means the authenticator was cloned.
+	// Authenticators without a counter always send 0.
+	err = us.Store.UpdateUser(user.Username, func(current *pb.PrivateUser) error {
+		stored := findWebAuthnCredential(current, req.CredentialId)
+		if stored == nil {
+			return twirp.NewError(twirp.PermissionDenied, "unknown credential")
+		}
+		if (parsed.signCount != 0 || stored.SignCount != 0) && parsed.signCount <= stored.SignCount {
+			return errSignCount
+		}
+		stored.SignCount = parsed.signCount
+		stored.LastUsedAt = us.Now().Unix()
+		user = current
+		return nil
+	})
+	if err == errSignCount {
+		us.recordEvent(c, loginEvent(user.Username, AuditFailure), "%s WebAuthn credential %s may be cloned, its sign count did not increase", user.Username, base64URL(req.CredentialId))
+		return nil, twirp.NewError(twirp.PermissionDenied, "credential sign count did not increase")
+	} else if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "unknown credential")
+	} else if err != nil {
+		return nil, err
+	}
+
+	if user.Disabled {
+		us.recordEvent(c, loginEvent(user.Username, AuditFailure), "%s login failed: account disabled", user.Username)
+		return nil, errAccountDisabled()
+	}
+	session, err := us.startSession(c, user)
+	if err != nil {
+		return nil, err
+	}
+	us.recordEvent(c, loginEvent(user.Username, AuditSuccess), "%s logged in with WebAuthn credential %s", user.Username, base64URL(req.CredentialId))
+
+	return &pb.FinishWebAuthnLoginResp{Session: session}, nil
+}
+
+///////////////////////////////////////////////////////////////////////////////
+// Internal
+///////////////////////////////////////////////////////////////////////////////
+
+var errSignCount = errors.New("sign count did not increase")
+
+func errWebAuthnNotConfigured() error {
+	return twirp.NewError(twirp.FailedPrecondition, "WebAuthn is not configured")
+}
+
+// webAuthnDescriptor is a PublicKeyCredentialDescriptor
+type webAuthnDescriptor struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// clientData is the part of CollectedClientData that is checked
+type clientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// newWebAuthnChallenge stores a challenge for a ceremony of kind, bound to
+// userID if it is set, and returns it base64url encoded
+func (us *userService) newWebAuthnChallenge(kind, userID string) (string, error) {
+	challenge, err := newSecretToken()
+	if err != nil {
+		return "", err
+	}
+	now := us.Now()
+	err = us.Store.PutLoginChallenge(&pb.PrivateLoginChallenge{
+		ChallengeHash: hashSecretToken(challenge),
+		UserId:        userID,
+		CreatedAt:     now.Unix(),
+		ExpiresAt:     now.Add(us.LoginChallengeTTL).Unix(),
+		Kind:          kind,
+	})
+	return challenge, err
+}
+
+// takeWebAuthnChallenge checks the client data of a ceremony of kind and uses
+// up its challenge
+func (us *userService) takeWebAuthnChallenge(kind string, clientDataJSON []byte) (*pb.PrivateLoginChallenge, error) {
+	data := &clientData{}
+	if err := json.Unmarshal(clientDataJSON, data); err != nil || data.Challenge == "" {
+		return nil, twirp.InvalidArgumentError("client_data_json", "is not WebAuthn client data")
+	}
+	if data.Type != kind {
+		return nil, twirp.InvalidArgumentError("client_data_json", "is not from "+kind)
+	}
+	allowed := false
+	for _, origin := range us.WebAuthn.Origins {
+		allowed = allowed || origin == data.Origin
+	}
+	if !allowed {
+		return nil, twirp.NewError(twirp.PermissionDenied, "origin not allowed")
+	}
+
+	challenge, err := us.Store.TakeLoginChallenge(hashSecretToken(data.Challenge))
+	if err == ErrNotFound {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid challenge")
+	} else if err != nil {
+		return nil, err
+	}
+	if challenge.Kind != kind {
+		return nil, twirp.NewError(twirp.PermissionDenied, "invalid challenge")
+	}
+	if us.Now().Unix() >= challenge.ExpiresAt {
+		return nil, twirp.NewError(twirp.PermissionDenied, "challenge expired")
+	}
+	return challenge, nil
+}
+
+// parseAttestationObject returns the authenticator data of an attestation
+// object
+func parseAttestationObject(data []byte) ([]byte, error) {
+	decoded, rest, err := decodeCBOR(data)
+	object, ok := decoded.(map[interface{}]interface{})
+	if err != nil || !ok || len(rest) != 0 {
+		return nil, twirp.InvalidArgumentError("attestation_object", "is not CBOR encoded")
+	}
+	authData, ok := object["authData"].([]byte)
+	if !ok {
+		return nil, twirp.InvalidArgumentError("attestation_object", "has no authData")
+	}
+	return authData, nil
+}
+
+// authenticatorData is the parsed authenticator data of a ceremony
+type authenticatorData struct {
+	signCount    uint32
+	credentialID []byte // only set for attestations
+	publicKey    []byte // COSE_Key, only set for attestations
+}
+
+// parseAuthenticatorData parses and checks authenticator data: it must be for
+// our RP ID with the user present and verified
+func (us *userService) parseAuthenticatorData(data []byte) (*authenticatorData, error) {
+	invalid := twirp.InvalidArgumentError("authenticator_data", "is malformed")
+	if len(data) < 37 {
+		return nil, invalid
+	}
+	rpIDHash := sha256.Sum256([]byte(us.WebAuthn.RPID))
+	if !bytes.Equal(data[:32], rpIDHash[:]) {
+		return nil, twirp.NewError(twirp.PermissionDenied, "credential is for another relying party")
+	}
+	flags := data[32]
+	if flags&authDataUserPresent == 0 || flags&authDataUserVerified == 0 {
+		return nil, twirp.NewError(twirp.PermissionDenied, "user was not verified by the authenticator")
+	}
+	parsed := &authenticatorData{signCount: binary.BigEndian.Uint32(data[33:37])}
+	if flags&authDataAttested == 0 {
+		return parsed, nil
+	}
+
+	// aaguid | credential id length | credential id | COSE_Key
+	rest := data[37:]
+	if len(rest) < 18 {
+		return nil, invalid
+	}
+	idLength := int(binary.BigEndian.Uint16(rest[16:18]))
+	rest = rest[18:]
+	if idLength == 0 || len(rest) < idLength {
+		return nil, invalid
+	}
+	parsed.credentialID = append([]byte(nil), rest[:idLength]...)
+	rest = rest[idLength:]
+
+	_, after, err := decodeCBOR(rest)
+	if err != nil {
+		return nil, invalid
+	}
+	parsed.publicKey = append([]byte(nil), rest[:len(rest)-len(after)]...)
+	if _, err := parseCOSEKey(parsed.publicKey); err != nil {
+		return nil, twirp.InvalidArgumentError("attestation_object", "has an unsupported public key, only ES256 and RS256 are supported")
+	}
+	return parsed, nil
+}
+
+// parseCOSEKey decodes an ES256 or RS256 COSE_Key
+func parseCOSEKey(data []byte) (crypto.PublicKey, error) {
+	decoded, _, err := decodeCBOR(data)
+	if err != nil {
+		return nil, err
+	}
+	key, ok := decoded.(map[interface{}]interface{})
+	if !ok {
+		return nil, errCBOR
+	}
+
+	kty, _ := key[int64(1)].(int64)
+	alg, _ := key[int64(3)].(int64)
+	switch {
+	case kty == 2 && alg == coseES256:
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		y, _ := key[int64(-3)].([]byte)
+		if crv != 1 || len(x) != 32 || len(y) != 32 {
+			return nil, errCBOR
+		}
+		public := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !public.Curve.IsOnCurve(public.X, public.Y) {
+			return nil, errCBOR
+		}
+		return public, nil
+	case kty == 3 && alg == coseRS256:
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if len(n) < 256 || len(e) == 0 || len(e) > 4 {
+			return nil, errCBOR
+		}
+		exponent := 0
+		for _, b := range e {
+			exponent = exponent<<8 | int(b)
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}, nil
+	}
+	return nil, errCBOR
+}
+
+// verifyCOSESignature checks a WebAuthn assertion signature over signed
+func verifyCOSESignature(coseKey, signed, signature []byte) bool {
+	public, err := parseCOSEKey(coseKey)
+	if err != nil {
+		return false
+	}
+	digest := sha256.Sum256(signed)
+	switch public := public.(type) {
+	case *ecdsa.PublicKey:
+		return ecdsa.VerifyASN1(public, digest[:], signature)
+	case *rsa.PublicKey:
+		return rsa.VerifyPKCS1v15(public, crypto.SHA256, digest[:], signature) == nil
+	}
+	return false
+}
+
+func findWebAuthnCredential(user *pb.PrivateUser, id []byte) *pb.PrivateWebAuthnCredential {
+	for _, credential := range user.WebauthnCredentials {
+		if bytes.Equal(credential.Id, id) {
+			return credential
+		}
+	}
+	return nil
+}
+
+func publicWebAuthnCredential(credential *pb.PrivateWebAuthnCredential) *pb.WebAuthnCredential {
+	return &pb.WebAuthnCredential{
+		Id:         base64URL(credential.Id),
+		Name:       credential.Name,
+		CreatedAt:  credential.CreatedAt,
+		LastUsedAt: credential.LastUsedAt,
+	}
+}
+
+func base64URL(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -6,23 +6,190 @@ import (
 	"net/http"
 	"os"
 	"fmt"
+	"flag"
+	"time"
+	"strings"
+	"expvar"
 )
 
 func main() {
-	server, err := usersservice.New("./.usersservice.db")
+	passwordReport := flag.Bool("password-report", false, "print how many users are on each password hash scheme and exit")
+	exportAudit := flag.String("export-audit", "", "write audit events as JSON Lines to this file, - for stdout, and exit")
+	auditSince := flag.Duration("audit-since", 0, "with -export-audit, only export events from this long ago on, ex: 168h")
+	auditAction := flag.String("audit-action", "", "with -export-audit, only export this action, ex: login")
+	auditSubject := flag.String("audit-subject", "", "with -export-audit, only export events done to this username")
+	store := flag.String("store", "leveldb", "storage backend: leveldb, sqlite or memory")
+	dbPath := flag.String("db", usersservice.DefaultDBPath, "database path for the leveldb or sqlite store")
+	rolesFile := flag.String("roles", "", "JSON file defining the roles that can be granted, defaults to a single admin role")
+	notifyFile := flag.String("notify-file", "", "append notifications such as password reset tokens to this file instead of stderr")
+	policyFile := flag.String("policy", "", "JSON file overriding the default username and password policy")
+	breachIndex := flag.String("breach-index", "", "reject new passwords found in this breach index")
+	buildBreachIndex := flag.String("build-breach-index", "", "build the -breach-index file from this Pwned Passwords file or directory of range files and exit")
+	secretKeyFile := flag.String("secret-key-file", "", "file holding the hex encoded 32 byte key that seals TOTP secrets, two-factor enrollment is off without it")
+	webAuthnRPID := flag.String("webauthn-rp-id", "", "domain passkeys are registered for, ex: example.com, WebAuthn is off without it")
+	webAuthnOrigins := flag.String("webauthn-origins", "", "comma separated origins WebAuthn ceremonies may come from, ex: https://example.com")
+	hideUsernames := flag.Bool("hide-usernames", false, "do not let Login and User tell whether a username exists, User then needs a session")
+	trustedProxies := flag.String("trusted-proxies", "", "comma separated proxy addresses or networks whose X-Forwarded-For header is believed, ex: 10.0.0.0/8")
+	metricsAddr := flag.String("metrics-addr", "", "serve expvar metrics at /debug/vars on this address, ex: localhost:9090")
+	flag.Parse()
+
+	var storeOpt usersservice.Option
+	switch *store {
+	case "leveldb":
+		storeOpt = usersservice.WithLevelDB(*dbPath)
+	case "sqlite":
+		storeOpt = usersservice.WithSQLite(*dbPath)
+	case "memory":
+		storeOpt = usersservice.WithMemoryStore()
+	default:
+		fmt.Fprintf(os.Stderr, "unknown store %q\n", *store)
+		os.Exit(2)
+	}
+
+	if *buildBreachIndex != "" {
+		if *breachIndex == "" {
+			fmt.Fprintln(os.Stderr, "-build-breach-index needs -breach-index")
+			os.Exit(2)
+		}
+		count, err := usersservice.BuildBreachIndex(*buildBreachIndex, *breachIndex)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("indexed %d hashes into %s\n", count, *breachIndex)
+		return
+	}
+
+	opts := []usersservice.Option{storeOpt}
+	if *notifyFile != "" {
+		notifier, err := usersservice.NewFileNotifier(*notifyFile)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, usersservice.WithNotifier(notifier))
+	}
+
+	if *rolesFile != "" {
+		roles, err := usersservice.LoadRoles(*rolesFile)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, usersservice.WithRoles(roles))
+	}
+
+	if *policyFile != "" {
+		policy, err := usersservice.LoadPolicy(*policyFile)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, usersservice.WithPolicy(policy))
+	}
+
+	if *breachIndex != "" {
+		opts = append(opts, usersservice.WithBreachIndex(*breachIndex))
+	}
+
+	if *secretKeyFile != "" {
+		key, err := usersservice.LoadSecretKey(*secretKeyFile)
+		if err != nil {
+			panic(err)
+		}
+		opts = append(opts, usersservice.WithSecretKey(key))
+	}
+
+	if *webAuthnRPID != "" {
+		opts = append(opts, usersservice.WithWebAuthn(&usersservice.WebAuthn{
+			RPID:    *webAuthnRPID,
+			Origins: strings.Split(*webAuthnOrigins, ","),
+		}))
+	}
+
+	server, err := usersservice.New(opts...)
+	if err != nil {
+		panic(err)
+	}
+	defer server.Close()
+
+	if *passwordReport {
+		report, err := server.PasswordReport()
+		if err != nil {
+			panic(err)
+		}
+		fmt.Printf("users: %d\n", report.Total)
+		fmt.Printf("legacy sha256: %d\n", report.Legacy)
+		for scheme, count := range report.Schemes {
+			fmt.Printf("%s: %d\n", scheme, count)
+		}
+		return
+	}
+
+	if *exportAudit != "" {
+		filter := &pb.AuditFilter{Action: *auditAction, Subject: *auditSubject}
+		if *auditSince > 0 {
+			filter.Since = time.Now().Add(-*auditSince).Unix()
+		}
+		out := os.Stdout
+		if *exportAudit != "-" {
+			out, err = os.Create(*exportAudit)
+			if err != nil {
+				panic(err)
+			}
+			defer out.Close()
+		}
+		count, err := server.ExportAuditEvents(out, filter)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Fprintf(os.Stderr, "exported %d audit events\n", count)
+		return
+	}
+
+	renamed, conflicts, err := server.NormalizeUsernames()
 	if err != nil {
 		panic(err)
 	}
+	if renamed > 0 {
+		fmt.Fprintf(os.Stderr, "normalized %d usernames\n", renamed)
+	}
+	for _, username := range conflicts {
+		fmt.Fprintf(os.Stderr, "username %s differs from another only by case, rename one of them\n", username)
+	}
 
+	// ADMINS is a comma separated list of usernames granted the admin role at
+	// startup, a user who has not registered yet is granted it next start
+	admins := []string{}
+	for _, username := range strings.Split(os.Getenv("ADMINS"), ",") {
+		if username != "" {
+			admins = append(admins, username)
+		}
+	}
+	missing, err := server.GrantAdmins(admins)
+	if err != nil {
+		panic(err)
+	}
+	for _, username := range missing {
+		fmt.Fprintf(os.Stderr, "ADMINS: %s not found, restart once they have registered\n", username)
+	}
+	server.HideUsernames = *hideUsernames
 
+	stopReaper := server.StartSessionReaper(10 * time.Minute)
+	defer stopReaper()
 
 	var bind = ":8080"
 	if port := os.Getenv("PORT"); port != "" {
 		bind = ":"+port
 	}
 
+	if *metricsAddr != "" {
+		go func() {
+			panic(http.ListenAndServe(*metricsAddr, expvar.Handler()))
+		}()
+	}
 
-	handler := pb.NewUsersServer(server, nil)
+	trusted, err := usersservice.ParseTrustedProxies(*trustedProxies)
+	if err != nil {
+		panic(err)
+	}
+	handler := usersservice.WithProxiedClientInfo(pb.NewUsersServer(server, server.ServerHooks()), trusted)
 	fmt.Printf("Listening on %s\n", bind)
 	err = http.ListenAndServe(bind, handler)
 	if err != nil {
@@ -16,9 +16,86 @@ It has these top-level messages:
 	UserResp
 	CurrentUserReq
 	CurrentUserResp
+	LogoutReq
+	LogoutResp
+	LogoutAllReq
+	LogoutAllResp
+	RevokeSessionReq
+	RevokeSessionResp
+	ListSessionsReq
+	ListSessionsResp
+	ChangePasswordReq
+	ChangePasswordResp
+	RequestPasswordResetReq
+	RequestPasswordResetResp
+	ResetPasswordReq
+	ResetPasswordResp
+	VerifyEmailReq
+	VerifyEmailResp
+	ResendVerificationReq
+	ResendVerificationResp
+	UpdateProfileReq
+	UpdateProfileResp
+	GrantRoleReq
+	GrantRoleResp
+	RevokeRoleReq
+	RevokeRoleResp
+	AuthorizeReq
+	AuthorizeResp
+	CreateGroupReq
+	CreateGroupResp
+	AddMemberReq
+	AddMemberResp
+	RemoveMemberReq
+	RemoveMemberResp
+	ListGroupMembersReq
+	ListGroupMembersResp
+	ListUserGroupsReq
+	ListUserGroupsResp
+	ListUsersReq
+	ListUsersResp
+	DisableUserReq
+	DisableUserResp
+	DeleteUserReq
+	DeleteUserResp
+	RenameUserReq
+	RenameUserResp
+	EnrollTOTPReq
+	EnrollTOTPResp
+	ConfirmTOTPReq
+	ConfirmTOTPResp
+	DisableTOTPReq
+	DisableTOTPResp
+	VerifySecondFactorReq
+	VerifySecondFactorResp
+	BeginWebAuthnRegistrationReq
+	BeginWebAuthnRegistrationResp
+	FinishWebAuthnRegistrationReq
+	FinishWebAuthnRegistrationResp
+	BeginWebAuthnLoginReq
+	BeginWebAuthnLoginResp
+	FinishWebAuthnLoginReq
+	FinishWebAuthnLoginResp
+	ListAuditEventsReq
+	ListAuditEventsResp
 	User
+	WebAuthnCredential
+	Group
+	Member
+	RoleGrant
+	Profile
+	AuditEvent
+	AuditFilter
 	Session
+	SessionInfo
 	PrivateUser
+	PrivateWebAuthnCredential
+	PrivateSession
+	PrivateRenamedUser
+	PrivateResetToken
+	PrivateLoginChallenge
+	PrivateVerificationToken
+	PrivateGroup
 */
 package users
 
@@ -43,6 +120,7 @@ const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 type RegisterReq struct {
 	Username string `protobuf:"bytes,1,opt,name=username" json:"username,omitempty"`
 	Password string `protobuf:"bytes,2,opt,name=password" json:"password,omitempty"`
+	Email    string `protobuf:"bytes,3,opt,name=email" json:"email,omitempty"`
 }
 
 func (m *RegisterReq) Reset()                    { *m = RegisterReq{} }
@@ -64,6 +142,13 @@ func (m *RegisterReq) GetPassword() string {
 	return ""
 }
 
+func (m *RegisterReq) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
 type RegisterResp struct {
 	User *User `protobuf:"bytes,1,opt,name=user" json:"user,omitempty"`
 }
@@ -108,7 +193,8 @@ func (m *LoginReq) GetPassword() string {
 }
 
 type LoginResp struct {
-	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Session               *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	SecondFactorChallenge string   `protobuf:"bytes,2,opt,name=second_factor_challenge,json=secondFactorChallenge" json:"secondFactorChallenge,omitempty"`
 }
 
 func (m *LoginResp) Reset()                    { *m = LoginResp{} }
@@ -123,11 +209,19 @@ func (m *LoginResp) GetSession() *Session {
 	return nil
 }
 
+func (m *LoginResp) GetSecondFactorChallenge() string {
+	if m != nil {
+		return m.SecondFactorChallenge
+	}
+	return ""
+}
+
 // /////////////////////////////////////////////////////////////////////////////
 // User() rpc
 // /////////////////////////////////////////////////////////////////////////////
 type UserReq struct {
-	Username string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	Session  *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Username string   `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
 }
 
 func (m *UserReq) Reset()                    { *m = UserReq{} }
@@ -135,6 +229,13 @@ func (m *UserReq) String() string            { return proto.CompactTextString(m)
 func (*UserReq) ProtoMessage()               {}
 func (*UserReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{4} }
 
+func (m *UserReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
 func (m *UserReq) GetUsername() string {
 	if m != nil {
 		return m.Username
@@ -193,74 +294,2439 @@ func (m *CurrentUserResp) GetUser() *User {
 	return nil
 }
 
-// User is the public user message
-type User struct {
-	Username string `protobuf:"bytes,1,opt,name=username" json:"username,omitempty"`
+// /////////////////////////////////////////////////////////////////////////////
+// Logout() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type LogoutReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
 }
 
-func (m *User) Reset()                    { *m = User{} }
-func (m *User) String() string            { return proto.CompactTextString(m) }
-func (*User) ProtoMessage()               {}
-func (*User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
+func (m *LogoutReq) Reset()                    { *m = LogoutReq{} }
+func (m *LogoutReq) String() string            { return proto.CompactTextString(m) }
+func (*LogoutReq) ProtoMessage()               {}
+func (*LogoutReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{8} }
 
-func (m *User) GetUsername() string {
+func (m *LogoutReq) GetSession() *Session {
 	if m != nil {
-		return m.Username
+		return m.Session
 	}
-	return ""
+	return nil
 }
 
-// Session is a message that represents a session. Use it as your key
-// for making authenticated rpc calls
-type Session struct {
-	Token    string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
-	Username string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+type LogoutResp struct {
 }
 
-func (m *Session) Reset()                    { *m = Session{} }
-func (m *Session) String() string            { return proto.CompactTextString(m) }
-func (*Session) ProtoMessage()               {}
-func (*Session) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
+func (m *LogoutResp) Reset()                    { *m = LogoutResp{} }
+func (m *LogoutResp) String() string            { return proto.CompactTextString(m) }
+func (*LogoutResp) ProtoMessage()               {}
+func (*LogoutResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{9} }
 
-func (m *Session) GetToken() string {
+// /////////////////////////////////////////////////////////////////////////////
+// LogoutAll() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type LogoutAllReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+}
+
+func (m *LogoutAllReq) Reset()                    { *m = LogoutAllReq{} }
+func (m *LogoutAllReq) String() string            { return proto.CompactTextString(m) }
+func (*LogoutAllReq) ProtoMessage()               {}
+func (*LogoutAllReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+
+func (m *LogoutAllReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+type LogoutAllResp struct {
+	Revoked int32 `protobuf:"varint,1,opt,name=revoked" json:"revoked,omitempty"`
+}
+
+func (m *LogoutAllResp) Reset()                    { *m = LogoutAllResp{} }
+func (m *LogoutAllResp) String() string            { return proto.CompactTextString(m) }
+func (*LogoutAllResp) ProtoMessage()               {}
+func (*LogoutAllResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{11} }
+
+func (m *LogoutAllResp) GetRevoked() int32 {
+	if m != nil {
+		return m.Revoked
+	}
+	return 0
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// RevokeSession() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type RevokeSessionReq struct {
+	Session   *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Token     string   `protobuf:"bytes,2,opt,name=token" json:"token,omitempty"`
+	SessionId string   `protobuf:"bytes,3,opt,name=session_id,json=sessionId" json:"sessionId,omitempty"`
+}
+
+func (m *RevokeSessionReq) Reset()                    { *m = RevokeSessionReq{} }
+func (m *RevokeSessionReq) String() string            { return proto.CompactTextString(m) }
+func (*RevokeSessionReq) ProtoMessage()               {}
+func (*RevokeSessionReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{12} }
+
+func (m *RevokeSessionReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *RevokeSessionReq) GetToken() string {
 	if m != nil {
 		return m.Token
 	}
 	return ""
 }
 
-func (m *Session) GetUsername() string {
+func (m *RevokeSessionReq) GetSessionId() string {
 	if m != nil {
-		return m.Username
+		return m.SessionId
 	}
 	return ""
 }
 
-// PrivateUser is the message that is stored in the DB, do not publiclly expose it.
-type PrivateUser struct {
-	Username       string `protobuf:"bytes,1,opt,name=username" json:"username,omitempty"`
-	PasswordSha256 []byte `protobuf:"bytes,2,opt,name=passwordSha256,proto3" json:"passwordSha256,omitempty"`
+type RevokeSessionResp struct {
 }
 
-func (m *PrivateUser) Reset()                    { *m = PrivateUser{} }
-func (m *PrivateUser) String() string            { return proto.CompactTextString(m) }
-func (*PrivateUser) ProtoMessage()               {}
-func (*PrivateUser) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{10} }
+func (m *RevokeSessionResp) Reset()                    { *m = RevokeSessionResp{} }
+func (m *RevokeSessionResp) String() string            { return proto.CompactTextString(m) }
+func (*RevokeSessionResp) ProtoMessage()               {}
+func (*RevokeSessionResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{13} }
 
-func (m *PrivateUser) GetUsername() string {
+// /////////////////////////////////////////////////////////////////////////////
+// ListSessions() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type ListSessionsReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+}
+
+func (m *ListSessionsReq) Reset()                    { *m = ListSessionsReq{} }
+func (m *ListSessionsReq) String() string            { return proto.CompactTextString(m) }
+func (*ListSessionsReq) ProtoMessage()               {}
+func (*ListSessionsReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{14} }
+
+func (m *ListSessionsReq) GetSession() *Session {
 	if m != nil {
-		return m.Username
+		return m.Session
+	}
+	return nil
+}
+
+type ListSessionsResp struct {
+	Sessions []*SessionInfo `protobuf:"bytes,1,rep,name=sessions" json:"sessions,omitempty"`
+}
+
+func (m *ListSessionsResp) Reset()                    { *m = ListSessionsResp{} }
+func (m *ListSessionsResp) String() string            { return proto.CompactTextString(m) }
+func (*ListSessionsResp) ProtoMessage()               {}
+func (*ListSessionsResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{15} }
+
+func (m *ListSessionsResp) GetSessions() []*SessionInfo {
+	if m != nil {
+		return m.Sessions
+	}
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// ChangePassword() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type ChangePasswordReq struct {
+	Session             *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	OldPassword         string   `protobuf:"bytes,2,opt,name=old_password,json=oldPassword" json:"oldPassword,omitempty"`
+	NewPassword         string   `protobuf:"bytes,3,opt,name=new_password,json=newPassword" json:"newPassword,omitempty"`
+	RevokeOtherSessions bool     `protobuf:"varint,4,opt,name=revoke_other_sessions,json=revokeOtherSessions" json:"revokeOtherSessions,omitempty"`
+}
+
+func (m *ChangePasswordReq) Reset()                    { *m = ChangePasswordReq{} }
+func (m *ChangePasswordReq) String() string            { return proto.CompactTextString(m) }
+func (*ChangePasswordReq) ProtoMessage()               {}
+func (*ChangePasswordReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{16} }
+
+func (m *ChangePasswordReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *ChangePasswordReq) GetOldPassword() string {
+	if m != nil {
+		return m.OldPassword
 	}
 	return ""
 }
 
-func (m *PrivateUser) GetPasswordSha256() []byte {
+func (m *ChangePasswordReq) GetNewPassword() string {
 	if m != nil {
-		return m.PasswordSha256
+		return m.NewPassword
+	}
+	return ""
+}
+
+func (m *ChangePasswordReq) GetRevokeOtherSessions() bool {
+	if m != nil {
+		return m.RevokeOtherSessions
+	}
+	return false
+}
+
+type ChangePasswordResp struct {
+	Revoked int32 `protobuf:"varint,1,opt,name=revoked" json:"revoked,omitempty"`
+}
+
+func (m *ChangePasswordResp) Reset()                    { *m = ChangePasswordResp{} }
+func (m *ChangePasswordResp) String() string            { return proto.CompactTextString(m) }
+func (*ChangePasswordResp) ProtoMessage()               {}
+func (*ChangePasswordResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{17} }
+
+func (m *ChangePasswordResp) GetRevoked() int32 {
+	if m != nil {
+		return m.Revoked
+	}
+	return 0
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// RequestPasswordReset() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type RequestPasswordResetReq struct {
+	UsernameOrEmail string `protobuf:"bytes,1,opt,name=username_or_email,json=usernameOrEmail" json:"usernameOrEmail,omitempty"`
+}
+
+func (m *RequestPasswordResetReq) Reset()                    { *m = RequestPasswordResetReq{} }
+func (m *RequestPasswordResetReq) String() string            { return proto.CompactTextString(m) }
+func (*RequestPasswordResetReq) ProtoMessage()               {}
+func (*RequestPasswordResetReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{18} }
+
+func (m *RequestPasswordResetReq) GetUsernameOrEmail() string {
+	if m != nil {
+		return m.UsernameOrEmail
+	}
+	return ""
+}
+
+type RequestPasswordResetResp struct {
+}
+
+func (m *RequestPasswordResetResp) Reset()                    { *m = RequestPasswordResetResp{} }
+func (m *RequestPasswordResetResp) String() string            { return proto.CompactTextString(m) }
+func (*RequestPasswordResetResp) ProtoMessage()               {}
+func (*RequestPasswordResetResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{19} }
+
+// /////////////////////////////////////////////////////////////////////////////
+// ResetPassword() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type ResetPasswordReq struct {
+	ResetToken  string `protobuf:"bytes,1,opt,name=reset_token,json=resetToken" json:"resetToken,omitempty"`
+	NewPassword string `protobuf:"bytes,2,opt,name=new_password,json=newPassword" json:"newPassword,omitempty"`
+}
+
+func (m *ResetPasswordReq) Reset()                    { *m = ResetPasswordReq{} }
+func (m *ResetPasswordReq) String() string            { return proto.CompactTextString(m) }
+func (*ResetPasswordReq) ProtoMessage()               {}
+func (*ResetPasswordReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{20} }
+
+func (m *ResetPasswordReq) GetResetToken() string {
+	if m != nil {
+		return m.ResetToken
+	}
+	return ""
+}
+
+func (m *ResetPasswordReq) GetNewPassword() string {
+	if m != nil {
+		return m.NewPassword
+	}
+	return ""
+}
+
+type ResetPasswordResp struct {
+	Revoked int32 `protobuf:"varint,1,opt,name=revoked" json:"revoked,omitempty"`
+}
+
+func (m *ResetPasswordResp) Reset()                    { *m = ResetPasswordResp{} }
+func (m *ResetPasswordResp) String() string            { return proto.CompactTextString(m) }
+func (*ResetPasswordResp) ProtoMessage()               {}
+func (*ResetPasswordResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{21} }
+
+func (m *ResetPasswordResp) GetRevoked() int32 {
+	if m != nil {
+		return m.Revoked
+	}
+	return 0
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// VerifyEmail() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type VerifyEmailReq struct {
+	Token string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+}
+
+func (m *VerifyEmailReq) Reset()                    { *m = VerifyEmailReq{} }
+func (m *VerifyEmailReq) String() string            { return proto.CompactTextString(m) }
+func (*VerifyEmailReq) ProtoMessage()               {}
+func (*VerifyEmailReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{22} }
+
+func (m *VerifyEmailReq) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+type VerifyEmailResp struct {
+	User *User `protobuf:"bytes,1,opt,name=user" json:"user,omitempty"`
+}
+
+func (m *VerifyEmailResp) Reset()                    { *m = VerifyEmailResp{} }
+func (m *VerifyEmailResp) String() string            { return proto.CompactTextString(m) }
+func (*VerifyEmailResp) ProtoMessage()               {}
+func (*VerifyEmailResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{23} }
+
+func (m *VerifyEmailResp) GetUser() *User {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// ResendVerification() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type ResendVerificationReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+}
+
+func (m *ResendVerificationReq) Reset()                    { *m = ResendVerificationReq{} }
+func (m *ResendVerificationReq) String() string            { return proto.CompactTextString(m) }
+func (*ResendVerificationReq) ProtoMessage()               {}
+func (*ResendVerificationReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{24} }
+
+func (m *ResendVerificationReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+type ResendVerificationResp struct {
+}
+
+func (m *ResendVerificationResp) Reset()                    { *m = ResendVerificationResp{} }
+func (m *ResendVerificationResp) String() string            { return proto.CompactTextString(m) }
+func (*ResendVerificationResp) ProtoMessage()               {}
+func (*ResendVerificationResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{25} }
+
+// /////////////////////////////////////////////////////////////////////////////
+// UpdateProfile() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type UpdateProfileReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Profile *Profile `protobuf:"bytes,2,opt,name=profile" json:"profile,omitempty"`
+	// The profile fields to update: display_name, avatar_url, locale, timezone,
+	//  attributes (replaces every attribute) or attributes.<key> (sets one
+	//  attribute, or removes it if profile.attributes does not have the key).
+	//  Must be non-empty.
+	UpdateMask []string `protobuf:"bytes,3,rep,name=update_mask,json=updateMask" json:"updateMask,omitempty"`
+}
+
+func (m *UpdateProfileReq) Reset()                    { *m = UpdateProfileReq{} }
+func (m *UpdateProfileReq) String() string            { return proto.CompactTextString(m) }
+func (*UpdateProfileReq) ProtoMessage()               {}
+func (*UpdateProfileReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{26} }
+
+func (m *UpdateProfileReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *UpdateProfileReq) GetProfile() *Profile {
+	if m != nil {
+		return m.Profile
+	}
+	return nil
+}
+
+func (m *UpdateProfileReq) GetUpdateMask() []string {
+	if m != nil {
+		return m.UpdateMask
+	}
+	return nil
+}
+
+type UpdateProfileResp struct {
+	User *User `protobuf:"bytes,1,opt,name=user" json:"user,omitempty"`
+}
+
+func (m *UpdateProfileResp) Reset()                    { *m = UpdateProfileResp{} }
+func (m *UpdateProfileResp) String() string            { return proto.CompactTextString(m) }
+func (*UpdateProfileResp) ProtoMessage()               {}
+func (*UpdateProfileResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{27} }
+
+func (m *UpdateProfileResp) GetUser() *User {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// GrantRole() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type GrantRoleReq struct {
+	Session  *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Username string   `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	Role     string   `protobuf:"bytes,3,opt,name=role" json:"role,omitempty"`
+	Resource string   `protobuf:"bytes,4,opt,name=resource" json:"resource,omitempty"`
+	Group    string   `protobuf:"bytes,5,opt,name=group" json:"group,omitempty"`
+}
+
+func (m *GrantRoleReq) Reset()                    { *m = GrantRoleReq{} }
+func (m *GrantRoleReq) String() string            { return proto.CompactTextString(m) }
+func (*GrantRoleReq) ProtoMessage()               {}
+func (*GrantRoleReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{28} }
+
+func (m *GrantRoleReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
 	}
 	return nil
 }
 
+func (m *GrantRoleReq) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *GrantRoleReq) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *GrantRoleReq) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+func (m *GrantRoleReq) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+type GrantRoleResp struct {
+}
+
+func (m *GrantRoleResp) Reset()                    { *m = GrantRoleResp{} }
+func (m *GrantRoleResp) String() string            { return proto.CompactTextString(m) }
+func (*GrantRoleResp) ProtoMessage()               {}
+func (*GrantRoleResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{29} }
+
+// /////////////////////////////////////////////////////////////////////////////
+// RevokeRole() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type RevokeRoleReq struct {
+	Session  *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Username string   `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	Role     string   `protobuf:"bytes,3,opt,name=role" json:"role,omitempty"`
+	Resource string   `protobuf:"bytes,4,opt,name=resource" json:"resource,omitempty"`
+	Group    string   `protobuf:"bytes,5,opt,name=group" json:"group,omitempty"`
+}
+
+func (m *RevokeRoleReq) Reset()                    { *m = RevokeRoleReq{} }
+func (m *RevokeRoleReq) String() string            { return proto.CompactTextString(m) }
+func (*RevokeRoleReq) ProtoMessage()               {}
+func (*RevokeRoleReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{30} }
+
+func (m *RevokeRoleReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *RevokeRoleReq) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *RevokeRoleReq) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *RevokeRoleReq) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+func (m *RevokeRoleReq) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+type RevokeRoleResp struct {
+}
+
+func (m *RevokeRoleResp) Reset()                    { *m = RevokeRoleResp{} }
+func (m *RevokeRoleResp) String() string            { return proto.CompactTextString(m) }
+func (*RevokeRoleResp) ProtoMessage()               {}
+func (*RevokeRoleResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{31} }
+
+// /////////////////////////////////////////////////////////////////////////////
+// Authorize() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type AuthorizeReq struct {
+	Session    *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Permission string   `protobuf:"bytes,2,opt,name=permission" json:"permission,omitempty"`
+	Resource   string   `protobuf:"bytes,3,opt,name=resource" json:"resource,omitempty"`
+}
+
+func (m *AuthorizeReq) Reset()                    { *m = AuthorizeReq{} }
+func (m *AuthorizeReq) String() string            { return proto.CompactTextString(m) }
+func (*AuthorizeReq) ProtoMessage()               {}
+func (*AuthorizeReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{32} }
+
+func (m *AuthorizeReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *AuthorizeReq) GetPermission() string {
+	if m != nil {
+		return m.Permission
+	}
+	return ""
+}
+
+func (m *AuthorizeReq) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+type AuthorizeResp struct {
+	Allowed  bool   `protobuf:"varint,1,opt,name=allowed" json:"allowed,omitempty"`
+	Reason   string `protobuf:"bytes,2,opt,name=reason" json:"reason,omitempty"`
+	Username string `protobuf:"bytes,3,opt,name=username" json:"username,omitempty"`
+}
+
+func (m *AuthorizeResp) Reset()                    { *m = AuthorizeResp{} }
+func (m *AuthorizeResp) String() string            { return proto.CompactTextString(m) }
+func (*AuthorizeResp) ProtoMessage()               {}
+func (*AuthorizeResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{33} }
+
+func (m *AuthorizeResp) GetAllowed() bool {
+	if m != nil {
+		return m.Allowed
+	}
+	return false
+}
+
+func (m *AuthorizeResp) GetReason() string {
+	if m != nil {
+		return m.Reason
+	}
+	return ""
+}
+
+func (m *AuthorizeResp) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// CreateGroup() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type CreateGroupReq struct {
+	Session     *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Name        string   `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Description string   `protobuf:"bytes,3,opt,name=description" json:"description,omitempty"`
+}
+
+func (m *CreateGroupReq) Reset()                    { *m = CreateGroupReq{} }
+func (m *CreateGroupReq) String() string            { return proto.CompactTextString(m) }
+func (*CreateGroupReq) ProtoMessage()               {}
+func (*CreateGroupReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{34} }
+
+func (m *CreateGroupReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *CreateGroupReq) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *CreateGroupReq) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+type CreateGroupResp struct {
+	Group *Group `protobuf:"bytes,1,opt,name=group" json:"group,omitempty"`
+}
+
+func (m *CreateGroupResp) Reset()                    { *m = CreateGroupResp{} }
+func (m *CreateGroupResp) String() string            { return proto.CompactTextString(m) }
+func (*CreateGroupResp) ProtoMessage()               {}
+func (*CreateGroupResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{35} }
+
+func (m *CreateGroupResp) GetGroup() *Group {
+	if m != nil {
+		return m.Group
+	}
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// AddMember() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type AddMemberReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Group   string   `protobuf:"bytes,2,opt,name=group" json:"group,omitempty"`
+	Member  *Member  `protobuf:"bytes,3,opt,name=member" json:"member,omitempty"`
+}
+
+func (m *AddMemberReq) Reset()                    { *m = AddMemberReq{} }
+func (m *AddMemberReq) String() string            { return proto.CompactTextString(m) }
+func (*AddMemberReq) ProtoMessage()               {}
+func (*AddMemberReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{36} }
+
+func (m *AddMemberReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *AddMemberReq) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *AddMemberReq) GetMember() *Member {
+	if m != nil {
+		return m.Member
+	}
+	return nil
+}
+
+type AddMemberResp struct {
+}
+
+func (m *AddMemberResp) Reset()                    { *m = AddMemberResp{} }
+func (m *AddMemberResp) String() string            { return proto.CompactTextString(m) }
+func (*AddMemberResp) ProtoMessage()               {}
+func (*AddMemberResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{37} }
+
+// /////////////////////////////////////////////////////////////////////////////
+// RemoveMember() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type RemoveMemberReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Group   string   `protobuf:"bytes,2,opt,name=group" json:"group,omitempty"`
+	Member  *Member  `protobuf:"bytes,3,opt,name=member" json:"member,omitempty"`
+}
+
+func (m *RemoveMemberReq) Reset()                    { *m = RemoveMemberReq{} }
+func (m *RemoveMemberReq) String() string            { return proto.CompactTextString(m) }
+func (*RemoveMemberReq) ProtoMessage()               {}
+func (*RemoveMemberReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{38} }
+
+func (m *RemoveMemberReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *RemoveMemberReq) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *RemoveMemberReq) GetMember() *Member {
+	if m != nil {
+		return m.Member
+	}
+	return nil
+}
+
+type RemoveMemberResp struct {
+}
+
+func (m *RemoveMemberResp) Reset()                    { *m = RemoveMemberResp{} }
+func (m *RemoveMemberResp) String() string            { return proto.CompactTextString(m) }
+func (*RemoveMemberResp) ProtoMessage()               {}
+func (*RemoveMemberResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{39} }
+
+// /////////////////////////////////////////////////////////////////////////////
+// ListGroupMembers() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type ListGroupMembersReq struct {
+	Session    *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Group      string   `protobuf:"bytes,2,opt,name=group" json:"group,omitempty"`
+	Transitive bool     `protobuf:"varint,3,opt,name=transitive" json:"transitive,omitempty"`
+}
+
+func (m *ListGroupMembersReq) Reset()                    { *m = ListGroupMembersReq{} }
+func (m *ListGroupMembersReq) String() string            { return proto.CompactTextString(m) }
+func (*ListGroupMembersReq) ProtoMessage()               {}
+func (*ListGroupMembersReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{40} }
+
+func (m *ListGroupMembersReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *ListGroupMembersReq) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+func (m *ListGroupMembersReq) GetTransitive() bool {
+	if m != nil {
+		return m.Transitive
+	}
+	return false
+}
+
+type ListGroupMembersResp struct {
+	Members []*Member `protobuf:"bytes,1,rep,name=members" json:"members,omitempty"`
+}
+
+func (m *ListGroupMembersResp) Reset()                    { *m = ListGroupMembersResp{} }
+func (m *ListGroupMembersResp) String() string            { return proto.CompactTextString(m) }
+func (*ListGroupMembersResp) ProtoMessage()               {}
+func (*ListGroupMembersResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{41} }
+
+func (m *ListGroupMembersResp) GetMembers() []*Member {
+	if m != nil {
+		return m.Members
+	}
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// ListUserGroups() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type ListUserGroupsReq struct {
+	Session  *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Username string   `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+}
+
+func (m *ListUserGroupsReq) Reset()                    { *m = ListUserGroupsReq{} }
+func (m *ListUserGroupsReq) String() string            { return proto.CompactTextString(m) }
+func (*ListUserGroupsReq) ProtoMessage()               {}
+func (*ListUserGroupsReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{42} }
+
+func (m *ListUserGroupsReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *ListUserGroupsReq) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type ListUserGroupsResp struct {
+	Groups []string `protobuf:"bytes,1,rep,name=groups" json:"groups,omitempty"`
+}
+
+func (m *ListUserGroupsResp) Reset()                    { *m = ListUserGroupsResp{} }
+func (m *ListUserGroupsResp) String() string            { return proto.CompactTextString(m) }
+func (*ListUserGroupsResp) ProtoMessage()               {}
+func (*ListUserGroupsResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{43} }
+
+func (m *ListUserGroupsResp) GetGroups() []string {
+	if m != nil {
+		return m.Groups
+	}
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// ListUsers() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type ListUsersReq struct {
+	Session        *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	PageSize       int32    `protobuf:"varint,2,opt,name=page_size,json=pageSize" json:"pageSize,omitempty"`
+	PageToken      string   `protobuf:"bytes,3,opt,name=page_token,json=pageToken" json:"pageToken,omitempty"`
+	UsernamePrefix string   `protobuf:"bytes,4,opt,name=username_prefix,json=usernamePrefix" json:"usernamePrefix,omitempty"`
+}
+
+func (m *ListUsersReq) Reset()                    { *m = ListUsersReq{} }
+func (m *ListUsersReq) String() string            { return proto.CompactTextString(m) }
+func (*ListUsersReq) ProtoMessage()               {}
+func (*ListUsersReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{44} }
+
+func (m *ListUsersReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *ListUsersReq) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+func (m *ListUsersReq) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListUsersReq) GetUsernamePrefix() string {
+	if m != nil {
+		return m.UsernamePrefix
+	}
+	return ""
+}
+
+type ListUsersResp struct {
+	Users         []*User `protobuf:"bytes,1,rep,name=users" json:"users,omitempty"`
+	NextPageToken string  `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"nextPageToken,omitempty"`
+}
+
+func (m *ListUsersResp) Reset()                    { *m = ListUsersResp{} }
+func (m *ListUsersResp) String() string            { return proto.CompactTextString(m) }
+func (*ListUsersResp) ProtoMessage()               {}
+func (*ListUsersResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{45} }
+
+func (m *ListUsersResp) GetUsers() []*User {
+	if m != nil {
+		return m.Users
+	}
+	return nil
+}
+
+func (m *ListUsersResp) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// DisableUser() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type DisableUserReq struct {
+	Session  *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Username string   `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+}
+
+func (m *DisableUserReq) Reset()                    { *m = DisableUserReq{} }
+func (m *DisableUserReq) String() string            { return proto.CompactTextString(m) }
+func (*DisableUserReq) ProtoMessage()               {}
+func (*DisableUserReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{46} }
+
+func (m *DisableUserReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *DisableUserReq) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type DisableUserResp struct {
+	Revoked int32 `protobuf:"varint,1,opt,name=revoked" json:"revoked,omitempty"`
+}
+
+func (m *DisableUserResp) Reset()                    { *m = DisableUserResp{} }
+func (m *DisableUserResp) String() string            { return proto.CompactTextString(m) }
+func (*DisableUserResp) ProtoMessage()               {}
+func (*DisableUserResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{47} }
+
+func (m *DisableUserResp) GetRevoked() int32 {
+	if m != nil {
+		return m.Revoked
+	}
+	return 0
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// DeleteUser() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type DeleteUserReq struct {
+	Session  *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Username string   `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+}
+
+func (m *DeleteUserReq) Reset()                    { *m = DeleteUserReq{} }
+func (m *DeleteUserReq) String() string            { return proto.CompactTextString(m) }
+func (*DeleteUserReq) ProtoMessage()               {}
+func (*DeleteUserReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{48} }
+
+func (m *DeleteUserReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *DeleteUserReq) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type DeleteUserResp struct {
+}
+
+func (m *DeleteUserResp) Reset()                    { *m = DeleteUserResp{} }
+func (m *DeleteUserResp) String() string            { return proto.CompactTextString(m) }
+func (*DeleteUserResp) ProtoMessage()               {}
+func (*DeleteUserResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{49} }
+
+// /////////////////////////////////////////////////////////////////////////////
+// RenameUser() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type RenameUserReq struct {
+	Session     *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Username    string   `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	NewUsername string   `protobuf:"bytes,3,opt,name=new_username,json=newUsername" json:"newUsername,omitempty"`
+}
+
+func (m *RenameUserReq) Reset()                    { *m = RenameUserReq{} }
+func (m *RenameUserReq) String() string            { return proto.CompactTextString(m) }
+func (*RenameUserReq) ProtoMessage()               {}
+func (*RenameUserReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{50} }
+
+func (m *RenameUserReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *RenameUserReq) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *RenameUserReq) GetNewUsername() string {
+	if m != nil {
+		return m.NewUsername
+	}
+	return ""
+}
+
+type RenameUserResp struct {
+	User *User `protobuf:"bytes,1,opt,name=user" json:"user,omitempty"`
+}
+
+func (m *RenameUserResp) Reset()                    { *m = RenameUserResp{} }
+func (m *RenameUserResp) String() string            { return proto.CompactTextString(m) }
+func (*RenameUserResp) ProtoMessage()               {}
+func (*RenameUserResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{51} }
+
+func (m *RenameUserResp) GetUser() *User {
+	if m != nil {
+		return m.User
+	}
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// Two-factor rpcs
+// /////////////////////////////////////////////////////////////////////////////
+type EnrollTOTPReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+}
+
+func (m *EnrollTOTPReq) Reset()                    { *m = EnrollTOTPReq{} }
+func (m *EnrollTOTPReq) String() string            { return proto.CompactTextString(m) }
+func (*EnrollTOTPReq) ProtoMessage()               {}
+func (*EnrollTOTPReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{52} }
+
+func (m *EnrollTOTPReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+type EnrollTOTPResp struct {
+	Secret     string `protobuf:"bytes,1,opt,name=secret" json:"secret,omitempty"`
+	OtpauthUrl string `protobuf:"bytes,2,opt,name=otpauth_url,json=otpauthUrl" json:"otpauthUrl,omitempty"`
+}
+
+func (m *EnrollTOTPResp) Reset()                    { *m = EnrollTOTPResp{} }
+func (m *EnrollTOTPResp) String() string            { return proto.CompactTextString(m) }
+func (*EnrollTOTPResp) ProtoMessage()               {}
+func (*EnrollTOTPResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{53} }
+
+func (m *EnrollTOTPResp) GetSecret() string {
+	if m != nil {
+		return m.Secret
+	}
+	return ""
+}
+
+func (m *EnrollTOTPResp) GetOtpauthUrl() string {
+	if m != nil {
+		return m.OtpauthUrl
+	}
+	return ""
+}
+
+type ConfirmTOTPReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Code    string   `protobuf:"bytes,2,opt,name=code" json:"code,omitempty"`
+}
+
+func (m *ConfirmTOTPReq) Reset()                    { *m = ConfirmTOTPReq{} }
+func (m *ConfirmTOTPReq) String() string            { return proto.CompactTextString(m) }
+func (*ConfirmTOTPReq) ProtoMessage()               {}
+func (*ConfirmTOTPReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{54} }
+
+func (m *ConfirmTOTPReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *ConfirmTOTPReq) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+type ConfirmTOTPResp struct {
+	RecoveryCodes []string `protobuf:"bytes,1,rep,name=recovery_codes,json=recoveryCodes" json:"recoveryCodes,omitempty"`
+}
+
+func (m *ConfirmTOTPResp) Reset()                    { *m = ConfirmTOTPResp{} }
+func (m *ConfirmTOTPResp) String() string            { return proto.CompactTextString(m) }
+func (*ConfirmTOTPResp) ProtoMessage()               {}
+func (*ConfirmTOTPResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{55} }
+
+func (m *ConfirmTOTPResp) GetRecoveryCodes() []string {
+	if m != nil {
+		return m.RecoveryCodes
+	}
+	return nil
+}
+
+type DisableTOTPReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Code    string   `protobuf:"bytes,2,opt,name=code" json:"code,omitempty"`
+}
+
+func (m *DisableTOTPReq) Reset()                    { *m = DisableTOTPReq{} }
+func (m *DisableTOTPReq) String() string            { return proto.CompactTextString(m) }
+func (*DisableTOTPReq) ProtoMessage()               {}
+func (*DisableTOTPReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{56} }
+
+func (m *DisableTOTPReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *DisableTOTPReq) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+type DisableTOTPResp struct {
+}
+
+func (m *DisableTOTPResp) Reset()                    { *m = DisableTOTPResp{} }
+func (m *DisableTOTPResp) String() string            { return proto.CompactTextString(m) }
+func (*DisableTOTPResp) ProtoMessage()               {}
+func (*DisableTOTPResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{57} }
+
+type VerifySecondFactorReq struct {
+	Challenge string `protobuf:"bytes,1,opt,name=challenge" json:"challenge,omitempty"`
+	Code      string `protobuf:"bytes,2,opt,name=code" json:"code,omitempty"`
+}
+
+func (m *VerifySecondFactorReq) Reset()                    { *m = VerifySecondFactorReq{} }
+func (m *VerifySecondFactorReq) String() string            { return proto.CompactTextString(m) }
+func (*VerifySecondFactorReq) ProtoMessage()               {}
+func (*VerifySecondFactorReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{58} }
+
+func (m *VerifySecondFactorReq) GetChallenge() string {
+	if m != nil {
+		return m.Challenge
+	}
+	return ""
+}
+
+func (m *VerifySecondFactorReq) GetCode() string {
+	if m != nil {
+		return m.Code
+	}
+	return ""
+}
+
+type VerifySecondFactorResp struct {
+	Session           *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	RecoveryCodesLeft int32    `protobuf:"varint,2,opt,name=recovery_codes_left,json=recoveryCodesLeft" json:"recoveryCodesLeft,omitempty"`
+}
+
+func (m *VerifySecondFactorResp) Reset()                    { *m = VerifySecondFactorResp{} }
+func (m *VerifySecondFactorResp) String() string            { return proto.CompactTextString(m) }
+func (*VerifySecondFactorResp) ProtoMessage()               {}
+func (*VerifySecondFactorResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{59} }
+
+func (m *VerifySecondFactorResp) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *VerifySecondFactorResp) GetRecoveryCodesLeft() int32 {
+	if m != nil {
+		return m.RecoveryCodesLeft
+	}
+	return 0
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// WebAuthn rpcs
+//
+// Binary values are sent as bytes here and base64url encoded in the JSON
+// options, as WebAuthn clients expect.
+// /////////////////////////////////////////////////////////////////////////////
+type BeginWebAuthnRegistrationReq struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+}
+
+func (m *BeginWebAuthnRegistrationReq) Reset()                    { *m = BeginWebAuthnRegistrationReq{} }
+func (m *BeginWebAuthnRegistrationReq) String() string            { return proto.CompactTextString(m) }
+func (*BeginWebAuthnRegistrationReq) ProtoMessage()               {}
+func (*BeginWebAuthnRegistrationReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{60} }
+
+func (m *BeginWebAuthnRegistrationReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+type BeginWebAuthnRegistrationResp struct {
+	Options string `protobuf:"bytes,1,opt,name=options" json:"options,omitempty"`
+}
+
+func (m *BeginWebAuthnRegistrationResp) Reset()                    { *m = BeginWebAuthnRegistrationResp{} }
+func (m *BeginWebAuthnRegistrationResp) String() string            { return proto.CompactTextString(m) }
+func (*BeginWebAuthnRegistrationResp) ProtoMessage()               {}
+func (*BeginWebAuthnRegistrationResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{61} }
+
+func (m *BeginWebAuthnRegistrationResp) GetOptions() string {
+	if m != nil {
+		return m.Options
+	}
+	return ""
+}
+
+type FinishWebAuthnRegistrationReq struct {
+	Session           *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	ClientDataJson    []byte   `protobuf:"bytes,2,opt,name=client_data_json,json=clientDataJson,proto3" json:"clientDataJson,omitempty"`
+	AttestationObject []byte   `protobuf:"bytes,3,opt,name=attestation_object,json=attestationObject,proto3" json:"attestationObject,omitempty"`
+	Name              string   `protobuf:"bytes,4,opt,name=name" json:"name,omitempty"`
+}
+
+func (m *FinishWebAuthnRegistrationReq) Reset()                    { *m = FinishWebAuthnRegistrationReq{} }
+func (m *FinishWebAuthnRegistrationReq) String() string            { return proto.CompactTextString(m) }
+func (*FinishWebAuthnRegistrationReq) ProtoMessage()               {}
+func (*FinishWebAuthnRegistrationReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{62} }
+
+func (m *FinishWebAuthnRegistrationReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *FinishWebAuthnRegistrationReq) GetClientDataJson() []byte {
+	if m != nil {
+		return m.ClientDataJson
+	}
+	return nil
+}
+
+func (m *FinishWebAuthnRegistrationReq) GetAttestationObject() []byte {
+	if m != nil {
+		return m.AttestationObject
+	}
+	return nil
+}
+
+func (m *FinishWebAuthnRegistrationReq) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+type FinishWebAuthnRegistrationResp struct {
+	Credential *WebAuthnCredential `protobuf:"bytes,1,opt,name=credential" json:"credential,omitempty"`
+}
+
+func (m *FinishWebAuthnRegistrationResp) Reset()         { *m = FinishWebAuthnRegistrationResp{} }
+func (m *FinishWebAuthnRegistrationResp) String() string { return proto.CompactTextString(m) }
+func (*FinishWebAuthnRegistrationResp) ProtoMessage()    {}
+func (*FinishWebAuthnRegistrationResp) Descriptor() ([]byte, []int) {
+	return fileDescriptor0, []int{63}
+}
+
+func (m *FinishWebAuthnRegistrationResp) GetCredential() *WebAuthnCredential {
+	if m != nil {
+		return m.Credential
+	}
+	return nil
+}
+
+type BeginWebAuthnLoginReq struct {
+	Username string `protobuf:"bytes,1,opt,name=username" json:"username,omitempty"`
+}
+
+func (m *BeginWebAuthnLoginReq) Reset()                    { *m = BeginWebAuthnLoginReq{} }
+func (m *BeginWebAuthnLoginReq) String() string            { return proto.CompactTextString(m) }
+func (*BeginWebAuthnLoginReq) ProtoMessage()               {}
+func (*BeginWebAuthnLoginReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{64} }
+
+func (m *BeginWebAuthnLoginReq) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+type BeginWebAuthnLoginResp struct {
+	Options string `protobuf:"bytes,1,opt,name=options" json:"options,omitempty"`
+}
+
+func (m *BeginWebAuthnLoginResp) Reset()                    { *m = BeginWebAuthnLoginResp{} }
+func (m *BeginWebAuthnLoginResp) String() string            { return proto.CompactTextString(m) }
+func (*BeginWebAuthnLoginResp) ProtoMessage()               {}
+func (*BeginWebAuthnLoginResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{65} }
+
+func (m *BeginWebAuthnLoginResp) GetOptions() string {
+	if m != nil {
+		return m.Options
+	}
+	return ""
+}
+
+type FinishWebAuthnLoginReq struct {
+	CredentialId      []byte `protobuf:"bytes,1,opt,name=credential_id,json=credentialId,proto3" json:"credentialId,omitempty"`
+	ClientDataJson    []byte `protobuf:"bytes,2,opt,name=client_data_json,json=clientDataJson,proto3" json:"clientDataJson,omitempty"`
+	AuthenticatorData []byte `protobuf:"bytes,3,opt,name=authenticator_data,json=authenticatorData,proto3" json:"authenticatorData,omitempty"`
+	Signature         []byte `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"`
+	UserHandle        []byte `protobuf:"bytes,5,opt,name=user_handle,json=userHandle,proto3" json:"userHandle,omitempty"`
+}
+
+func (m *FinishWebAuthnLoginReq) Reset()                    { *m = FinishWebAuthnLoginReq{} }
+func (m *FinishWebAuthnLoginReq) String() string            { return proto.CompactTextString(m) }
+func (*FinishWebAuthnLoginReq) ProtoMessage()               {}
+func (*FinishWebAuthnLoginReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{66} }
+
+func (m *FinishWebAuthnLoginReq) GetCredentialId() []byte {
+	if m != nil {
+		return m.CredentialId
+	}
+	return nil
+}
+
+func (m *FinishWebAuthnLoginReq) GetClientDataJson() []byte {
+	if m != nil {
+		return m.ClientDataJson
+	}
+	return nil
+}
+
+func (m *FinishWebAuthnLoginReq) GetAuthenticatorData() []byte {
+	if m != nil {
+		return m.AuthenticatorData
+	}
+	return nil
+}
+
+func (m *FinishWebAuthnLoginReq) GetSignature() []byte {
+	if m != nil {
+		return m.Signature
+	}
+	return nil
+}
+
+func (m *FinishWebAuthnLoginReq) GetUserHandle() []byte {
+	if m != nil {
+		return m.UserHandle
+	}
+	return nil
+}
+
+type FinishWebAuthnLoginResp struct {
+	Session *Session `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+}
+
+func (m *FinishWebAuthnLoginResp) Reset()                    { *m = FinishWebAuthnLoginResp{} }
+func (m *FinishWebAuthnLoginResp) String() string            { return proto.CompactTextString(m) }
+func (*FinishWebAuthnLoginResp) ProtoMessage()               {}
+func (*FinishWebAuthnLoginResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{67} }
+
+func (m *FinishWebAuthnLoginResp) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+// ListAuditEvents() rpc
+// /////////////////////////////////////////////////////////////////////////////
+type ListAuditEventsReq struct {
+	Session   *Session     `protobuf:"bytes,1,opt,name=session" json:"session,omitempty"`
+	Filter    *AuditFilter `protobuf:"bytes,2,opt,name=filter" json:"filter,omitempty"`
+	PageToken string       `protobuf:"bytes,3,opt,name=page_token,json=pageToken" json:"pageToken,omitempty"`
+	PageSize  int32        `protobuf:"varint,4,opt,name=page_size,json=pageSize" json:"pageSize,omitempty"`
+}
+
+func (m *ListAuditEventsReq) Reset()                    { *m = ListAuditEventsReq{} }
+func (m *ListAuditEventsReq) String() string            { return proto.CompactTextString(m) }
+func (*ListAuditEventsReq) ProtoMessage()               {}
+func (*ListAuditEventsReq) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{68} }
+
+func (m *ListAuditEventsReq) GetSession() *Session {
+	if m != nil {
+		return m.Session
+	}
+	return nil
+}
+
+func (m *ListAuditEventsReq) GetFilter() *AuditFilter {
+	if m != nil {
+		return m.Filter
+	}
+	return nil
+}
+
+func (m *ListAuditEventsReq) GetPageToken() string {
+	if m != nil {
+		return m.PageToken
+	}
+	return ""
+}
+
+func (m *ListAuditEventsReq) GetPageSize() int32 {
+	if m != nil {
+		return m.PageSize
+	}
+	return 0
+}
+
+type ListAuditEventsResp struct {
+	Events        []*AuditEvent `protobuf:"bytes,1,rep,name=events" json:"events,omitempty"`
+	NextPageToken string        `protobuf:"bytes,2,opt,name=next_page_token,json=nextPageToken" json:"nextPageToken,omitempty"`
+}
+
+func (m *ListAuditEventsResp) Reset()                    { *m = ListAuditEventsResp{} }
+func (m *ListAuditEventsResp) String() string            { return proto.CompactTextString(m) }
+func (*ListAuditEventsResp) ProtoMessage()               {}
+func (*ListAuditEventsResp) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{69} }
+
+func (m *ListAuditEventsResp) GetEvents() []*AuditEvent {
+	if m != nil {
+		return m.Events
+	}
+	return nil
+}
+
+func (m *ListAuditEventsResp) GetNextPageToken() string {
+	if m != nil {
+		return m.NextPageToken
+	}
+	return ""
+}
+
+// User is the public user message
+type User struct {
+	Username            string                `protobuf:"bytes,1,opt,name=username" json:"username,omitempty"`
+	Email               string                `protobuf:"bytes,2,opt,name=email" json:"email,omitempty"`
+	EmailVerified       bool                  `protobuf:"varint,3,opt,name=email_verified,json=emailVerified" json:"emailVerified,omitempty"`
+	Profile             *Profile              `protobuf:"bytes,4,opt,name=profile" json:"profile,omitempty"`
+	Roles               []*RoleGrant          `protobuf:"bytes,5,rep,name=roles" json:"roles,omitempty"`
+	Disabled            bool                  `protobuf:"varint,6,opt,name=disabled" json:"disabled,omitempty"`
+	TotpEnabled         bool                  `protobuf:"varint,7,opt,name=totp_enabled,json=totpEnabled" json:"totpEnabled,omitempty"`
+	WebauthnCredentials []*WebAuthnCredential `protobuf:"bytes,8,rep,name=webauthn_credentials,json=webauthnCredentials" json:"webauthnCredentials,omitempty"`
+}
+
+func (m *User) Reset()                    { *m = User{} }
+func (m *User) String() string            { return proto.CompactTextString(m) }
+func (*User) ProtoMessage()               {}
+func (*User) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{70} }
+
+func (m *User) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *User) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *User) GetEmailVerified() bool {
+	if m != nil {
+		return m.EmailVerified
+	}
+	return false
+}
+
+func (m *User) GetProfile() *Profile {
+	if m != nil {
+		return m.Profile
+	}
+	return nil
+}
+
+func (m *User) GetRoles() []*RoleGrant {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *User) GetDisabled() bool {
+	if m != nil {
+		return m.Disabled
+	}
+	return false
+}
+
+func (m *User) GetTotpEnabled() bool {
+	if m != nil {
+		return m.TotpEnabled
+	}
+	return false
+}
+
+func (m *User) GetWebauthnCredentials() []*WebAuthnCredential {
+	if m != nil {
+		return m.WebauthnCredentials
+	}
+	return nil
+}
+
+// WebAuthnCredential is a passkey or security key registered to a user
+type WebAuthnCredential struct {
+	Id         string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Name       string `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	CreatedAt  int64  `protobuf:"varint,3,opt,name=created_at,json=createdAt" json:"createdAt,omitempty"`
+	LastUsedAt int64  `protobuf:"varint,4,opt,name=last_used_at,json=lastUsedAt" json:"lastUsedAt,omitempty"`
+}
+
+func (m *WebAuthnCredential) Reset()                    { *m = WebAuthnCredential{} }
+func (m *WebAuthnCredential) String() string            { return proto.CompactTextString(m) }
+func (*WebAuthnCredential) ProtoMessage()               {}
+func (*WebAuthnCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{71} }
+
+func (m *WebAuthnCredential) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *WebAuthnCredential) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *WebAuthnCredential) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *WebAuthnCredential) GetLastUsedAt() int64 {
+	if m != nil {
+		return m.LastUsedAt
+	}
+	return 0
+}
+
+// Group is a named set of users and groups
+type Group struct {
+	Name        string       `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Description string       `protobuf:"bytes,2,opt,name=description" json:"description,omitempty"`
+	Roles       []*RoleGrant `protobuf:"bytes,3,rep,name=roles" json:"roles,omitempty"`
+}
+
+func (m *Group) Reset()                    { *m = Group{} }
+func (m *Group) String() string            { return proto.CompactTextString(m) }
+func (*Group) ProtoMessage()               {}
+func (*Group) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{72} }
+
+func (m *Group) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *Group) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *Group) GetRoles() []*RoleGrant {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+// Member is a member of a group, exactly one of username or group is set
+type Member struct {
+	Username string `protobuf:"bytes,1,opt,name=username" json:"username,omitempty"`
+	Group    string `protobuf:"bytes,2,opt,name=group" json:"group,omitempty"`
+}
+
+func (m *Member) Reset()                    { *m = Member{} }
+func (m *Member) String() string            { return proto.CompactTextString(m) }
+func (*Member) ProtoMessage()               {}
+func (*Member) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{73} }
+
+func (m *Member) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *Member) GetGroup() string {
+	if m != nil {
+		return m.Group
+	}
+	return ""
+}
+
+// RoleGrant is a role held by a user or a group. A grant with an empty resource applies
+// to every resource, a resource ending in * applies to every resource with
+// that prefix, ex: projects/42/*
+type RoleGrant struct {
+	Role     string `protobuf:"bytes,1,opt,name=role" json:"role,omitempty"`
+	Resource string `protobuf:"bytes,2,opt,name=resource" json:"resource,omitempty"`
+}
+
+func (m *RoleGrant) Reset()                    { *m = RoleGrant{} }
+func (m *RoleGrant) String() string            { return proto.CompactTextString(m) }
+func (*RoleGrant) ProtoMessage()               {}
+func (*RoleGrant) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{74} }
+
+func (m *RoleGrant) GetRole() string {
+	if m != nil {
+		return m.Role
+	}
+	return ""
+}
+
+func (m *RoleGrant) GetResource() string {
+	if m != nil {
+		return m.Resource
+	}
+	return ""
+}
+
+// Profile is the user editable part of a user
+type Profile struct {
+	DisplayName string            `protobuf:"bytes,1,opt,name=display_name,json=displayName" json:"displayName,omitempty"`
+	AvatarUrl   string            `protobuf:"bytes,2,opt,name=avatar_url,json=avatarUrl" json:"avatarUrl,omitempty"`
+	Locale      string            `protobuf:"bytes,3,opt,name=locale" json:"locale,omitempty"`
+	Timezone    string            `protobuf:"bytes,4,opt,name=timezone" json:"timezone,omitempty"`
+	Attributes  map[string]string `protobuf:"bytes,5,rep,name=attributes" json:"attributes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+}
+
+func (m *Profile) Reset()                    { *m = Profile{} }
+func (m *Profile) String() string            { return proto.CompactTextString(m) }
+func (*Profile) ProtoMessage()               {}
+func (*Profile) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{75} }
+
+func (m *Profile) GetDisplayName() string {
+	if m != nil {
+		return m.DisplayName
+	}
+	return ""
+}
+
+func (m *Profile) GetAvatarUrl() string {
+	if m != nil {
+		return m.AvatarUrl
+	}
+	return ""
+}
+
+func (m *Profile) GetLocale() string {
+	if m != nil {
+		return m.Locale
+	}
+	return ""
+}
+
+func (m *Profile) GetTimezone() string {
+	if m != nil {
+		return m.Timezone
+	}
+	return ""
+}
+
+func (m *Profile) GetAttributes() map[string]string {
+	if m != nil {
+		return m.Attributes
+	}
+	return nil
+}
+
+// AuditEvent is a security relevant event. Events are only ever appended.
+type AuditEvent struct {
+	Id       string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	Time     int64  `protobuf:"varint,2,opt,name=time" json:"time,omitempty"`
+	Action   string `protobuf:"bytes,3,opt,name=action" json:"action,omitempty"`
+	Actor    string `protobuf:"bytes,4,opt,name=actor" json:"actor,omitempty"`
+	Subject  string `protobuf:"bytes,5,opt,name=subject" json:"subject,omitempty"`
+	ClientIp string `protobuf:"bytes,6,opt,name=client_ip,json=clientIp" json:"clientIp,omitempty"`
+	Outcome  string `protobuf:"bytes,7,opt,name=outcome" json:"outcome,omitempty"`
+	Detail   string `protobuf:"bytes,8,opt,name=detail" json:"detail,omitempty"`
+}
+
+func (m *AuditEvent) Reset()                    { *m = AuditEvent{} }
+func (m *AuditEvent) String() string            { return proto.CompactTextString(m) }
+func (*AuditEvent) ProtoMessage()               {}
+func (*AuditEvent) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{76} }
+
+func (m *AuditEvent) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetTime() int64 {
+	if m != nil {
+		return m.Time
+	}
+	return 0
+}
+
+func (m *AuditEvent) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetClientIp() string {
+	if m != nil {
+		return m.ClientIp
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetOutcome() string {
+	if m != nil {
+		return m.Outcome
+	}
+	return ""
+}
+
+func (m *AuditEvent) GetDetail() string {
+	if m != nil {
+		return m.Detail
+	}
+	return ""
+}
+
+// AuditFilter selects audit events, empty fields match everything
+type AuditFilter struct {
+	Action   string `protobuf:"bytes,1,opt,name=action" json:"action,omitempty"`
+	Actor    string `protobuf:"bytes,2,opt,name=actor" json:"actor,omitempty"`
+	Subject  string `protobuf:"bytes,3,opt,name=subject" json:"subject,omitempty"`
+	ClientIp string `protobuf:"bytes,4,opt,name=client_ip,json=clientIp" json:"clientIp,omitempty"`
+	Outcome  string `protobuf:"bytes,5,opt,name=outcome" json:"outcome,omitempty"`
+	Since    int64  `protobuf:"varint,6,opt,name=since" json:"since,omitempty"`
+	Until    int64  `protobuf:"varint,7,opt,name=until" json:"until,omitempty"`
+}
+
+func (m *AuditFilter) Reset()                    { *m = AuditFilter{} }
+func (m *AuditFilter) String() string            { return proto.CompactTextString(m) }
+func (*AuditFilter) ProtoMessage()               {}
+func (*AuditFilter) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{77} }
+
+func (m *AuditFilter) GetAction() string {
+	if m != nil {
+		return m.Action
+	}
+	return ""
+}
+
+func (m *AuditFilter) GetActor() string {
+	if m != nil {
+		return m.Actor
+	}
+	return ""
+}
+
+func (m *AuditFilter) GetSubject() string {
+	if m != nil {
+		return m.Subject
+	}
+	return ""
+}
+
+func (m *AuditFilter) GetClientIp() string {
+	if m != nil {
+		return m.ClientIp
+	}
+	return ""
+}
+
+func (m *AuditFilter) GetOutcome() string {
+	if m != nil {
+		return m.Outcome
+	}
+	return ""
+}
+
+func (m *AuditFilter) GetSince() int64 {
+	if m != nil {
+		return m.Since
+	}
+	return 0
+}
+
+func (m *AuditFilter) GetUntil() int64 {
+	if m != nil {
+		return m.Until
+	}
+	return 0
+}
+
+// Session is a message that represents a session. Use it as your key
+// for making authenticated rpc calls. Only the token needs to be sent, the
+// other fields are informational and are never trusted by the server.
+type Session struct {
+	Token      string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	Username   string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	CreatedAt  int64  `protobuf:"varint,3,opt,name=created_at,json=createdAt" json:"createdAt,omitempty"`
+	LastSeenAt int64  `protobuf:"varint,4,opt,name=last_seen_at,json=lastSeenAt" json:"lastSeenAt,omitempty"`
+	ExpiresAt  int64  `protobuf:"varint,5,opt,name=expires_at,json=expiresAt" json:"expiresAt,omitempty"`
+}
+
+func (m *Session) Reset()                    { *m = Session{} }
+func (m *Session) String() string            { return proto.CompactTextString(m) }
+func (*Session) ProtoMessage()               {}
+func (*Session) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{78} }
+
+func (m *Session) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *Session) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *Session) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *Session) GetLastSeenAt() int64 {
+	if m != nil {
+		return m.LastSeenAt
+	}
+	return 0
+}
+
+func (m *Session) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+// SessionInfo describes a session without exposing its token
+type SessionInfo struct {
+	Id         string `protobuf:"bytes,1,opt,name=id" json:"id,omitempty"`
+	CreatedAt  int64  `protobuf:"varint,2,opt,name=created_at,json=createdAt" json:"createdAt,omitempty"`
+	LastSeenAt int64  `protobuf:"varint,3,opt,name=last_seen_at,json=lastSeenAt" json:"lastSeenAt,omitempty"`
+	ExpiresAt  int64  `protobuf:"varint,4,opt,name=expires_at,json=expiresAt" json:"expiresAt,omitempty"`
+	ClientIp   string `protobuf:"bytes,5,opt,name=client_ip,json=clientIp" json:"clientIp,omitempty"`
+	UserAgent  string `protobuf:"bytes,6,opt,name=user_agent,json=userAgent" json:"userAgent,omitempty"`
+	Current    bool   `protobuf:"varint,7,opt,name=current" json:"current,omitempty"`
+}
+
+func (m *SessionInfo) Reset()                    { *m = SessionInfo{} }
+func (m *SessionInfo) String() string            { return proto.CompactTextString(m) }
+func (*SessionInfo) ProtoMessage()               {}
+func (*SessionInfo) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{79} }
+
+func (m *SessionInfo) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *SessionInfo) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetLastSeenAt() int64 {
+	if m != nil {
+		return m.LastSeenAt
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *SessionInfo) GetClientIp() string {
+	if m != nil {
+		return m.ClientIp
+	}
+	return ""
+}
+
+func (m *SessionInfo) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *SessionInfo) GetCurrent() bool {
+	if m != nil {
+		return m.Current
+	}
+	return false
+}
+
+// PrivateUser is the message that is stored in the DB, do not publiclly expose it.
+type PrivateUser struct {
+	Username            string                       `protobuf:"bytes,1,opt,name=username" json:"username,omitempty"`
+	PasswordSha256      []byte                       `protobuf:"bytes,2,opt,name=passwordSha256,proto3" json:"passwordSha256,omitempty"`
+	PasswordHash        string                       `protobuf:"bytes,3,opt,name=passwordHash" json:"passwordHash,omitempty"`
+	Email               string                       `protobuf:"bytes,4,opt,name=email" json:"email,omitempty"`
+	EmailVerified       bool                         `protobuf:"varint,5,opt,name=emailVerified" json:"emailVerified,omitempty"`
+	Profile             *Profile                     `protobuf:"bytes,6,opt,name=profile" json:"profile,omitempty"`
+	Roles               []*RoleGrant                 `protobuf:"bytes,7,rep,name=roles" json:"roles,omitempty"`
+	Disabled            bool                         `protobuf:"varint,8,opt,name=disabled" json:"disabled,omitempty"`
+	DisabledAt          int64                        `protobuf:"varint,9,opt,name=disabled_at,json=disabledAt" json:"disabledAt,omitempty"`
+	Id                  string                       `protobuf:"bytes,10,opt,name=id" json:"id,omitempty"`
+	CreatedAt           int64                        `protobuf:"varint,11,opt,name=created_at,json=createdAt" json:"createdAt,omitempty"`
+	TotpSecret          []byte                       `protobuf:"bytes,12,opt,name=totp_secret,json=totpSecret,proto3" json:"totpSecret,omitempty"`
+	TotpEnabled         bool                         `protobuf:"varint,13,opt,name=totp_enabled,json=totpEnabled" json:"totpEnabled,omitempty"`
+	TotpLastStep        int64                        `protobuf:"varint,14,opt,name=totp_last_step,json=totpLastStep" json:"totpLastStep,omitempty"`
+	RecoveryCodeHashes  []string                     `protobuf:"bytes,15,rep,name=recovery_code_hashes,json=recoveryCodeHashes" json:"recoveryCodeHashes,omitempty"`
+	WebauthnCredentials []*PrivateWebAuthnCredential `protobuf:"bytes,16,rep,name=webauthn_credentials,json=webauthnCredentials" json:"webauthnCredentials,omitempty"`
+}
+
+func (m *PrivateUser) Reset()                    { *m = PrivateUser{} }
+func (m *PrivateUser) String() string            { return proto.CompactTextString(m) }
+func (*PrivateUser) ProtoMessage()               {}
+func (*PrivateUser) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{80} }
+
+func (m *PrivateUser) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *PrivateUser) GetPasswordSha256() []byte {
+	if m != nil {
+		return m.PasswordSha256
+	}
+	return nil
+}
+
+func (m *PrivateUser) GetPasswordHash() string {
+	if m != nil {
+		return m.PasswordHash
+	}
+	return ""
+}
+
+func (m *PrivateUser) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *PrivateUser) GetEmailVerified() bool {
+	if m != nil {
+		return m.EmailVerified
+	}
+	return false
+}
+
+func (m *PrivateUser) GetProfile() *Profile {
+	if m != nil {
+		return m.Profile
+	}
+	return nil
+}
+
+func (m *PrivateUser) GetRoles() []*RoleGrant {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *PrivateUser) GetDisabled() bool {
+	if m != nil {
+		return m.Disabled
+	}
+	return false
+}
+
+func (m *PrivateUser) GetDisabledAt() int64 {
+	if m != nil {
+		return m.DisabledAt
+	}
+	return 0
+}
+
+func (m *PrivateUser) GetId() string {
+	if m != nil {
+		return m.Id
+	}
+	return ""
+}
+
+func (m *PrivateUser) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *PrivateUser) GetTotpSecret() []byte {
+	if m != nil {
+		return m.TotpSecret
+	}
+	return nil
+}
+
+func (m *PrivateUser) GetTotpEnabled() bool {
+	if m != nil {
+		return m.TotpEnabled
+	}
+	return false
+}
+
+func (m *PrivateUser) GetTotpLastStep() int64 {
+	if m != nil {
+		return m.TotpLastStep
+	}
+	return 0
+}
+
+func (m *PrivateUser) GetRecoveryCodeHashes() []string {
+	if m != nil {
+		return m.RecoveryCodeHashes
+	}
+	return nil
+}
+
+func (m *PrivateUser) GetWebauthnCredentials() []*PrivateWebAuthnCredential {
+	if m != nil {
+		return m.WebauthnCredentials
+	}
+	return nil
+}
+
+// PrivateWebAuthnCredential is a registered WebAuthn credential, do not
+// publicly expose it
+type PrivateWebAuthnCredential struct {
+	Id         []byte `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	PublicKey  []byte `protobuf:"bytes,2,opt,name=public_key,json=publicKey,proto3" json:"publicKey,omitempty"`
+	SignCount  uint32 `protobuf:"varint,3,opt,name=sign_count,json=signCount" json:"signCount,omitempty"`
+	Name       string `protobuf:"bytes,4,opt,name=name" json:"name,omitempty"`
+	CreatedAt  int64  `protobuf:"varint,5,opt,name=created_at,json=createdAt" json:"createdAt,omitempty"`
+	LastUsedAt int64  `protobuf:"varint,6,opt,name=last_used_at,json=lastUsedAt" json:"lastUsedAt,omitempty"`
+}
+
+func (m *PrivateWebAuthnCredential) Reset()                    { *m = PrivateWebAuthnCredential{} }
+func (m *PrivateWebAuthnCredential) String() string            { return proto.CompactTextString(m) }
+func (*PrivateWebAuthnCredential) ProtoMessage()               {}
+func (*PrivateWebAuthnCredential) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{81} }
+
+func (m *PrivateWebAuthnCredential) GetId() []byte {
+	if m != nil {
+		return m.Id
+	}
+	return nil
+}
+
+func (m *PrivateWebAuthnCredential) GetPublicKey() []byte {
+	if m != nil {
+		return m.PublicKey
+	}
+	return nil
+}
+
+func (m *PrivateWebAuthnCredential) GetSignCount() uint32 {
+	if m != nil {
+		return m.SignCount
+	}
+	return 0
+}
+
+func (m *PrivateWebAuthnCredential) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PrivateWebAuthnCredential) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *PrivateWebAuthnCredential) GetLastUsedAt() int64 {
+	if m != nil {
+		return m.LastUsedAt
+	}
+	return 0
+}
+
+// PrivateSession is the message that is stored in the DB, do not publicly expose it.
+type PrivateSession struct {
+	Token      string `protobuf:"bytes,1,opt,name=token" json:"token,omitempty"`
+	Username   string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	CreatedAt  int64  `protobuf:"varint,3,opt,name=created_at,json=createdAt" json:"createdAt,omitempty"`
+	LastSeenAt int64  `protobuf:"varint,4,opt,name=last_seen_at,json=lastSeenAt" json:"lastSeenAt,omitempty"`
+	ExpiresAt  int64  `protobuf:"varint,5,opt,name=expires_at,json=expiresAt" json:"expiresAt,omitempty"`
+	ClientIp   string `protobuf:"bytes,6,opt,name=client_ip,json=clientIp" json:"clientIp,omitempty"`
+	UserAgent  string `protobuf:"bytes,7,opt,name=user_agent,json=userAgent" json:"userAgent,omitempty"`
+	UserId     string `protobuf:"bytes,8,opt,name=user_id,json=userId" json:"userId,omitempty"`
+}
+
+func (m *PrivateSession) Reset()                    { *m = PrivateSession{} }
+func (m *PrivateSession) String() string            { return proto.CompactTextString(m) }
+func (*PrivateSession) ProtoMessage()               {}
+func (*PrivateSession) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{82} }
+
+func (m *PrivateSession) GetToken() string {
+	if m != nil {
+		return m.Token
+	}
+	return ""
+}
+
+func (m *PrivateSession) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *PrivateSession) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *PrivateSession) GetLastSeenAt() int64 {
+	if m != nil {
+		return m.LastSeenAt
+	}
+	return 0
+}
+
+func (m *PrivateSession) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *PrivateSession) GetClientIp() string {
+	if m != nil {
+		return m.ClientIp
+	}
+	return ""
+}
+
+func (m *PrivateSession) GetUserAgent() string {
+	if m != nil {
+		return m.UserAgent
+	}
+	return ""
+}
+
+func (m *PrivateSession) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+// PrivateRenamedUser records a username given up by a rename. The name is
+// reserved for the user who had it until reserved_until.
+type PrivateRenamedUser struct {
+	Username      string `protobuf:"bytes,1,opt,name=username" json:"username,omitempty"`
+	UserId        string `protobuf:"bytes,2,opt,name=user_id,json=userId" json:"userId,omitempty"`
+	RenamedTo     string `protobuf:"bytes,3,opt,name=renamed_to,json=renamedTo" json:"renamedTo,omitempty"`
+	RenamedAt     int64  `protobuf:"varint,4,opt,name=renamed_at,json=renamedAt" json:"renamedAt,omitempty"`
+	ReservedUntil int64  `protobuf:"varint,5,opt,name=reserved_until,json=reservedUntil" json:"reservedUntil,omitempty"`
+}
+
+func (m *PrivateRenamedUser) Reset()                    { *m = PrivateRenamedUser{} }
+func (m *PrivateRenamedUser) String() string            { return proto.CompactTextString(m) }
+func (*PrivateRenamedUser) ProtoMessage()               {}
+func (*PrivateRenamedUser) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{83} }
+
+func (m *PrivateRenamedUser) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *PrivateRenamedUser) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *PrivateRenamedUser) GetRenamedTo() string {
+	if m != nil {
+		return m.RenamedTo
+	}
+	return ""
+}
+
+func (m *PrivateRenamedUser) GetRenamedAt() int64 {
+	if m != nil {
+		return m.RenamedAt
+	}
+	return 0
+}
+
+func (m *PrivateRenamedUser) GetReservedUntil() int64 {
+	if m != nil {
+		return m.ReservedUntil
+	}
+	return 0
+}
+
+// PrivateResetToken is a pending password reset, do not publicly expose it.
+// Only the sha256 of the token is stored.
+type PrivateResetToken struct {
+	TokenHash string `protobuf:"bytes,1,opt,name=token_hash,json=tokenHash" json:"tokenHash,omitempty"`
+	Username  string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	CreatedAt int64  `protobuf:"varint,3,opt,name=created_at,json=createdAt" json:"createdAt,omitempty"`
+	ExpiresAt int64  `protobuf:"varint,4,opt,name=expires_at,json=expiresAt" json:"expiresAt,omitempty"`
+}
+
+func (m *PrivateResetToken) Reset()                    { *m = PrivateResetToken{} }
+func (m *PrivateResetToken) String() string            { return proto.CompactTextString(m) }
+func (*PrivateResetToken) ProtoMessage()               {}
+func (*PrivateResetToken) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{84} }
+
+func (m *PrivateResetToken) GetTokenHash() string {
+	if m != nil {
+		return m.TokenHash
+	}
+	return ""
+}
+
+func (m *PrivateResetToken) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *PrivateResetToken) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *PrivateResetToken) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+// PrivateLoginChallenge is a Login waiting for a second factor or a pending
+// WebAuthn ceremony, do not publicly expose it. Only the sha256 of the
+// challenge is stored.
+type PrivateLoginChallenge struct {
+	ChallengeHash string `protobuf:"bytes,1,opt,name=challenge_hash,json=challengeHash" json:"challengeHash,omitempty"`
+	UserId        string `protobuf:"bytes,2,opt,name=user_id,json=userId" json:"userId,omitempty"`
+	CreatedAt     int64  `protobuf:"varint,3,opt,name=created_at,json=createdAt" json:"createdAt,omitempty"`
+	ExpiresAt     int64  `protobuf:"varint,4,opt,name=expires_at,json=expiresAt" json:"expiresAt,omitempty"`
+	Failures      int32  `protobuf:"varint,5,opt,name=failures" json:"failures,omitempty"`
+	Kind          string `protobuf:"bytes,6,opt,name=kind" json:"kind,omitempty"`
+}
+
+func (m *PrivateLoginChallenge) Reset()                    { *m = PrivateLoginChallenge{} }
+func (m *PrivateLoginChallenge) String() string            { return proto.CompactTextString(m) }
+func (*PrivateLoginChallenge) ProtoMessage()               {}
+func (*PrivateLoginChallenge) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{85} }
+
+func (m *PrivateLoginChallenge) GetChallengeHash() string {
+	if m != nil {
+		return m.ChallengeHash
+	}
+	return ""
+}
+
+func (m *PrivateLoginChallenge) GetUserId() string {
+	if m != nil {
+		return m.UserId
+	}
+	return ""
+}
+
+func (m *PrivateLoginChallenge) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *PrivateLoginChallenge) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+func (m *PrivateLoginChallenge) GetFailures() int32 {
+	if m != nil {
+		return m.Failures
+	}
+	return 0
+}
+
+func (m *PrivateLoginChallenge) GetKind() string {
+	if m != nil {
+		return m.Kind
+	}
+	return ""
+}
+
+// PrivateVerificationToken is a pending email verification, do not publicly
+// expose it. Only the sha256 of the token is stored.
+type PrivateVerificationToken struct {
+	TokenHash string `protobuf:"bytes,1,opt,name=token_hash,json=tokenHash" json:"tokenHash,omitempty"`
+	Username  string `protobuf:"bytes,2,opt,name=username" json:"username,omitempty"`
+	Email     string `protobuf:"bytes,3,opt,name=email" json:"email,omitempty"`
+	CreatedAt int64  `protobuf:"varint,4,opt,name=created_at,json=createdAt" json:"createdAt,omitempty"`
+	ExpiresAt int64  `protobuf:"varint,5,opt,name=expires_at,json=expiresAt" json:"expiresAt,omitempty"`
+}
+
+func (m *PrivateVerificationToken) Reset()                    { *m = PrivateVerificationToken{} }
+func (m *PrivateVerificationToken) String() string            { return proto.CompactTextString(m) }
+func (*PrivateVerificationToken) ProtoMessage()               {}
+func (*PrivateVerificationToken) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{86} }
+
+func (m *PrivateVerificationToken) GetTokenHash() string {
+	if m != nil {
+		return m.TokenHash
+	}
+	return ""
+}
+
+func (m *PrivateVerificationToken) GetUsername() string {
+	if m != nil {
+		return m.Username
+	}
+	return ""
+}
+
+func (m *PrivateVerificationToken) GetEmail() string {
+	if m != nil {
+		return m.Email
+	}
+	return ""
+}
+
+func (m *PrivateVerificationToken) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
+func (m *PrivateVerificationToken) GetExpiresAt() int64 {
+	if m != nil {
+		return m.ExpiresAt
+	}
+	return 0
+}
+
+// PrivateGroup is the message that is stored in the DB, do not publicly expose it.
+// Memberships are stored separately.
+type PrivateGroup struct {
+	Name        string       `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Description string       `protobuf:"bytes,2,opt,name=description" json:"description,omitempty"`
+	Roles       []*RoleGrant `protobuf:"bytes,3,rep,name=roles" json:"roles,omitempty"`
+	CreatedAt   int64        `protobuf:"varint,4,opt,name=created_at,json=createdAt" json:"createdAt,omitempty"`
+}
+
+func (m *PrivateGroup) Reset()                    { *m = PrivateGroup{} }
+func (m *PrivateGroup) String() string            { return proto.CompactTextString(m) }
+func (*PrivateGroup) ProtoMessage()               {}
+func (*PrivateGroup) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{87} }
+
+func (m *PrivateGroup) GetName() string {
+	if m != nil {
+		return m.Name
+	}
+	return ""
+}
+
+func (m *PrivateGroup) GetDescription() string {
+	if m != nil {
+		return m.Description
+	}
+	return ""
+}
+
+func (m *PrivateGroup) GetRoles() []*RoleGrant {
+	if m != nil {
+		return m.Roles
+	}
+	return nil
+}
+
+func (m *PrivateGroup) GetCreatedAt() int64 {
+	if m != nil {
+		return m.CreatedAt
+	}
+	return 0
+}
+
 func init() {
 	proto.RegisterType((*RegisterReq)(nil), "ericmoritz.users.RegisterReq")
 	proto.RegisterType((*RegisterResp)(nil), "ericmoritz.users.RegisterResp")
@@ -270,36 +2736,288 @@ func init() {
 	proto.RegisterType((*UserResp)(nil), "ericmoritz.users.UserResp")
 	proto.RegisterType((*CurrentUserReq)(nil), "ericmoritz.users.CurrentUserReq")
 	proto.RegisterType((*CurrentUserResp)(nil), "ericmoritz.users.CurrentUserResp")
+	proto.RegisterType((*LogoutReq)(nil), "ericmoritz.users.LogoutReq")
+	proto.RegisterType((*LogoutResp)(nil), "ericmoritz.users.LogoutResp")
+	proto.RegisterType((*LogoutAllReq)(nil), "ericmoritz.users.LogoutAllReq")
+	proto.RegisterType((*LogoutAllResp)(nil), "ericmoritz.users.LogoutAllResp")
+	proto.RegisterType((*RevokeSessionReq)(nil), "ericmoritz.users.RevokeSessionReq")
+	proto.RegisterType((*RevokeSessionResp)(nil), "ericmoritz.users.RevokeSessionResp")
+	proto.RegisterType((*ListSessionsReq)(nil), "ericmoritz.users.ListSessionsReq")
+	proto.RegisterType((*ListSessionsResp)(nil), "ericmoritz.users.ListSessionsResp")
+	proto.RegisterType((*ChangePasswordReq)(nil), "ericmoritz.users.ChangePasswordReq")
+	proto.RegisterType((*ChangePasswordResp)(nil), "ericmoritz.users.ChangePasswordResp")
+	proto.RegisterType((*RequestPasswordResetReq)(nil), "ericmoritz.users.RequestPasswordResetReq")
+	proto.RegisterType((*RequestPasswordResetResp)(nil), "ericmoritz.users.RequestPasswordResetResp")
+	proto.RegisterType((*ResetPasswordReq)(nil), "ericmoritz.users.ResetPasswordReq")
+	proto.RegisterType((*ResetPasswordResp)(nil), "ericmoritz.users.ResetPasswordResp")
+	proto.RegisterType((*VerifyEmailReq)(nil), "ericmoritz.users.VerifyEmailReq")
+	proto.RegisterType((*VerifyEmailResp)(nil), "ericmoritz.users.VerifyEmailResp")
+	proto.RegisterType((*ResendVerificationReq)(nil), "ericmoritz.users.ResendVerificationReq")
+	proto.RegisterType((*ResendVerificationResp)(nil), "ericmoritz.users.ResendVerificationResp")
+	proto.RegisterType((*UpdateProfileReq)(nil), "ericmoritz.users.UpdateProfileReq")
+	proto.RegisterType((*UpdateProfileResp)(nil), "ericmoritz.users.UpdateProfileResp")
+	proto.RegisterType((*GrantRoleReq)(nil), "ericmoritz.users.GrantRoleReq")
+	proto.RegisterType((*GrantRoleResp)(nil), "ericmoritz.users.GrantRoleResp")
+	proto.RegisterType((*RevokeRoleReq)(nil), "ericmoritz.users.RevokeRoleReq")
+	proto.RegisterType((*RevokeRoleResp)(nil), "ericmoritz.users.RevokeRoleResp")
+	proto.RegisterType((*AuthorizeReq)(nil), "ericmoritz.users.AuthorizeReq")
+	proto.RegisterType((*AuthorizeResp)(nil), "ericmoritz.users.AuthorizeResp")
+	proto.RegisterType((*CreateGroupReq)(nil), "ericmoritz.users.CreateGroupReq")
+	proto.RegisterType((*CreateGroupResp)(nil), "ericmoritz.users.CreateGroupResp")
+	proto.RegisterType((*AddMemberReq)(nil), "ericmoritz.users.AddMemberReq")
+	proto.RegisterType((*AddMemberResp)(nil), "ericmoritz.users.AddMemberResp")
+	proto.RegisterType((*RemoveMemberReq)(nil), "ericmoritz.users.RemoveMemberReq")
+	proto.RegisterType((*RemoveMemberResp)(nil), "ericmoritz.users.RemoveMemberResp")
+	proto.RegisterType((*ListGroupMembersReq)(nil), "ericmoritz.users.ListGroupMembersReq")
+	proto.RegisterType((*ListGroupMembersResp)(nil), "ericmoritz.users.ListGroupMembersResp")
+	proto.RegisterType((*ListUserGroupsReq)(nil), "ericmoritz.users.ListUserGroupsReq")
+	proto.RegisterType((*ListUserGroupsResp)(nil), "ericmoritz.users.ListUserGroupsResp")
+	proto.RegisterType((*ListUsersReq)(nil), "ericmoritz.users.ListUsersReq")
+	proto.RegisterType((*ListUsersResp)(nil), "ericmoritz.users.ListUsersResp")
+	proto.RegisterType((*DisableUserReq)(nil), "ericmoritz.users.DisableUserReq")
+	proto.RegisterType((*DisableUserResp)(nil), "ericmoritz.users.DisableUserResp")
+	proto.RegisterType((*DeleteUserReq)(nil), "ericmoritz.users.DeleteUserReq")
+	proto.RegisterType((*DeleteUserResp)(nil), "ericmoritz.users.DeleteUserResp")
+	proto.RegisterType((*RenameUserReq)(nil), "ericmoritz.users.RenameUserReq")
+	proto.RegisterType((*RenameUserResp)(nil), "ericmoritz.users.RenameUserResp")
+	proto.RegisterType((*EnrollTOTPReq)(nil), "ericmoritz.users.EnrollTOTPReq")
+	proto.RegisterType((*EnrollTOTPResp)(nil), "ericmoritz.users.EnrollTOTPResp")
+	proto.RegisterType((*ConfirmTOTPReq)(nil), "ericmoritz.users.ConfirmTOTPReq")
+	proto.RegisterType((*ConfirmTOTPResp)(nil), "ericmoritz.users.ConfirmTOTPResp")
+	proto.RegisterType((*DisableTOTPReq)(nil), "ericmoritz.users.DisableTOTPReq")
+	proto.RegisterType((*DisableTOTPResp)(nil), "ericmoritz.users.DisableTOTPResp")
+	proto.RegisterType((*VerifySecondFactorReq)(nil), "ericmoritz.users.VerifySecondFactorReq")
+	proto.RegisterType((*VerifySecondFactorResp)(nil), "ericmoritz.users.VerifySecondFactorResp")
+	proto.RegisterType((*BeginWebAuthnRegistrationReq)(nil), "ericmoritz.users.BeginWebAuthnRegistrationReq")
+	proto.RegisterType((*BeginWebAuthnRegistrationResp)(nil), "ericmoritz.users.BeginWebAuthnRegistrationResp")
+	proto.RegisterType((*FinishWebAuthnRegistrationReq)(nil), "ericmoritz.users.FinishWebAuthnRegistrationReq")
+	proto.RegisterType((*FinishWebAuthnRegistrationResp)(nil), "ericmoritz.users.FinishWebAuthnRegistrationResp")
+	proto.RegisterType((*BeginWebAuthnLoginReq)(nil), "ericmoritz.users.BeginWebAuthnLoginReq")
+	proto.RegisterType((*BeginWebAuthnLoginResp)(nil), "ericmoritz.users.BeginWebAuthnLoginResp")
+	proto.RegisterType((*FinishWebAuthnLoginReq)(nil), "ericmoritz.users.FinishWebAuthnLoginReq")
+	proto.RegisterType((*FinishWebAuthnLoginResp)(nil), "ericmoritz.users.FinishWebAuthnLoginResp")
+	proto.RegisterType((*ListAuditEventsReq)(nil), "ericmoritz.users.ListAuditEventsReq")
+	proto.RegisterType((*ListAuditEventsResp)(nil), "ericmoritz.users.ListAuditEventsResp")
 	proto.RegisterType((*User)(nil), "ericmoritz.users.User")
+	proto.RegisterType((*WebAuthnCredential)(nil), "ericmoritz.users.WebAuthnCredential")
+	proto.RegisterType((*Group)(nil), "ericmoritz.users.Group")
+	proto.RegisterType((*Member)(nil), "ericmoritz.users.Member")
+	proto.RegisterType((*RoleGrant)(nil), "ericmoritz.users.RoleGrant")
+	proto.RegisterType((*Profile)(nil), "ericmoritz.users.Profile")
+	proto.RegisterType((*AuditEvent)(nil), "ericmoritz.users.AuditEvent")
+	proto.RegisterType((*AuditFilter)(nil), "ericmoritz.users.AuditFilter")
 	proto.RegisterType((*Session)(nil), "ericmoritz.users.Session")
+	proto.RegisterType((*SessionInfo)(nil), "ericmoritz.users.SessionInfo")
 	proto.RegisterType((*PrivateUser)(nil), "ericmoritz.users.PrivateUser")
+	proto.RegisterType((*PrivateWebAuthnCredential)(nil), "ericmoritz.users.PrivateWebAuthnCredential")
+	proto.RegisterType((*PrivateSession)(nil), "ericmoritz.users.PrivateSession")
+	proto.RegisterType((*PrivateRenamedUser)(nil), "ericmoritz.users.PrivateRenamedUser")
+	proto.RegisterType((*PrivateResetToken)(nil), "ericmoritz.users.PrivateResetToken")
+	proto.RegisterType((*PrivateLoginChallenge)(nil), "ericmoritz.users.PrivateLoginChallenge")
+	proto.RegisterType((*PrivateVerificationToken)(nil), "ericmoritz.users.PrivateVerificationToken")
+	proto.RegisterType((*PrivateGroup)(nil), "ericmoritz.users.PrivateGroup")
 }
 
 func init() { proto.RegisterFile("rpc/users/service.proto", fileDescriptor0) }
 
 var fileDescriptor0 = []byte{
-	// 367 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0x51, 0x4b, 0x32, 0x41,
-	0x14, 0x45, 0x71, 0xbf, 0x5d, 0xef, 0x8a, 0xdf, 0xc7, 0xf0, 0x51, 0xba, 0x51, 0xd8, 0x42, 0x11,
-	0x3d, 0xac, 0xa0, 0xe4, 0x43, 0x21, 0x88, 0xe1, 0x43, 0xd0, 0x43, 0xad, 0xf4, 0xd2, 0xdb, 0x66,
-	0x17, 0x1b, 0xc2, 0x9d, 0x71, 0xee, 0x6a, 0xd0, 0x1f, 0xea, 0x6f, 0xc6, 0xce, 0xee, 0x96, 0xba,
-	0xa8, 0x58, 0x8f, 0x77, 0xce, 0xd9, 0x73, 0xce, 0xdc, 0x39, 0x0b, 0xfb, 0x4a, 0x8e, 0x9a, 0x33,
-	0x42, 0x45, 0x4d, 0x42, 0x35, 0xe7, 0x23, 0xf4, 0xa4, 0x12, 0x91, 0x60, 0xff, 0x50, 0xf1, 0xd1,
-	0x44, 0x28, 0x1e, 0xbd, 0x7b, 0x1a, 0x77, 0x07, 0x60, 0xfb, 0x38, 0xe6, 0x14, 0xa1, 0xf2, 0x71,
-	0xca, 0x1c, 0xb0, 0xe2, 0xf3, 0x30, 0x98, 0x60, 0xad, 0xd0, 0x28, 0x9c, 0x95, 0xfd, 0xaf, 0x39,
-	0xc6, 0x64, 0x40, 0xf4, 0x26, 0xd4, 0x73, 0xad, 0x98, 0x60, 0xd9, 0xec, 0x5e, 0x42, 0xe5, 0x5b,
-	0x86, 0x24, 0x3b, 0x87, 0x52, 0xfc, 0x9d, 0xd6, 0xb0, 0x5b, 0x7b, 0xde, 0xaa, 0xaf, 0xf7, 0x40,
-	0xa8, 0x7c, 0xcd, 0x71, 0xfb, 0x60, 0xdd, 0x8a, 0x31, 0x0f, 0x7f, 0xe3, 0xdf, 0x83, 0x72, 0xaa,
-	0x41, 0x92, 0xb5, 0xc1, 0x24, 0x24, 0xe2, 0x22, 0x4c, 0xfd, 0xeb, 0x79, 0xff, 0x61, 0x42, 0xf0,
-	0x33, 0xa6, 0x7b, 0x02, 0xa6, 0xce, 0xb4, 0x12, 0xa2, 0xb8, 0x1c, 0xc2, 0xed, 0x80, 0x95, 0xd0,
-	0x76, 0xbc, 0xe4, 0x00, 0xaa, 0xd7, 0x33, 0xa5, 0x30, 0x8c, 0x32, 0x97, 0x1f, 0xa5, 0xec, 0xc2,
-	0xdf, 0x25, 0x99, 0x1d, 0x53, 0xb8, 0x50, 0x8a, 0xa7, 0x4d, 0x6b, 0x76, 0xaf, 0xc0, 0x4c, 0x6d,
-	0xd9, 0x7f, 0x30, 0x22, 0xf1, 0x8a, 0x61, 0xca, 0x49, 0x86, 0x8d, 0xeb, 0xb9, 0x07, 0xfb, 0x4e,
-	0xf1, 0x79, 0x10, 0xe1, 0x36, 0x1f, 0x76, 0x0a, 0xd5, 0xec, 0xf9, 0x86, 0x2f, 0x41, 0xeb, 0xa2,
-	0xa3, 0xc5, 0x2a, 0xfe, 0xca, 0x69, 0xeb, 0xa3, 0x08, 0x46, 0x2c, 0x46, 0xec, 0x06, 0xac, 0xac,
-	0x64, 0xec, 0x30, 0x7f, 0xcf, 0x85, 0x1e, 0x3b, 0x47, 0x9b, 0x60, 0x92, 0xac, 0x07, 0x86, 0xee,
-	0x0b, 0x73, 0xf2, 0xc4, 0xac, 0x8c, 0xce, 0xc1, 0x5a, 0x8c, 0x24, 0xeb, 0xa6, 0xab, 0xac, 0xaf,
-	0x59, 0x38, 0x4e, 0x1d, 0x67, 0x1d, 0x44, 0x92, 0xf9, 0x60, 0x2f, 0x3c, 0x24, 0x6b, 0xe4, 0xa9,
-	0xcb, 0x75, 0x71, 0x8e, 0xb7, 0x30, 0x48, 0xf6, 0xcd, 0x47, 0x43, 0x03, 0x4f, 0x7f, 0xf4, 0xdf,
-	0xde, 0xfe, 0x0c, 0x00, 0x00, 0xff, 0xff, 0x4e, 0x5d, 0xbb, 0xcd, 0x08, 0x04, 0x00, 0x00,
+	// 3159 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x3b, 0x4b, 0x6f, 0x1c, 0xc7,
+	0xd1, 0x98, 0x5d, 0x2e, 0xc9, 0xad, 0x7d, 0x90, 0x6c, 0xbd, 0x56, 0x63, 0x49, 0x24, 0x5b, 0xb2,
+	0x4c, 0xf9, 0x41, 0xfb, 0xa3, 0x3e, 0x1b, 0xb6, 0x13, 0x23, 0xa6, 0x29, 0xca, 0xa6, 0x23, 0x5b,
+	0xc2, 0x50, 0xb4, 0x13, 0x1b, 0xf1, 0x64, 0xb8, 0xd3, 0x24, 0xc7, 0x1a, 0xce, 0xb4, 0xa6, 0x7b,
+	0x29, 0x51, 0x71, 0xe0, 0x04, 0x39, 0x05, 0x09, 0x72, 0xcc, 0x25, 0xc8, 0x31, 0x81, 0x73, 0xc9,
+	0x39, 0x87, 0x20, 0x40, 0x02, 0x04, 0xf9, 0x01, 0x41, 0xce, 0xf9, 0x19, 0xb9, 0x06, 0xfd, 0x9a,
+	0x9d, 0xd9, 0x9d, 0x7d, 0x70, 0x69, 0x05, 0xbe, 0x6d, 0x57, 0xd5, 0x54, 0x57, 0x57, 0x57, 0x57,
+	0x55, 0x77, 0xd5, 0xc2, 0x85, 0x84, 0xb6, 0x5f, 0xee, 0x30, 0x92, 0xb0, 0x97, 0x19, 0x49, 0x8e,
+	0x82, 0x36, 0x59, 0xa5, 0x49, 0xcc, 0x63, 0x34, 0x4f, 0x92, 0xa0, 0x7d, 0x18, 0x27, 0x01, 0x7f,
+	0xb2, 0x2a, 0xf1, 0xf8, 0x53, 0xa8, 0x39, 0x64, 0x3f, 0x60, 0x9c, 0x24, 0x0e, 0x79, 0x88, 0x6c,
+	0x98, 0x15, 0xf0, 0xc8, 0x3b, 0x24, 0x2d, 0x6b, 0xc9, 0x5a, 0xa9, 0x3a, 0xe9, 0x58, 0xe0, 0xa8,
+	0xc7, 0xd8, 0xa3, 0x38, 0xf1, 0x5b, 0x25, 0x85, 0x33, 0x63, 0x74, 0x16, 0x2a, 0xe4, 0xd0, 0x0b,
+	0xc2, 0x56, 0x59, 0x22, 0xd4, 0x00, 0xbf, 0x09, 0xf5, 0x2e, 0x73, 0x46, 0xd1, 0xf3, 0x30, 0x25,
+	0xb8, 0x49, 0xce, 0xb5, 0xb5, 0xf3, 0xab, 0xbd, 0xd2, 0xac, 0xee, 0x30, 0x92, 0x38, 0x92, 0x06,
+	0xbf, 0x03, 0xb3, 0x77, 0xe2, 0xfd, 0x20, 0x3a, 0x85, 0x54, 0xf8, 0x31, 0x54, 0x35, 0x0f, 0x46,
+	0xd1, 0x4d, 0x98, 0x61, 0x84, 0xb1, 0x20, 0x8e, 0xf4, 0xfc, 0x17, 0xfb, 0xe7, 0xdf, 0x56, 0x04,
+	0x8e, 0xa1, 0x44, 0xaf, 0xc1, 0x05, 0x46, 0xda, 0x71, 0xe4, 0xbb, 0x7b, 0x5e, 0x9b, 0xc7, 0x89,
+	0xdb, 0x3e, 0xf0, 0xc2, 0x90, 0x44, 0xfb, 0x44, 0x4f, 0x76, 0x4e, 0xa1, 0x6f, 0x4b, 0xec, 0x86,
+	0x41, 0xe2, 0x4f, 0x60, 0x46, 0xae, 0x85, 0x3c, 0x9c, 0x6c, 0xde, 0xec, 0x8a, 0x4b, 0xf9, 0x15,
+	0xe3, 0xd7, 0x60, 0x76, 0x87, 0x4d, 0xa0, 0xd1, 0x4d, 0x68, 0x6e, 0x74, 0x92, 0x84, 0x44, 0xfc,
+	0x34, 0xa2, 0xe1, 0xb7, 0x60, 0x2e, 0xc7, 0xe6, 0x84, 0x52, 0xbc, 0x2d, 0xf7, 0x24, 0xee, 0xf0,
+	0x89, 0x05, 0xa8, 0x03, 0x18, 0x0e, 0x8c, 0xe2, 0x0d, 0xa8, 0xab, 0xd1, 0x7a, 0x18, 0x4e, 0xcc,
+	0xf2, 0x06, 0x34, 0x32, 0x4c, 0x18, 0x45, 0x2d, 0x98, 0x49, 0xc8, 0x51, 0xfc, 0x80, 0xf8, 0x92,
+	0x4b, 0xc5, 0x31, 0x43, 0xfc, 0x05, 0xcc, 0x3b, 0xf2, 0xa7, 0x61, 0x32, 0xe9, 0x16, 0x9f, 0x85,
+	0x0a, 0x8f, 0x1f, 0x90, 0x48, 0xef, 0xaf, 0x1a, 0xa0, 0xcb, 0x00, 0x9a, 0xc0, 0x0d, 0x7c, 0x7d,
+	0x9a, 0xaa, 0x1a, 0xb2, 0xe5, 0xe3, 0x33, 0xb0, 0xd0, 0x33, 0x3b, 0xa3, 0xf8, 0x36, 0xcc, 0xdd,
+	0x09, 0x18, 0xd7, 0x20, 0x36, 0xb1, 0x16, 0x3e, 0x80, 0xf9, 0x3c, 0x1f, 0x46, 0xd1, 0x1b, 0x30,
+	0xab, 0xd1, 0xac, 0x65, 0x2d, 0x95, 0x57, 0x6a, 0x6b, 0x97, 0x07, 0x72, 0xda, 0x8a, 0xf6, 0x62,
+	0x27, 0x25, 0xc7, 0x7f, 0xb3, 0x60, 0x61, 0xe3, 0xc0, 0x8b, 0xf6, 0xc9, 0x3d, 0x7d, 0x20, 0x27,
+	0xd6, 0xd5, 0x32, 0xd4, 0xe3, 0xd0, 0x77, 0x7b, 0x0e, 0x7a, 0x2d, 0x0e, 0x7d, 0xc3, 0x5a, 0x90,
+	0x44, 0xe4, 0x51, 0x97, 0x44, 0xa9, 0xae, 0x16, 0x91, 0x47, 0x29, 0xc9, 0x1a, 0x9c, 0x53, 0xbb,
+	0xe8, 0xc6, 0xfc, 0x80, 0x24, 0x6e, 0xba, 0xb0, 0xa9, 0x25, 0x6b, 0x65, 0xd6, 0x39, 0xa3, 0x90,
+	0x77, 0x05, 0xce, 0xe8, 0x00, 0xaf, 0x02, 0xea, 0x5d, 0xc3, 0x50, 0xf3, 0xd8, 0x84, 0x0b, 0x0e,
+	0x79, 0xd8, 0x21, 0x8c, 0x67, 0x3e, 0x20, 0xd2, 0xd8, 0x9f, 0x87, 0x05, 0x73, 0x86, 0xdd, 0x38,
+	0x71, 0x95, 0xbf, 0x54, 0xee, 0x6c, 0xce, 0x20, 0xee, 0x26, 0x9b, 0xd2, 0x73, 0xda, 0xd0, 0x2a,
+	0x66, 0xc3, 0x28, 0xfe, 0x48, 0x58, 0x20, 0x23, 0x3c, 0xab, 0xd5, 0x45, 0xa8, 0x25, 0x02, 0xe6,
+	0x2a, 0x93, 0x52, 0x5c, 0x41, 0x82, 0xee, 0x0b, 0x48, 0x9f, 0x7a, 0x4a, 0x7d, 0xea, 0xc1, 0x2f,
+	0xc1, 0x42, 0x0f, 0xdf, 0xa1, 0x2b, 0xbd, 0x0e, 0xcd, 0x8f, 0x48, 0x12, 0xec, 0x1d, 0x4b, 0x89,
+	0x85, 0x10, 0xa9, 0x45, 0x5b, 0x19, 0x8b, 0x16, 0xfe, 0x22, 0x47, 0x77, 0x42, 0x7f, 0x71, 0x07,
+	0xce, 0x09, 0xa9, 0x22, 0x5f, 0x32, 0x09, 0xda, 0x1e, 0x3f, 0xc5, 0xa1, 0xc3, 0x2d, 0x38, 0x5f,
+	0xc4, 0x8d, 0x51, 0xfc, 0x5b, 0x0b, 0xe6, 0x77, 0xa8, 0xef, 0x71, 0x72, 0x2f, 0x89, 0xf7, 0x82,
+	0x90, 0x4c, 0x6c, 0xac, 0x37, 0x61, 0x86, 0x2a, 0x16, 0xad, 0xd2, 0xa0, 0x8f, 0xcc, 0x1c, 0x86,
+	0x52, 0x6c, 0x60, 0x47, 0xce, 0xee, 0x1e, 0x7a, 0xec, 0x41, 0xab, 0xbc, 0x54, 0x16, 0x1b, 0xa8,
+	0x40, 0x1f, 0x78, 0xec, 0x01, 0xfe, 0x0e, 0x2c, 0xf4, 0x88, 0x77, 0x42, 0x45, 0xfe, 0xce, 0x82,
+	0xfa, 0xbb, 0x89, 0x17, 0x71, 0x27, 0x0e, 0xc9, 0xd3, 0x08, 0x4c, 0x08, 0xc1, 0x54, 0x12, 0x87,
+	0x44, 0x1f, 0x3d, 0xf9, 0x5b, 0xd0, 0x27, 0x84, 0xc5, 0x9d, 0xa4, 0x4d, 0xe4, 0x31, 0xab, 0x3a,
+	0xe9, 0x58, 0xd8, 0xcb, 0x7e, 0x12, 0x77, 0x68, 0xab, 0xa2, 0xec, 0x45, 0x0e, 0xf0, 0x1c, 0x34,
+	0x32, 0x62, 0x32, 0x8a, 0x7f, 0x6f, 0x41, 0x43, 0x39, 0xbd, 0x6f, 0xb8, 0xe4, 0xf3, 0xd0, 0xcc,
+	0xca, 0xc9, 0x28, 0xfe, 0x12, 0xea, 0xeb, 0x1d, 0x7e, 0x10, 0x27, 0xc1, 0x93, 0xc9, 0x05, 0xbf,
+	0x02, 0x40, 0x49, 0x72, 0x18, 0xa8, 0xef, 0x94, 0xe8, 0x19, 0x48, 0x4e, 0xd0, 0x72, 0x5e, 0x50,
+	0xfc, 0x03, 0x68, 0x64, 0x04, 0x50, 0xe7, 0xd9, 0x0b, 0xc3, 0xf8, 0x91, 0x3e, 0xcf, 0xb3, 0x8e,
+	0x19, 0xa2, 0xf3, 0x30, 0x9d, 0x10, 0x8f, 0xa5, 0x53, 0xe8, 0x51, 0x4e, 0x6f, 0xe5, 0x9e, 0x54,
+	0xe4, 0x47, 0xd0, 0xdc, 0x48, 0x88, 0xc7, 0xc9, 0xbb, 0x42, 0x01, 0x13, 0xaf, 0x10, 0xc1, 0x54,
+	0x66, 0x5b, 0xe4, 0x6f, 0xb4, 0x04, 0x35, 0x9f, 0xb0, 0x76, 0x12, 0x50, 0x71, 0x44, 0x8d, 0x3b,
+	0xcf, 0x80, 0xf0, 0xdb, 0x30, 0x97, 0x9b, 0x9c, 0x51, 0xf4, 0x92, 0xd9, 0x17, 0x35, 0xf7, 0x85,
+	0xfe, 0xb9, 0x15, 0xad, 0xde, 0xb0, 0x5f, 0x58, 0x50, 0x5f, 0xf7, 0xfd, 0x0f, 0xc8, 0xe1, 0xee,
+	0x29, 0x72, 0xb5, 0xd4, 0x18, 0x4a, 0x19, 0x63, 0x40, 0xaf, 0xc0, 0xf4, 0xa1, 0xe4, 0x2b, 0x45,
+	0xaf, 0xad, 0xb5, 0xfa, 0x39, 0xe9, 0x79, 0x35, 0x9d, 0x30, 0xfc, 0x8c, 0x30, 0x8c, 0xe2, 0x5f,
+	0x59, 0x30, 0xe7, 0x90, 0xc3, 0xf8, 0x88, 0x7c, 0x43, 0x24, 0x44, 0x30, 0x9f, 0x97, 0x87, 0x51,
+	0xfc, 0x13, 0x0b, 0xce, 0x88, 0xac, 0x41, 0x2a, 0x56, 0xc1, 0xd9, 0xd7, 0x2c, 0xe8, 0x15, 0x00,
+	0x9e, 0x78, 0x11, 0x0b, 0x78, 0x70, 0xa4, 0x6c, 0x70, 0xd6, 0xc9, 0x40, 0xf0, 0xfb, 0x70, 0xb6,
+	0x5f, 0x02, 0x46, 0xd1, 0x1a, 0xcc, 0x28, 0xc1, 0x4d, 0xea, 0x32, 0x78, 0x85, 0x86, 0x10, 0xfb,
+	0xb0, 0x20, 0x78, 0x09, 0xbf, 0x29, 0xf9, 0xb1, 0xa7, 0x92, 0xc2, 0xbf, 0x08, 0xa8, 0x77, 0x16,
+	0x46, 0xc5, 0x09, 0x94, 0x0b, 0x56, 0xe2, 0x56, 0x1d, 0x3d, 0xc2, 0x5f, 0x59, 0x50, 0x37, 0xe4,
+	0x93, 0xcb, 0xf3, 0x0c, 0x54, 0xa9, 0xb7, 0x4f, 0x5c, 0x16, 0x3c, 0x51, 0x02, 0x55, 0xc4, 0x4d,
+	0x69, 0x9f, 0x6c, 0x07, 0x4f, 0x88, 0x48, 0x3b, 0x25, 0x52, 0xc5, 0x6f, 0x9d, 0x76, 0x0a, 0x88,
+	0xca, 0x1e, 0x9e, 0x83, 0x34, 0x43, 0x71, 0x69, 0x42, 0xf6, 0x82, 0xc7, 0xda, 0x25, 0x36, 0x0d,
+	0xf8, 0x9e, 0x84, 0x62, 0x02, 0x8d, 0x8c, 0xa4, 0x8c, 0xa2, 0x17, 0xa1, 0x22, 0xe5, 0xd1, 0x3b,
+	0x30, 0x28, 0x44, 0x29, 0x22, 0x74, 0x1d, 0xe6, 0x22, 0xf2, 0x98, 0xbb, 0x19, 0x59, 0x94, 0xea,
+	0x1a, 0x02, 0x7c, 0xcf, 0xc8, 0x83, 0x3d, 0x68, 0xde, 0x0a, 0x98, 0xb7, 0x1b, 0x92, 0xa7, 0x76,
+	0xcb, 0x7a, 0x01, 0xe6, 0x72, 0x53, 0x0c, 0xcd, 0x85, 0x7e, 0x08, 0x8d, 0x5b, 0x24, 0x24, 0xfc,
+	0xe9, 0x89, 0x33, 0x0f, 0xcd, 0xec, 0x0c, 0x8c, 0xe2, 0x9f, 0xc9, 0xb0, 0x28, 0x90, 0x4f, 0x6b,
+	0x52, 0x93, 0x34, 0xf6, 0xb8, 0x7f, 0x91, 0x34, 0xee, 0x18, 0xb9, 0xbe, 0x2d, 0x62, 0x5e, 0x57,
+	0x88, 0x13, 0xe6, 0x24, 0xb7, 0xa0, 0xb1, 0x19, 0x25, 0x71, 0x18, 0xde, 0xbf, 0x7b, 0xff, 0xde,
+	0xc4, 0x49, 0xdd, 0x16, 0x34, 0xb3, 0x5c, 0xd4, 0x49, 0x62, 0xa4, 0x9d, 0x10, 0xae, 0x53, 0x51,
+	0x3d, 0x12, 0x59, 0x56, 0xcc, 0xa9, 0xd7, 0xe1, 0x07, 0x6e, 0x27, 0x09, 0x4d, 0x2c, 0xd5, 0xa0,
+	0x9d, 0x24, 0xc4, 0xdf, 0x87, 0xe6, 0x46, 0x1c, 0xed, 0x05, 0xc9, 0xe1, 0x69, 0x24, 0x12, 0x01,
+	0xad, 0x1d, 0xfb, 0x69, 0x40, 0x13, 0xbf, 0xf1, 0xeb, 0x30, 0x97, 0x63, 0xcd, 0x28, 0x7a, 0x16,
+	0x9a, 0x09, 0x69, 0xc7, 0x47, 0x24, 0x39, 0x76, 0x05, 0x8d, 0x39, 0xf8, 0x0d, 0x03, 0xdd, 0x10,
+	0x40, 0x21, 0x94, 0x36, 0xc5, 0xaf, 0x5d, 0xa8, 0x05, 0x98, 0xcb, 0xb1, 0x66, 0x14, 0x6f, 0xc1,
+	0x39, 0x95, 0xaf, 0x6f, 0x67, 0x5e, 0x36, 0xc4, 0xa4, 0x97, 0xa0, 0xda, 0x7d, 0xfd, 0x50, 0x7a,
+	0xed, 0x02, 0x0a, 0xb9, 0xff, 0x18, 0xce, 0x17, 0xb1, 0x9a, 0xf4, 0x31, 0x66, 0x15, 0xce, 0xe4,
+	0xd5, 0xe5, 0x86, 0x64, 0x8f, 0x6b, 0x5f, 0xb6, 0x90, 0xd3, 0xd9, 0x1d, 0xb2, 0xc7, 0xf1, 0x36,
+	0x5c, 0x7a, 0x87, 0xec, 0x07, 0xd1, 0xc7, 0x64, 0x57, 0x24, 0x41, 0x91, 0x7a, 0x8b, 0x4a, 0x4e,
+	0x77, 0x83, 0x78, 0x03, 0x2e, 0x0f, 0x61, 0xaa, 0xbc, 0x44, 0x2c, 0x13, 0x14, 0xa6, 0x95, 0x64,
+	0x86, 0xf8, 0xaf, 0x16, 0x5c, 0xbe, 0x1d, 0x44, 0x01, 0x3b, 0xf8, 0x3a, 0x25, 0x42, 0x2b, 0x30,
+	0xdf, 0x0e, 0x03, 0x12, 0x71, 0xd7, 0xf7, 0xb8, 0xe7, 0x7e, 0x6e, 0x52, 0xb8, 0xba, 0xd3, 0x54,
+	0xf0, 0x5b, 0x1e, 0xf7, 0xde, 0x17, 0xa9, 0xdc, 0x4b, 0x80, 0x3c, 0xce, 0x09, 0xe3, 0x72, 0x42,
+	0x37, 0xde, 0xfd, 0x9c, 0xb4, 0xb9, 0x3c, 0xd5, 0x75, 0x67, 0x21, 0x83, 0xb9, 0x2b, 0x11, 0x69,
+	0x5a, 0x36, 0xd5, 0x4d, 0xcb, 0xf0, 0x1e, 0x5c, 0x19, 0xb6, 0x04, 0x46, 0xd1, 0x2d, 0x80, 0x76,
+	0x42, 0x7c, 0x12, 0xf1, 0xc0, 0x0b, 0xf5, 0x32, 0xae, 0xf5, 0x2f, 0xc3, 0x7c, 0xbf, 0x91, 0xd2,
+	0x3a, 0x99, 0xef, 0xf0, 0x4d, 0x38, 0x97, 0x53, 0xf3, 0x38, 0x6f, 0x81, 0x78, 0x0d, 0xce, 0x17,
+	0x7d, 0x34, 0x74, 0x53, 0xfe, 0x65, 0xc1, 0xf9, 0xfc, 0x8a, 0xd2, 0xa9, 0xae, 0x42, 0xa3, 0x2b,
+	0x91, 0x78, 0x8e, 0xb1, 0xa4, 0xa6, 0xea, 0x5d, 0xe0, 0x96, 0x7f, 0x42, 0xed, 0x77, 0xf8, 0x81,
+	0xf8, 0xb2, 0xed, 0x89, 0xb7, 0x44, 0xf1, 0x41, 0xaa, 0xfd, 0x2c, 0x46, 0x7c, 0x22, 0x8e, 0x1b,
+	0x0b, 0xf6, 0x23, 0x8f, 0x77, 0x12, 0xb5, 0x05, 0x75, 0xa7, 0x0b, 0x90, 0xf7, 0x45, 0x46, 0x12,
+	0xf7, 0xc0, 0x8b, 0xfc, 0x90, 0xc8, 0x7b, 0x48, 0xdd, 0x01, 0x01, 0x7a, 0x4f, 0x42, 0xf0, 0x87,
+	0x70, 0xa1, 0x70, 0x59, 0x13, 0x1e, 0x3e, 0xfc, 0x67, 0x4b, 0xe5, 0x2c, 0xeb, 0x1d, 0x3f, 0xe0,
+	0x9b, 0x47, 0x24, 0xe2, 0x93, 0xa7, 0x22, 0xaf, 0xc2, 0xf4, 0x5e, 0x10, 0x72, 0x92, 0xe8, 0x0b,
+	0x72, 0xc1, 0x93, 0x92, 0x9c, 0xe6, 0xb6, 0x24, 0x72, 0x34, 0xf1, 0xa8, 0x24, 0x25, 0x97, 0xe0,
+	0x4c, 0xe5, 0x13, 0x1c, 0xcc, 0x54, 0x96, 0x9a, 0x93, 0x9e, 0x51, 0xf4, 0xff, 0x30, 0x4d, 0xe4,
+	0x48, 0xe7, 0x27, 0x97, 0x06, 0x48, 0x22, 0x3f, 0x71, 0x34, 0xed, 0xd8, 0x69, 0xca, 0xbf, 0x4b,
+	0x30, 0x25, 0xa2, 0xdd, 0xd0, 0x07, 0xec, 0xf4, 0xe9, 0xbc, 0x94, 0x79, 0x3a, 0x17, 0xa1, 0x41,
+	0xfe, 0x70, 0x8f, 0xe4, 0x43, 0x05, 0xf1, 0x75, 0xde, 0xdb, 0x90, 0xd0, 0x8f, 0x34, 0x30, 0xfb,
+	0xd6, 0x30, 0x35, 0xf6, 0x5b, 0xc3, 0xff, 0x41, 0x25, 0x89, 0x43, 0xc2, 0x5a, 0x15, 0xb9, 0xe6,
+	0x67, 0xfa, 0x3f, 0x11, 0x17, 0x58, 0x75, 0x09, 0x57, 0x94, 0x62, 0x01, 0xbe, 0x8a, 0x13, 0x7e,
+	0x6b, 0x5a, 0x0a, 0x92, 0x8e, 0x45, 0x96, 0xc0, 0x63, 0x4e, 0x5d, 0x12, 0x29, 0xfc, 0x8c, 0xc4,
+	0xd7, 0x04, 0x6c, 0x53, 0x81, 0xd0, 0xc7, 0x70, 0xf6, 0x11, 0xd9, 0x15, 0x36, 0x1e, 0xb9, 0xdd,
+	0xd3, 0xc3, 0x5a, 0xb3, 0x4b, 0xe5, 0xb1, 0xbd, 0xc3, 0x19, 0xc3, 0xa1, 0x0b, 0x63, 0xf8, 0x18,
+	0x50, 0x3f, 0x29, 0x6a, 0x42, 0x49, 0x9f, 0xd6, 0xaa, 0x53, 0x0a, 0xfc, 0xc2, 0xfb, 0xe5, 0x65,
+	0xe9, 0xa6, 0x3c, 0x4e, 0x7c, 0xd7, 0x53, 0x3e, 0xb0, 0xec, 0x54, 0x35, 0x64, 0x9d, 0xa3, 0x25,
+	0xa8, 0x87, 0x1e, 0xe3, 0x22, 0xf7, 0x91, 0x04, 0x53, 0x92, 0x00, 0x04, 0x6c, 0x87, 0x09, 0x0a,
+	0x4c, 0xa1, 0x22, 0x73, 0xf7, 0x94, 0xbb, 0x35, 0xf8, 0xf6, 0x5a, 0xea, 0xbb, 0xbd, 0x76, 0x37,
+	0xa1, 0x3c, 0xee, 0x26, 0xe0, 0x37, 0x61, 0x5a, 0x5d, 0x57, 0x46, 0xd9, 0x53, 0xff, 0x1d, 0x0a,
+	0x7f, 0x0b, 0xaa, 0x29, 0xbf, 0xf4, 0xb9, 0xc3, 0x1a, 0xf0, 0xdc, 0x51, 0xea, 0x79, 0x45, 0xf8,
+	0x79, 0x09, 0x66, 0xb4, 0x15, 0x89, 0xdd, 0xf6, 0x03, 0x46, 0x43, 0xef, 0xd8, 0xcd, 0x4c, 0x5f,
+	0xd3, 0xb0, 0x0f, 0xb5, 0x6a, 0xbd, 0x23, 0x8f, 0x7b, 0x49, 0x26, 0xc9, 0xaa, 0x2a, 0xc8, 0x4e,
+	0x12, 0x8a, 0xe4, 0x2c, 0x8c, 0xdb, 0x5e, 0xfa, 0xdc, 0xa2, 0x47, 0x42, 0x02, 0x1e, 0x1c, 0x92,
+	0x27, 0x71, 0x94, 0x3e, 0xb8, 0x98, 0x31, 0xda, 0x02, 0xf0, 0x38, 0x4f, 0x82, 0xdd, 0x0e, 0x4f,
+	0xed, 0xf6, 0xc6, 0x40, 0x53, 0x5f, 0x5d, 0x4f, 0x69, 0x37, 0x23, 0x9e, 0x1c, 0x3b, 0x99, 0x8f,
+	0xed, 0xb7, 0x60, 0xae, 0x07, 0x8d, 0xe6, 0xa1, 0xfc, 0x80, 0x1c, 0xeb, 0xa5, 0x88, 0x9f, 0x42,
+	0x89, 0x47, 0x5e, 0xd8, 0x31, 0xaa, 0x50, 0x83, 0x37, 0x4b, 0xaf, 0x5b, 0xf8, 0x1f, 0x16, 0x40,
+	0xd7, 0x25, 0x14, 0x99, 0x9a, 0x10, 0x5a, 0x7e, 0x57, 0x76, 0xe4, 0x6f, 0xb1, 0x60, 0xaf, 0x9d,
+	0x79, 0xc5, 0xd0, 0x23, 0x31, 0x89, 0xcc, 0x88, 0xf4, 0x6a, 0xd5, 0x40, 0x84, 0x2a, 0xd6, 0x51,
+	0x91, 0x59, 0xbd, 0x2e, 0x99, 0xa1, 0x70, 0x70, 0x3a, 0xd4, 0x04, 0x54, 0x9e, 0xc2, 0xaa, 0x33,
+	0xab, 0x00, 0x5b, 0x2a, 0xc2, 0x75, 0x78, 0x3b, 0x3e, 0x24, 0xad, 0x19, 0x1d, 0xe1, 0xd4, 0x50,
+	0x4c, 0xef, 0x13, 0x2e, 0x3c, 0xcc, 0xac, 0x9a, 0x5e, 0x8d, 0xf0, 0x9f, 0x2c, 0xa8, 0x65, 0xdc,
+	0x6c, 0x46, 0x4c, 0xab, 0x58, 0xcc, 0xd2, 0x00, 0x31, 0xcb, 0x43, 0xc4, 0x9c, 0x1a, 0x2c, 0x66,
+	0x25, 0x2f, 0xe6, 0x59, 0xa8, 0xb0, 0x20, 0x6a, 0x13, 0xb9, 0xb2, 0xb2, 0xa3, 0x06, 0x02, 0xda,
+	0x89, 0x78, 0x10, 0xca, 0x45, 0x95, 0x1d, 0x35, 0xc0, 0xbf, 0xb1, 0x60, 0x66, 0xbb, 0xb7, 0x8e,
+	0x92, 0x7d, 0x75, 0x1e, 0x7a, 0xad, 0x19, 0xf3, 0xe8, 0x33, 0x42, 0xa2, 0x9e, 0xa3, 0xbf, 0x4d,
+	0x48, 0xb4, 0xce, 0x05, 0x03, 0xf2, 0x98, 0x06, 0x09, 0x61, 0x02, 0x5f, 0x51, 0x0c, 0x34, 0x64,
+	0x9d, 0xe3, 0x7f, 0x5a, 0x50, 0xcb, 0x94, 0x44, 0xfa, 0x6c, 0x24, 0x3f, 0x7f, 0x69, 0xd4, 0xfc,
+	0xe5, 0x11, 0xf3, 0x4f, 0xf5, 0xcc, 0x9f, 0xdf, 0x80, 0x4a, 0xcf, 0x06, 0x5c, 0x06, 0x99, 0x25,
+	0xb8, 0xde, 0x3e, 0x89, 0xb8, 0xb6, 0xa2, 0xaa, 0x80, 0xac, 0x0b, 0x80, 0xd8, 0x9f, 0xb6, 0xaa,
+	0xee, 0x69, 0x3f, 0x6e, 0x86, 0xf8, 0xa7, 0x15, 0xa8, 0xdd, 0x4b, 0x82, 0x23, 0x4f, 0xdd, 0x41,
+	0x87, 0xfa, 0xa0, 0xeb, 0xd0, 0x34, 0x95, 0x86, 0xed, 0x03, 0x6f, 0xed, 0xd5, 0xd7, 0x4c, 0x4a,
+	0x94, 0x87, 0x22, 0x0c, 0x75, 0x03, 0x79, 0xcf, 0x63, 0x07, 0xda, 0x92, 0x72, 0xb0, 0x6e, 0x7c,
+	0x9c, 0xca, 0xc6, 0xc7, 0x6b, 0x90, 0x8f, 0x84, 0xad, 0xca, 0x88, 0xf0, 0x38, 0x7d, 0xf2, 0xf0,
+	0x38, 0x33, 0x51, 0x78, 0x9c, 0xed, 0x09, 0x8f, 0x8b, 0x50, 0x33, 0xbf, 0xc5, 0x6e, 0x55, 0xd5,
+	0x6e, 0x1a, 0xd0, 0xba, 0x71, 0x21, 0x30, 0xc0, 0x3c, 0x6a, 0xbd, 0xe6, 0xb1, 0x08, 0x32, 0xb4,
+	0xba, 0xfa, 0x82, 0x5b, 0x57, 0x99, 0x9f, 0x00, 0x6d, 0x4b, 0x48, 0x5f, 0x3c, 0x6e, 0xf4, 0xc7,
+	0xe3, 0x6b, 0xd0, 0x94, 0x24, 0xca, 0xce, 0x38, 0xa1, 0xad, 0xa6, 0x9c, 0x46, 0x7e, 0x78, 0x47,
+	0x18, 0x1a, 0x27, 0xe2, 0x81, 0xf0, 0x6c, 0xee, 0xbe, 0xe5, 0x1e, 0x78, 0xec, 0x80, 0xb0, 0xd6,
+	0x9c, 0xbc, 0xa4, 0xa2, 0xec, 0x85, 0xeb, 0x3d, 0x89, 0x41, 0x9f, 0x0d, 0x88, 0xf3, 0xf3, 0x52,
+	0x93, 0x2f, 0x14, 0x29, 0x5f, 0x1a, 0xd4, 0xb8, 0xe1, 0xfe, 0x2f, 0x16, 0x5c, 0x1c, 0xf8, 0x49,
+	0xe6, 0x9c, 0xd5, 0x8d, 0x22, 0x69, 0x67, 0x37, 0x0c, 0xda, 0xae, 0xf0, 0xee, 0xca, 0x02, 0xab,
+	0x0a, 0xf2, 0x5d, 0x72, 0x2c, 0xd0, 0x22, 0x9f, 0x76, 0xdb, 0x71, 0x27, 0x52, 0xa7, 0xac, 0xa1,
+	0x32, 0xec, 0x0d, 0x01, 0x28, 0xba, 0xfd, 0xf4, 0x6c, 0x4d, 0x65, 0x54, 0xd2, 0x30, 0xdd, 0x97,
+	0x34, 0xfc, 0xc7, 0x82, 0xa6, 0x5e, 0xc0, 0x37, 0xd6, 0x7f, 0x0d, 0x8f, 0x33, 0x79, 0xff, 0x31,
+	0xd3, 0xeb, 0x3f, 0x2e, 0xc0, 0x8c, 0x44, 0x07, 0xbe, 0x89, 0x36, 0x62, 0xb8, 0xe5, 0xe3, 0x3f,
+	0x5a, 0x80, 0xf4, 0xca, 0xd5, 0x83, 0x91, 0x3f, 0xd2, 0x8b, 0x64, 0x78, 0x95, 0xb2, 0xbc, 0x84,
+	0x0c, 0x89, 0xe2, 0xe1, 0xf2, 0xd8, 0x5c, 0x04, 0x34, 0xe4, 0x7e, 0x9c, 0x45, 0x77, 0xdd, 0xa3,
+	0x86, 0xac, 0x73, 0xf5, 0xea, 0xc2, 0x48, 0x72, 0x44, 0x7c, 0x57, 0xc5, 0x16, 0xa5, 0x81, 0x86,
+	0x81, 0xee, 0xc8, 0x18, 0xf3, 0x4b, 0x0b, 0x16, 0x52, 0x81, 0xd3, 0x3a, 0xea, 0x65, 0x00, 0xb9,
+	0x41, 0xf2, 0x2c, 0x98, 0x57, 0x10, 0x09, 0x91, 0xce, 0xea, 0x14, 0xdb, 0x36, 0xdc, 0xa9, 0xe3,
+	0xbf, 0x5b, 0x70, 0x4e, 0x8b, 0x23, 0x6f, 0x72, 0x69, 0xaf, 0x89, 0x58, 0x4f, 0xfa, 0x0c, 0x93,
+	0x15, 0xab, 0x91, 0x42, 0xa5, 0x68, 0xc3, 0xb4, 0x39, 0xb9, 0x5c, 0x62, 0xc5, 0x7b, 0x5e, 0x10,
+	0x76, 0x12, 0x99, 0x97, 0xc9, 0x4b, 0x97, 0x19, 0x8b, 0x23, 0xf4, 0x20, 0x88, 0x7c, 0x6d, 0x43,
+	0xf2, 0x37, 0xfe, 0x83, 0x05, 0x2d, 0xbd, 0x8e, 0x6c, 0x0d, 0xf6, 0xd4, 0xda, 0x2d, 0xec, 0x40,
+	0xea, 0x59, 0xdb, 0xd4, 0xf0, 0xb5, 0xf5, 0x05, 0xf2, 0x5f, 0x5b, 0x50, 0xd7, 0xb2, 0xfe, 0x6f,
+	0x53, 0xfd, 0x11, 0x72, 0xaf, 0x7d, 0xd5, 0x82, 0x8a, 0x7c, 0x63, 0x47, 0x5b, 0x30, 0x6b, 0x5a,
+	0xac, 0x50, 0xc1, 0x35, 0x3a, 0xd3, 0xdb, 0x65, 0x5f, 0x19, 0x86, 0x66, 0x14, 0xbd, 0x0d, 0x15,
+	0x69, 0x59, 0xc8, 0xee, 0x27, 0x34, 0x6f, 0x22, 0xf6, 0x33, 0x03, 0x71, 0x8c, 0xa2, 0xb7, 0xf4,
+	0x75, 0xf7, 0xe2, 0x80, 0x47, 0x5f, 0xf2, 0xd0, 0xb6, 0x07, 0xa1, 0x18, 0x45, 0x0e, 0xd4, 0x32,
+	0x9d, 0x45, 0x68, 0xa9, 0x9f, 0x34, 0xdf, 0xbf, 0x64, 0x2f, 0x8f, 0xa0, 0x60, 0x14, 0x6d, 0xc0,
+	0xb4, 0xea, 0xec, 0x41, 0xc5, 0x92, 0xab, 0x46, 0x24, 0xfb, 0xd2, 0x60, 0x24, 0xa3, 0xe8, 0x8e,
+	0xe9, 0x59, 0x5a, 0x0f, 0x43, 0x74, 0x65, 0x10, 0xa9, 0x6a, 0x40, 0xb2, 0x17, 0x87, 0xe2, 0x19,
+	0x45, 0xdf, 0x33, 0xe5, 0x6c, 0x13, 0x01, 0x70, 0xd1, 0xc6, 0xe4, 0x5b, 0x8c, 0xec, 0xab, 0x23,
+	0x69, 0x18, 0x45, 0x3b, 0xaa, 0x4e, 0xa4, 0x41, 0x0c, 0x15, 0xe8, 0xa7, 0xa7, 0x51, 0xc8, 0xc6,
+	0xa3, 0x48, 0x18, 0x45, 0x9f, 0x42, 0x33, 0xdf, 0x03, 0x83, 0x0a, 0xa4, 0xe9, 0xeb, 0xf4, 0xb1,
+	0xaf, 0x8d, 0x26, 0x62, 0x14, 0x1d, 0xc2, 0xd9, 0xa2, 0x4e, 0x17, 0x74, 0xa3, 0x68, 0xc1, 0x85,
+	0x8d, 0x35, 0xf6, 0xf3, 0xe3, 0x92, 0x1a, 0xe5, 0x67, 0x9a, 0x5c, 0x8a, 0x95, 0x9f, 0xef, 0xae,
+	0xb1, 0xaf, 0x8e, 0xa4, 0x51, 0xd6, 0x9b, 0xe9, 0x73, 0x29, 0xb2, 0xde, 0x7c, 0xbb, 0x8c, 0xbd,
+	0x3c, 0x82, 0x82, 0x51, 0xb4, 0x0f, 0xa8, 0xbf, 0x5d, 0x05, 0x3d, 0x57, 0x2c, 0x4e, 0x5f, 0x8b,
+	0x8c, 0xbd, 0x32, 0x1e, 0xa1, 0x52, 0x4b, 0xae, 0xbb, 0xa4, 0x48, 0x2d, 0xbd, 0xdd, 0x31, 0xf6,
+	0xd5, 0x91, 0x34, 0xea, 0xec, 0xa4, 0xed, 0x1c, 0x45, 0x67, 0x27, 0xdb, 0x92, 0x62, 0x2f, 0x0e,
+	0xc5, 0x33, 0x8a, 0xee, 0x02, 0x74, 0x5b, 0x2c, 0xd0, 0xe2, 0xa0, 0x43, 0x61, 0xf8, 0x2d, 0x0d,
+	0x27, 0x50, 0xe2, 0xa5, 0x0d, 0x12, 0x45, 0xe2, 0x65, 0xdb, 0x37, 0xec, 0xc5, 0xa1, 0x78, 0xed,
+	0xc1, 0xba, 0x2d, 0x09, 0x85, 0x1e, 0x2c, 0xd7, 0x2e, 0x61, 0x2f, 0x8f, 0xa0, 0xd0, 0x12, 0x9a,
+	0xb6, 0x80, 0x42, 0x09, 0x33, 0x0d, 0x0c, 0xf6, 0xe2, 0x50, 0xbc, 0x72, 0x11, 0xd9, 0x12, 0x7e,
+	0x91, 0x8b, 0xe8, 0x69, 0x39, 0xb0, 0xf1, 0x28, 0x12, 0x46, 0x91, 0xa7, 0x5a, 0x07, 0xb3, 0x25,
+	0x78, 0xf4, 0x6c, 0xb1, 0x6b, 0xe9, 0x69, 0x14, 0xb0, 0xaf, 0x8f, 0x43, 0xa6, 0xbc, 0x50, 0xbe,
+	0x66, 0x5e, 0xe4, 0x85, 0xfa, 0x6a, 0xf7, 0xf6, 0xb5, 0xd1, 0x44, 0xda, 0xc3, 0x6b, 0x28, 0x2b,
+	0xf4, 0xf0, 0x99, 0xf2, 0xbb, 0xbd, 0x38, 0x14, 0xaf, 0xcc, 0x20, 0x53, 0x3b, 0x2e, 0x32, 0x83,
+	0x7c, 0xf5, 0xda, 0x5e, 0x1e, 0x41, 0xa1, 0x2c, 0xbf, 0x5b, 0x00, 0x2e, 0xb2, 0xfc, 0x5c, 0x01,
+	0xda, 0x5e, 0x1a, 0x4e, 0x60, 0x8e, 0x92, 0xa9, 0xdc, 0x16, 0x1f, 0xa5, 0x4c, 0x71, 0xd9, 0x5e,
+	0x1a, 0x4e, 0xa0, 0x18, 0x76, 0xcb, 0xb0, 0x45, 0x0c, 0x73, 0xa5, 0x5e, 0x7b, 0x69, 0x38, 0x81,
+	0x3e, 0x4d, 0xdd, 0x8a, 0x69, 0xe1, 0x69, 0xca, 0xd5, 0x6a, 0xed, 0xe5, 0x11, 0x14, 0xb9, 0xad,
+	0x19, 0xc4, 0x33, 0x5f, 0x6a, 0xb5, 0x97, 0x47, 0x50, 0x28, 0x2f, 0xdd, 0x5f, 0xe6, 0x2c, 0xf2,
+	0xd2, 0x85, 0x75, 0x55, 0x7b, 0x65, 0x3c, 0x42, 0x46, 0xd1, 0x17, 0x70, 0x71, 0x60, 0xed, 0x11,
+	0xad, 0xf6, 0xb3, 0x19, 0x56, 0xfd, 0xb4, 0x5f, 0x3e, 0x11, 0x3d, 0xa3, 0xe8, 0x4b, 0xb0, 0x07,
+	0x97, 0xfe, 0x50, 0x01, 0xbb, 0xa1, 0xb5, 0x4e, 0xfb, 0x95, 0x93, 0x7d, 0xa0, 0xf4, 0xdc, 0x5f,
+	0xde, 0x2b, 0xd2, 0x73, 0x61, 0xe5, 0xd0, 0x5e, 0x19, 0x8f, 0x90, 0x51, 0xf4, 0x39, 0x9c, 0x29,
+	0xa8, 0x9d, 0xa1, 0x95, 0x51, 0x12, 0xa7, 0x53, 0xdd, 0x18, 0x93, 0x92, 0x51, 0xf4, 0x99, 0x6a,
+	0xde, 0xce, 0x14, 0xa6, 0xd0, 0x00, 0x97, 0x95, 0xaf, 0xbc, 0xd9, 0xcf, 0x8e, 0x41, 0xc5, 0xe8,
+	0x3b, 0x33, 0x9f, 0xa8, 0xde, 0x9a, 0xdd, 0x69, 0xf9, 0x17, 0x90, 0x9b, 0xff, 0x1d, 0x00, 0x16,
+	0x4f, 0x9a, 0xdb, 0x1d, 0x32, 0x00, 0x00,
 }
@@ -33,24 +33,187 @@ import url "net/url"
 // ===============
 
 type Users interface {
-	// Register a username
+	// Register a username. The username is normalized, see User.username.
+	//  Policy violations and breached passwords are InvalidArgument errors with
+	//  a "rules" meta.
 	// Errors: AlreadyExists, InvalidArgument
 	Register(context.Context, *RegisterReq) (*RegisterResp, error)
 
 	// Login a user. Use this request to acquire a Session message.
 	//  The Session message is used to make authenticated requests. You can store it and use it with multiple
 	//  requests as long as the session is valid.
+	//  Users with two-factor authentication get a second_factor_challenge
+	//  instead of a session, pass it to VerifySecondFactor with a code.
+	//  Repeated failures for a username, or too many attempts from one client
+	//  ip, are ResourceExhausted errors with a "retry_after" meta in seconds.
+	//  Servers that hide usernames fail the same way for unknown usernames and
+	//  wrong passwords.
 	//
-	// Errors: PermissionDenied
+	// Errors: PermissionDenied, NotFound, FailedPrecondition if the account is
+	//  disabled, ResourceExhausted
 	Login(context.Context, *LoginReq) (*LoginResp, error)
 
-	// User get the details about a user.
-	// Errors: PermissionDenied
+	// User get the details about a user. Servers that hide usernames only
+	//  answer callers with a session.
+	// Errors: PermissionDenied, NotFound
 	User(context.Context, *UserReq) (*UserResp, error)
 
-	// CurrentUser gets the user for a session
-	// Errors: PermissionDenied
+	// CurrentUser gets the user for a session. Calling it renews the session's
+	//  idle timeout, up to the session's absolute lifetime.
+	// Errors: PermissionDenied, FailedPrecondition if the account is disabled
 	CurrentUser(context.Context, *CurrentUserReq) (*CurrentUserResp, error)
+
+	// Logout ends a session
+	// Errors: PermissionDenied
+	Logout(context.Context, *LogoutReq) (*LogoutResp, error)
+
+	// LogoutAll ends every session of the session's user, including the session itself
+	// Errors: PermissionDenied
+	LogoutAll(context.Context, *LogoutAllReq) (*LogoutAllResp, error)
+
+	// RevokeSession ends a session. Users may revoke their own sessions by the
+	//  session_id from ListSessions(). Admins may revoke any session by token.
+	// Errors: PermissionDenied, NotFound
+	RevokeSession(context.Context, *RevokeSessionReq) (*RevokeSessionResp, error)
+
+	// ListSessions lists the active sessions of the session's user
+	// Errors: PermissionDenied
+	ListSessions(context.Context, *ListSessionsReq) (*ListSessionsResp, error)
+
+	// ChangePassword replaces the session user's password. The current password
+	//  must be given again. Set revoke_other_sessions to end every other session
+	//  of the user, the calling session stays valid.
+	// Errors: PermissionDenied, InvalidArgument
+	ChangePassword(context.Context, *ChangePasswordReq) (*ChangePasswordResp, error)
+
+	// RequestPasswordReset sends a single-use reset token to the user through
+	//  the server's Notifier. It succeeds whether or not the user exists.
+	RequestPasswordReset(context.Context, *RequestPasswordResetReq) (*RequestPasswordResetResp, error)
+
+	// ResetPassword sets a new password using a token from RequestPasswordReset()
+	//  and ends every session of the user
+	// Errors: PermissionDenied, InvalidArgument
+	ResetPassword(context.Context, *ResetPasswordReq) (*ResetPasswordResp, error)
+
+	// VerifyEmail marks a user's email as verified using a token sent by
+	//  Register() or ResendVerification()
+	// Errors: PermissionDenied
+	VerifyEmail(context.Context, *VerifyEmailReq) (*VerifyEmailResp, error)
+
+	// ResendVerification sends a new email verification token for the session's user
+	// Errors: PermissionDenied, FailedPrecondition
+	ResendVerification(context.Context, *ResendVerificationReq) (*ResendVerificationResp, error)
+
+	// UpdateProfile updates the session user's profile. Only the fields named
+	//  in update_mask are changed.
+	// Errors: PermissionDenied, InvalidArgument
+	UpdateProfile(context.Context, *UpdateProfileReq) (*UpdateProfileResp, error)
+
+	// GrantRole grants a role to a user, optionally scoped to a resource. Admin only.
+	// Errors: PermissionDenied, NotFound, InvalidArgument
+	GrantRole(context.Context, *GrantRoleReq) (*GrantRoleResp, error)
+
+	// RevokeRole takes back a role granted by GrantRole(). Admin only.
+	// Errors: PermissionDenied, NotFound
+	RevokeRole(context.Context, *RevokeRoleReq) (*RevokeRoleResp, error)
+
+	// Authorize decides whether the session's user holds a permission on a
+	//  resource. A denial is a normal response, not an error.
+	// Errors: PermissionDenied for an invalid session
+	Authorize(context.Context, *AuthorizeReq) (*AuthorizeResp, error)
+
+	// CreateGroup creates an empty group. Admin only.
+	// Errors: PermissionDenied, AlreadyExists, InvalidArgument
+	CreateGroup(context.Context, *CreateGroupReq) (*CreateGroupResp, error)
+
+	// AddMember adds a user or a group to a group. Admin only.
+	// Errors: PermissionDenied, NotFound, FailedPrecondition if it would create a cycle
+	AddMember(context.Context, *AddMemberReq) (*AddMemberResp, error)
+
+	// RemoveMember removes a user or a group from a group. Admin only.
+	// Errors: PermissionDenied, NotFound
+	RemoveMember(context.Context, *RemoveMemberReq) (*RemoveMemberResp, error)
+
+	// ListGroupMembers lists the members of a group
+	// Errors: PermissionDenied, NotFound
+	ListGroupMembers(context.Context, *ListGroupMembersReq) (*ListGroupMembersResp, error)
+
+	// ListUserGroups lists the groups a user belongs to, directly or through
+	//  nested groups. Users may list their own groups, admins anyone's.
+	// Errors: PermissionDenied, NotFound
+	ListUserGroups(context.Context, *ListUserGroupsReq) (*ListUserGroupsResp, error)
+
+	// ListUsers lists users ordered by username, a page at a time. Admin only.
+	// Errors: PermissionDenied, InvalidArgument
+	ListUsers(context.Context, *ListUsersReq) (*ListUsersResp, error)
+
+	// DisableUser blocks a user from logging in and ends their sessions. The
+	//  user's record is kept. Admin only.
+	// Errors: PermissionDenied, NotFound, FailedPrecondition
+	DisableUser(context.Context, *DisableUserReq) (*DisableUserResp, error)
+
+	// DeleteUser removes a user with their sessions, roles and group
+	//  memberships. Admin only.
+	// Errors: PermissionDenied, NotFound, FailedPrecondition
+	DeleteUser(context.Context, *DeleteUserReq) (*DeleteUserResp, error)
+
+	// RenameUser changes a username. Users may rename themselves, admins may
+	//  rename anyone. Sessions stay valid under the new name. The old name can
+	//  not be taken by anyone else during a cooling-off period.
+	// Errors: PermissionDenied, NotFound, AlreadyExists, InvalidArgument
+	RenameUser(context.Context, *RenameUserReq) (*RenameUserResp, error)
+
+	// EnrollTOTP starts two-factor enrollment for the session's user. The
+	//  returned secret goes into an authenticator app, it is not used until
+	//  ConfirmTOTP. Enrolling again replaces an unconfirmed secret.
+	// Errors: PermissionDenied, FailedPrecondition
+	EnrollTOTP(context.Context, *EnrollTOTPReq) (*EnrollTOTPResp, error)
+
+	// ConfirmTOTP turns on two-factor authentication with a code from the
+	//  enrolled secret and returns single-use recovery codes. The recovery
+	//  codes are only returned this once.
+	// Errors: PermissionDenied, FailedPrecondition, InvalidArgument
+	ConfirmTOTP(context.Context, *ConfirmTOTPReq) (*ConfirmTOTPResp, error)
+
+	// DisableTOTP turns off two-factor authentication given a current code or
+	//  a recovery code
+	// Errors: PermissionDenied, FailedPrecondition, InvalidArgument
+	DisableTOTP(context.Context, *DisableTOTPReq) (*DisableTOTPResp, error)
+
+	// VerifySecondFactor completes a Login that returned a
+	//  second_factor_challenge with a code from the user's authenticator or
+	//  a recovery code. A challenge expires after a few minutes or failed codes.
+	// Errors: PermissionDenied, InvalidArgument, FailedPrecondition
+	VerifySecondFactor(context.Context, *VerifySecondFactorReq) (*VerifySecondFactorResp, error)
+
+	// BeginWebAuthnRegistration starts adding a passkey or security key to the
+	//  session's user. Pass the returned options to navigator.credentials.create().
+	// Errors: PermissionDenied, FailedPrecondition
+	BeginWebAuthnRegistration(context.Context, *BeginWebAuthnRegistrationReq) (*BeginWebAuthnRegistrationResp, error)
+
+	// FinishWebAuthnRegistration stores the credential created from
+	//  BeginWebAuthnRegistration's options
+	// Errors: PermissionDenied, InvalidArgument, AlreadyExists
+	FinishWebAuthnRegistration(context.Context, *FinishWebAuthnRegistrationReq) (*FinishWebAuthnRegistrationResp, error)
+
+	// BeginWebAuthnLogin starts a passwordless login. Pass the returned options
+	//  to navigator.credentials.get(). Without a username any passkey
+	//  registered with this service can be used.
+	// Errors: FailedPrecondition
+	BeginWebAuthnLogin(context.Context, *BeginWebAuthnLoginReq) (*BeginWebAuthnLoginResp, error)
+
+	// FinishWebAuthnLogin checks the assertion made from BeginWebAuthnLogin's
+	//  options and returns a session. The authenticator verifies the user so
+	//  no second factor is asked for.
+	// Errors: PermissionDenied, InvalidArgument, FailedPrecondition if the account is disabled
+	FinishWebAuthnLogin(context.Context, *FinishWebAuthnLoginReq) (*FinishWebAuthnLoginResp, error)
+
+	// ListAuditEvents lists security relevant events oldest first, a page at
+	//  a time. A page can be short or even empty when few events match the
+	//  filter, only an empty next_page_token means there are no more. Admin
+	//  only.
+	// Errors: PermissionDenied, InvalidArgument
+	ListAuditEvents(context.Context, *ListAuditEventsReq) (*ListAuditEventsResp, error)
 }
 
 // =====================
@@ -59,18 +222,49 @@ type Users interface {
 
 type usersProtobufClient struct {
 	client HTTPClient
-	urls   [4]string
+	urls   [35]string
 }
 
 // NewUsersProtobufClient creates a Protobuf client that implements the Users interface.
 // It communicates using Protobuf and can be configured with a custom HTTPClient.
 func NewUsersProtobufClient(addr string, client HTTPClient) Users {
 	prefix := urlBase(addr) + UsersPathPrefix
-	urls := [4]string{
+	urls := [35]string{
 		prefix + "Register",
 		prefix + "Login",
 		prefix + "User",
 		prefix + "CurrentUser",
+		prefix + "Logout",
+		prefix + "LogoutAll",
+		prefix + "RevokeSession",
+		prefix + "ListSessions",
+		prefix + "ChangePassword",
+		prefix + "RequestPasswordReset",
+		prefix + "ResetPassword",
+		prefix + "VerifyEmail",
+		prefix + "ResendVerification",
+		prefix + "UpdateProfile",
+		prefix + "GrantRole",
+		prefix + "RevokeRole",
+		prefix + "Authorize",
+		prefix + "CreateGroup",
+		prefix + "AddMember",
+		prefix + "RemoveMember",
+		prefix + "ListGroupMembers",
+		prefix + "ListUserGroups",
+		prefix + "ListUsers",
+		prefix + "DisableUser",
+		prefix + "DeleteUser",
+		prefix + "RenameUser",
+		prefix + "EnrollTOTP",
+		prefix + "ConfirmTOTP",
+		prefix + "DisableTOTP",
+		prefix + "VerifySecondFactor",
+		prefix + "BeginWebAuthnRegistration",
+		prefix + "FinishWebAuthnRegistration",
+		prefix + "BeginWebAuthnLogin",
+		prefix + "FinishWebAuthnLogin",
+		prefix + "ListAuditEvents",
 	}
 	if httpClient, ok := client.(*http.Client); ok {
 		return &usersProtobufClient{
@@ -108,24 +302,241 @@ func (c *usersProtobufClient) CurrentUser(ctx context.Context, in *CurrentUserRe
 	return out, err
 }
 
+func (c *usersProtobufClient) Logout(ctx context.Context, in *LogoutReq) (*LogoutResp, error) {
+	out := new(LogoutResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[4], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) LogoutAll(ctx context.Context, in *LogoutAllReq) (*LogoutAllResp, error) {
+	out := new(LogoutAllResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[5], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) RevokeSession(ctx context.Context, in *RevokeSessionReq) (*RevokeSessionResp, error) {
+	out := new(RevokeSessionResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[6], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) ListSessions(ctx context.Context, in *ListSessionsReq) (*ListSessionsResp, error) {
+	out := new(ListSessionsResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[7], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) ChangePassword(ctx context.Context, in *ChangePasswordReq) (*ChangePasswordResp, error) {
+	out := new(ChangePasswordResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[8], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetReq) (*RequestPasswordResetResp, error) {
+	out := new(RequestPasswordResetResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[9], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) ResetPassword(ctx context.Context, in *ResetPasswordReq) (*ResetPasswordResp, error) {
+	out := new(ResetPasswordResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[10], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) VerifyEmail(ctx context.Context, in *VerifyEmailReq) (*VerifyEmailResp, error) {
+	out := new(VerifyEmailResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[11], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) ResendVerification(ctx context.Context, in *ResendVerificationReq) (*ResendVerificationResp, error) {
+	out := new(ResendVerificationResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[12], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) UpdateProfile(ctx context.Context, in *UpdateProfileReq) (*UpdateProfileResp, error) {
+	out := new(UpdateProfileResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[13], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) GrantRole(ctx context.Context, in *GrantRoleReq) (*GrantRoleResp, error) {
+	out := new(GrantRoleResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[14], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) RevokeRole(ctx context.Context, in *RevokeRoleReq) (*RevokeRoleResp, error) {
+	out := new(RevokeRoleResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[15], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) Authorize(ctx context.Context, in *AuthorizeReq) (*AuthorizeResp, error) {
+	out := new(AuthorizeResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[16], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) CreateGroup(ctx context.Context, in *CreateGroupReq) (*CreateGroupResp, error) {
+	out := new(CreateGroupResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[17], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) AddMember(ctx context.Context, in *AddMemberReq) (*AddMemberResp, error) {
+	out := new(AddMemberResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[18], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) RemoveMember(ctx context.Context, in *RemoveMemberReq) (*RemoveMemberResp, error) {
+	out := new(RemoveMemberResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[19], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) ListGroupMembers(ctx context.Context, in *ListGroupMembersReq) (*ListGroupMembersResp, error) {
+	out := new(ListGroupMembersResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[20], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) ListUserGroups(ctx context.Context, in *ListUserGroupsReq) (*ListUserGroupsResp, error) {
+	out := new(ListUserGroupsResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[21], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) ListUsers(ctx context.Context, in *ListUsersReq) (*ListUsersResp, error) {
+	out := new(ListUsersResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[22], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) DisableUser(ctx context.Context, in *DisableUserReq) (*DisableUserResp, error) {
+	out := new(DisableUserResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[23], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) DeleteUser(ctx context.Context, in *DeleteUserReq) (*DeleteUserResp, error) {
+	out := new(DeleteUserResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[24], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) RenameUser(ctx context.Context, in *RenameUserReq) (*RenameUserResp, error) {
+	out := new(RenameUserResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[25], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) EnrollTOTP(ctx context.Context, in *EnrollTOTPReq) (*EnrollTOTPResp, error) {
+	out := new(EnrollTOTPResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[26], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) ConfirmTOTP(ctx context.Context, in *ConfirmTOTPReq) (*ConfirmTOTPResp, error) {
+	out := new(ConfirmTOTPResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[27], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) DisableTOTP(ctx context.Context, in *DisableTOTPReq) (*DisableTOTPResp, error) {
+	out := new(DisableTOTPResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[28], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) VerifySecondFactor(ctx context.Context, in *VerifySecondFactorReq) (*VerifySecondFactorResp, error) {
+	out := new(VerifySecondFactorResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[29], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) BeginWebAuthnRegistration(ctx context.Context, in *BeginWebAuthnRegistrationReq) (*BeginWebAuthnRegistrationResp, error) {
+	out := new(BeginWebAuthnRegistrationResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[30], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) FinishWebAuthnRegistration(ctx context.Context, in *FinishWebAuthnRegistrationReq) (*FinishWebAuthnRegistrationResp, error) {
+	out := new(FinishWebAuthnRegistrationResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[31], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) BeginWebAuthnLogin(ctx context.Context, in *BeginWebAuthnLoginReq) (*BeginWebAuthnLoginResp, error) {
+	out := new(BeginWebAuthnLoginResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[32], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) FinishWebAuthnLogin(ctx context.Context, in *FinishWebAuthnLoginReq) (*FinishWebAuthnLoginResp, error) {
+	out := new(FinishWebAuthnLoginResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[33], in, out)
+	return out, err
+}
+
+func (c *usersProtobufClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsReq) (*ListAuditEventsResp, error) {
+	out := new(ListAuditEventsResp)
+	err := doProtobufRequest(ctx, c.client, c.urls[34], in, out)
+	return out, err
+}
+
 // =================
 // Users JSON Client
 // =================
 
 type usersJSONClient struct {
 	client HTTPClient
-	urls   [4]string
+	urls   [35]string
 }
 
 // NewUsersJSONClient creates a JSON client that implements the Users interface.
 // It communicates using JSON and can be configured with a custom HTTPClient.
 func NewUsersJSONClient(addr string, client HTTPClient) Users {
 	prefix := urlBase(addr) + UsersPathPrefix
-	urls := [4]string{
+	urls := [35]string{
 		prefix + "Register",
 		prefix + "Login",
 		prefix + "User",
 		prefix + "CurrentUser",
+		prefix + "Logout",
+		prefix + "LogoutAll",
+		prefix + "RevokeSession",
+		prefix + "ListSessions",
+		prefix + "ChangePassword",
+		prefix + "RequestPasswordReset",
+		prefix + "ResetPassword",
+		prefix + "VerifyEmail",
+		prefix + "ResendVerification",
+		prefix + "UpdateProfile",
+		prefix + "GrantRole",
+		prefix + "RevokeRole",
+		prefix + "Authorize",
+		prefix + "CreateGroup",
+		prefix + "AddMember",
+		prefix + "RemoveMember",
+		prefix + "ListGroupMembers",
+		prefix + "ListUserGroups",
+		prefix + "ListUsers",
+		prefix + "DisableUser",
+		prefix + "DeleteUser",
+		prefix + "RenameUser",
+		prefix + "EnrollTOTP",
+		prefix + "ConfirmTOTP",
+		prefix + "DisableTOTP",
+		prefix + "VerifySecondFactor",
+		prefix + "BeginWebAuthnRegistration",
+		prefix + "FinishWebAuthnRegistration",
+		prefix + "BeginWebAuthnLogin",
+		prefix + "FinishWebAuthnLogin",
+		prefix + "ListAuditEvents",
 	}
 	if httpClient, ok := client.(*http.Client); ok {
 		return &usersJSONClient{
@@ -163,6 +574,192 @@ func (c *usersJSONClient) CurrentUser(ctx context.Context, in *CurrentUserReq) (
 	return out, err
 }
 
+func (c *usersJSONClient) Logout(ctx context.Context, in *LogoutReq) (*LogoutResp, error) {
+	out := new(LogoutResp)
+	err := doJSONRequest(ctx, c.client, c.urls[4], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) LogoutAll(ctx context.Context, in *LogoutAllReq) (*LogoutAllResp, error) {
+	out := new(LogoutAllResp)
+	err := doJSONRequest(ctx, c.client, c.urls[5], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) RevokeSession(ctx context.Context, in *RevokeSessionReq) (*RevokeSessionResp, error) {
+	out := new(RevokeSessionResp)
+	err := doJSONRequest(ctx, c.client, c.urls[6], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) ListSessions(ctx context.Context, in *ListSessionsReq) (*ListSessionsResp, error) {
+	out := new(ListSessionsResp)
+	err := doJSONRequest(ctx, c.client, c.urls[7], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) ChangePassword(ctx context.Context, in *ChangePasswordReq) (*ChangePasswordResp, error) {
+	out := new(ChangePasswordResp)
+	err := doJSONRequest(ctx, c.client, c.urls[8], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) RequestPasswordReset(ctx context.Context, in *RequestPasswordResetReq) (*RequestPasswordResetResp, error) {
+	out := new(RequestPasswordResetResp)
+	err := doJSONRequest(ctx, c.client, c.urls[9], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) ResetPassword(ctx context.Context, in *ResetPasswordReq) (*ResetPasswordResp, error) {
+	out := new(ResetPasswordResp)
+	err := doJSONRequest(ctx, c.client, c.urls[10], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) VerifyEmail(ctx context.Context, in *VerifyEmailReq) (*VerifyEmailResp, error) {
+	out := new(VerifyEmailResp)
+	err := doJSONRequest(ctx, c.client, c.urls[11], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) ResendVerification(ctx context.Context, in *ResendVerificationReq) (*ResendVerificationResp, error) {
+	out := new(ResendVerificationResp)
+	err := doJSONRequest(ctx, c.client, c.urls[12], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) UpdateProfile(ctx context.Context, in *UpdateProfileReq) (*UpdateProfileResp, error) {
+	out := new(UpdateProfileResp)
+	err := doJSONRequest(ctx, c.client, c.urls[13], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) GrantRole(ctx context.Context, in *GrantRoleReq) (*GrantRoleResp, error) {
+	out := new(GrantRoleResp)
+	err := doJSONRequest(ctx, c.client, c.urls[14], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) RevokeRole(ctx context.Context, in *RevokeRoleReq) (*RevokeRoleResp, error) {
+	out := new(RevokeRoleResp)
+	err := doJSONRequest(ctx, c.client, c.urls[15], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) Authorize(ctx context.Context, in *AuthorizeReq) (*AuthorizeResp, error) {
+	out := new(AuthorizeResp)
+	err := doJSONRequest(ctx, c.client, c.urls[16], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) CreateGroup(ctx context.Context, in *CreateGroupReq) (*CreateGroupResp, error) {
+	out := new(CreateGroupResp)
+	err := doJSONRequest(ctx, c.client, c.urls[17], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) AddMember(ctx context.Context, in *AddMemberReq) (*AddMemberResp, error) {
+	out := new(AddMemberResp)
+	err := doJSONRequest(ctx, c.client, c.urls[18], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) RemoveMember(ctx context.Context, in *RemoveMemberReq) (*RemoveMemberResp, error) {
+	out := new(RemoveMemberResp)
+	err := doJSONRequest(ctx, c.client, c.urls[19], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) ListGroupMembers(ctx context.Context, in *ListGroupMembersReq) (*ListGroupMembersResp, error) {
+	out := new(ListGroupMembersResp)
+	err := doJSONRequest(ctx, c.client, c.urls[20], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) ListUserGroups(ctx context.Context, in *ListUserGroupsReq) (*ListUserGroupsResp, error) {
+	out := new(ListUserGroupsResp)
+	err := doJSONRequest(ctx, c.client, c.urls[21], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) ListUsers(ctx context.Context, in *ListUsersReq) (*ListUsersResp, error) {
+	out := new(ListUsersResp)
+	err := doJSONRequest(ctx, c.client, c.urls[22], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) DisableUser(ctx context.Context, in *DisableUserReq) (*DisableUserResp, error) {
+	out := new(DisableUserResp)
+	err := doJSONRequest(ctx, c.client, c.urls[23], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) DeleteUser(ctx context.Context, in *DeleteUserReq) (*DeleteUserResp, error) {
+	out := new(DeleteUserResp)
+	err := doJSONRequest(ctx, c.client, c.urls[24], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) RenameUser(ctx context.Context, in *RenameUserReq) (*RenameUserResp, error) {
+	out := new(RenameUserResp)
+	err := doJSONRequest(ctx, c.client, c.urls[25], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) EnrollTOTP(ctx context.Context, in *EnrollTOTPReq) (*EnrollTOTPResp, error) {
+	out := new(EnrollTOTPResp)
+	err := doJSONRequest(ctx, c.client, c.urls[26], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) ConfirmTOTP(ctx context.Context, in *ConfirmTOTPReq) (*ConfirmTOTPResp, error) {
+	out := new(ConfirmTOTPResp)
+	err := doJSONRequest(ctx, c.client, c.urls[27], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) DisableTOTP(ctx context.Context, in *DisableTOTPReq) (*DisableTOTPResp, error) {
+	out := new(DisableTOTPResp)
+	err := doJSONRequest(ctx, c.client, c.urls[28], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) VerifySecondFactor(ctx context.Context, in *VerifySecondFactorReq) (*VerifySecondFactorResp, error) {
+	out := new(VerifySecondFactorResp)
+	err := doJSONRequest(ctx, c.client, c.urls[29], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) BeginWebAuthnRegistration(ctx context.Context, in *BeginWebAuthnRegistrationReq) (*BeginWebAuthnRegistrationResp, error) {
+	out := new(BeginWebAuthnRegistrationResp)
+	err := doJSONRequest(ctx, c.client, c.urls[30], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) FinishWebAuthnRegistration(ctx context.Context, in *FinishWebAuthnRegistrationReq) (*FinishWebAuthnRegistrationResp, error) {
+	out := new(FinishWebAuthnRegistrationResp)
+	err := doJSONRequest(ctx, c.client, c.urls[31], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) BeginWebAuthnLogin(ctx context.Context, in *BeginWebAuthnLoginReq) (*BeginWebAuthnLoginResp, error) {
+	out := new(BeginWebAuthnLoginResp)
+	err := doJSONRequest(ctx, c.client, c.urls[32], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) FinishWebAuthnLogin(ctx context.Context, in *FinishWebAuthnLoginReq) (*FinishWebAuthnLoginResp, error) {
+	out := new(FinishWebAuthnLoginResp)
+	err := doJSONRequest(ctx, c.client, c.urls[33], in, out)
+	return out, err
+}
+
+func (c *usersJSONClient) ListAuditEvents(ctx context.Context, in *ListAuditEventsReq) (*ListAuditEventsResp, error) {
+	out := new(ListAuditEventsResp)
+	err := doJSONRequest(ctx, c.client, c.urls[34], in, out)
+	return out, err
+}
+
 // ====================
 // Users Server Handler
 // ====================
@@ -223,6 +820,99 @@ func (s *usersServer) ServeHTTP(resp http.ResponseWriter, req *http.Request) {
 	case "/twirp/ericmoritz.users.Users/CurrentUser":
 		s.serveCurrentUser(ctx, resp, req)
 		return
+	case "/twirp/ericmoritz.users.Users/Logout":
+		s.serveLogout(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/LogoutAll":
+		s.serveLogoutAll(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/RevokeSession":
+		s.serveRevokeSession(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/ListSessions":
+		s.serveListSessions(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/ChangePassword":
+		s.serveChangePassword(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/RequestPasswordReset":
+		s.serveRequestPasswordReset(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/ResetPassword":
+		s.serveResetPassword(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/VerifyEmail":
+		s.serveVerifyEmail(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/ResendVerification":
+		s.serveResendVerification(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/UpdateProfile":
+		s.serveUpdateProfile(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/GrantRole":
+		s.serveGrantRole(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/RevokeRole":
+		s.serveRevokeRole(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/Authorize":
+		s.serveAuthorize(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/CreateGroup":
+		s.serveCreateGroup(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/AddMember":
+		s.serveAddMember(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/RemoveMember":
+		s.serveRemoveMember(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/ListGroupMembers":
+		s.serveListGroupMembers(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/ListUserGroups":
+		s.serveListUserGroups(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/ListUsers":
+		s.serveListUsers(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/DisableUser":
+		s.serveDisableUser(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/DeleteUser":
+		s.serveDeleteUser(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/RenameUser":
+		s.serveRenameUser(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/EnrollTOTP":
+		s.serveEnrollTOTP(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/ConfirmTOTP":
+		s.serveConfirmTOTP(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/DisableTOTP":
+		s.serveDisableTOTP(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/VerifySecondFactor":
+		s.serveVerifySecondFactor(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/BeginWebAuthnRegistration":
+		s.serveBeginWebAuthnRegistration(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/FinishWebAuthnRegistration":
+		s.serveFinishWebAuthnRegistration(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/BeginWebAuthnLogin":
+		s.serveBeginWebAuthnLogin(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/FinishWebAuthnLogin":
+		s.serveFinishWebAuthnLogin(ctx, resp, req)
+		return
+	case "/twirp/ericmoritz.users.Users/ListAuditEvents":
+		s.serveListAuditEvents(ctx, resp, req)
+		return
 	default:
 		msg := fmt.Sprintf("no handler for path %q", req.URL.Path)
 		err = badRouteError(msg, req.Method, req.URL.Path)
@@ -771,6 +1461,4191 @@ func (s *usersServer) serveCurrentUserProtobuf(ctx context.Context, resp http.Re
 	callResponseSent(ctx, s.hooks)
 }
 
+func (s *usersServer) serveLogout(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveLogoutJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveLogoutProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveLogoutJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "Logout")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(LogoutReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *LogoutResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Logout(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *LogoutResp and nil error while calling Logout. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveLogoutProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "Logout")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(LogoutReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *LogoutResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Logout(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *LogoutResp and nil error while calling Logout. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveLogoutAll(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveLogoutAllJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveLogoutAllProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveLogoutAllJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "LogoutAll")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(LogoutAllReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *LogoutAllResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.LogoutAll(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *LogoutAllResp and nil error while calling LogoutAll. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveLogoutAllProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "LogoutAll")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(LogoutAllReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *LogoutAllResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.LogoutAll(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *LogoutAllResp and nil error while calling LogoutAll. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveRevokeSession(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveRevokeSessionJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveRevokeSessionProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveRevokeSessionJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RevokeSession")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(RevokeSessionReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RevokeSessionResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.RevokeSession(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RevokeSessionResp and nil error while calling RevokeSession. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveRevokeSessionProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RevokeSession")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(RevokeSessionReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RevokeSessionResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.RevokeSession(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RevokeSessionResp and nil error while calling RevokeSession. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveListSessions(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveListSessionsJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveListSessionsProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveListSessionsJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListSessions")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(ListSessionsReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListSessionsResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ListSessions(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListSessionsResp and nil error while calling ListSessions. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveListSessionsProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListSessions")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ListSessionsReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListSessionsResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ListSessions(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListSessionsResp and nil error while calling ListSessions. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveChangePassword(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveChangePasswordJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveChangePasswordProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveChangePasswordJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ChangePassword")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(ChangePasswordReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ChangePasswordResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ChangePassword(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ChangePasswordResp and nil error while calling ChangePassword. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveChangePasswordProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ChangePassword")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ChangePasswordReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ChangePasswordResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ChangePassword(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ChangePasswordResp and nil error while calling ChangePassword. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveRequestPasswordReset(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveRequestPasswordResetJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveRequestPasswordResetProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveRequestPasswordResetJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RequestPasswordReset")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(RequestPasswordResetReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RequestPasswordResetResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.RequestPasswordReset(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RequestPasswordResetResp and nil error while calling RequestPasswordReset. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveRequestPasswordResetProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RequestPasswordReset")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(RequestPasswordResetReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RequestPasswordResetResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.RequestPasswordReset(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RequestPasswordResetResp and nil error while calling RequestPasswordReset. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveResetPassword(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveResetPasswordJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveResetPasswordProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveResetPasswordJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ResetPassword")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(ResetPasswordReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ResetPasswordResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ResetPassword(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ResetPasswordResp and nil error while calling ResetPassword. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveResetPasswordProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ResetPassword")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ResetPasswordReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ResetPasswordResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ResetPassword(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ResetPasswordResp and nil error while calling ResetPassword. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveVerifyEmail(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveVerifyEmailJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveVerifyEmailProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveVerifyEmailJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "VerifyEmail")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(VerifyEmailReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *VerifyEmailResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.VerifyEmail(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *VerifyEmailResp and nil error while calling VerifyEmail. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveVerifyEmailProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "VerifyEmail")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(VerifyEmailReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *VerifyEmailResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.VerifyEmail(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *VerifyEmailResp and nil error while calling VerifyEmail. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveResendVerification(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveResendVerificationJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveResendVerificationProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveResendVerificationJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ResendVerification")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(ResendVerificationReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ResendVerificationResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ResendVerification(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ResendVerificationResp and nil error while calling ResendVerification. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveResendVerificationProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ResendVerification")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ResendVerificationReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ResendVerificationResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ResendVerification(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ResendVerificationResp and nil error while calling ResendVerification. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveUpdateProfile(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveUpdateProfileJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveUpdateProfileProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveUpdateProfileJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "UpdateProfile")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(UpdateProfileReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *UpdateProfileResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.UpdateProfile(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *UpdateProfileResp and nil error while calling UpdateProfile. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveUpdateProfileProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "UpdateProfile")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(UpdateProfileReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *UpdateProfileResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.UpdateProfile(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *UpdateProfileResp and nil error while calling UpdateProfile. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveGrantRole(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveGrantRoleJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveGrantRoleProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveGrantRoleJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "GrantRole")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(GrantRoleReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *GrantRoleResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.GrantRole(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GrantRoleResp and nil error while calling GrantRole. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveGrantRoleProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "GrantRole")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(GrantRoleReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *GrantRoleResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.GrantRole(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *GrantRoleResp and nil error while calling GrantRole. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveRevokeRole(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveRevokeRoleJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveRevokeRoleProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveRevokeRoleJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RevokeRole")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(RevokeRoleReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RevokeRoleResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.RevokeRole(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RevokeRoleResp and nil error while calling RevokeRole. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveRevokeRoleProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RevokeRole")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(RevokeRoleReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RevokeRoleResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.RevokeRole(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RevokeRoleResp and nil error while calling RevokeRole. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveAuthorize(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveAuthorizeJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveAuthorizeProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveAuthorizeJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "Authorize")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(AuthorizeReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *AuthorizeResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Authorize(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *AuthorizeResp and nil error while calling Authorize. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveAuthorizeProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "Authorize")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(AuthorizeReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *AuthorizeResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.Authorize(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *AuthorizeResp and nil error while calling Authorize. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveCreateGroup(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveCreateGroupJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveCreateGroupProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveCreateGroupJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "CreateGroup")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(CreateGroupReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *CreateGroupResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.CreateGroup(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *CreateGroupResp and nil error while calling CreateGroup. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveCreateGroupProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "CreateGroup")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(CreateGroupReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *CreateGroupResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.CreateGroup(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *CreateGroupResp and nil error while calling CreateGroup. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveAddMember(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveAddMemberJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveAddMemberProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveAddMemberJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "AddMember")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(AddMemberReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *AddMemberResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.AddMember(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *AddMemberResp and nil error while calling AddMember. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveAddMemberProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "AddMember")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(AddMemberReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *AddMemberResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.AddMember(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *AddMemberResp and nil error while calling AddMember. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveRemoveMember(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveRemoveMemberJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveRemoveMemberProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveRemoveMemberJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RemoveMember")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(RemoveMemberReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RemoveMemberResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.RemoveMember(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RemoveMemberResp and nil error while calling RemoveMember. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveRemoveMemberProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RemoveMember")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(RemoveMemberReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RemoveMemberResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.RemoveMember(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RemoveMemberResp and nil error while calling RemoveMember. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveListGroupMembers(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveListGroupMembersJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveListGroupMembersProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveListGroupMembersJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListGroupMembers")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(ListGroupMembersReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListGroupMembersResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ListGroupMembers(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListGroupMembersResp and nil error while calling ListGroupMembers. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveListGroupMembersProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListGroupMembers")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ListGroupMembersReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListGroupMembersResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ListGroupMembers(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListGroupMembersResp and nil error while calling ListGroupMembers. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveListUserGroups(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveListUserGroupsJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveListUserGroupsProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveListUserGroupsJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListUserGroups")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(ListUserGroupsReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListUserGroupsResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ListUserGroups(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListUserGroupsResp and nil error while calling ListUserGroups. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveListUserGroupsProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListUserGroups")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ListUserGroupsReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListUserGroupsResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ListUserGroups(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListUserGroupsResp and nil error while calling ListUserGroups. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveListUsers(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveListUsersJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveListUsersProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveListUsersJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListUsers")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(ListUsersReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListUsersResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ListUsers(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListUsersResp and nil error while calling ListUsers. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveListUsersProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListUsers")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ListUsersReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListUsersResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ListUsers(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListUsersResp and nil error while calling ListUsers. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveDisableUser(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveDisableUserJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveDisableUserProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveDisableUserJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "DisableUser")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(DisableUserReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *DisableUserResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.DisableUser(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DisableUserResp and nil error while calling DisableUser. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveDisableUserProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "DisableUser")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(DisableUserReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *DisableUserResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.DisableUser(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DisableUserResp and nil error while calling DisableUser. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveDeleteUser(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveDeleteUserJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveDeleteUserProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveDeleteUserJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "DeleteUser")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(DeleteUserReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *DeleteUserResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.DeleteUser(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DeleteUserResp and nil error while calling DeleteUser. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveDeleteUserProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "DeleteUser")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(DeleteUserReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *DeleteUserResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.DeleteUser(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DeleteUserResp and nil error while calling DeleteUser. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveRenameUser(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveRenameUserJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveRenameUserProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveRenameUserJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RenameUser")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(RenameUserReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RenameUserResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.RenameUser(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RenameUserResp and nil error while calling RenameUser. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveRenameUserProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "RenameUser")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(RenameUserReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *RenameUserResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.RenameUser(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *RenameUserResp and nil error while calling RenameUser. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveEnrollTOTP(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveEnrollTOTPJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveEnrollTOTPProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveEnrollTOTPJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "EnrollTOTP")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(EnrollTOTPReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *EnrollTOTPResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.EnrollTOTP(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *EnrollTOTPResp and nil error while calling EnrollTOTP. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveEnrollTOTPProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "EnrollTOTP")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(EnrollTOTPReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *EnrollTOTPResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.EnrollTOTP(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *EnrollTOTPResp and nil error while calling EnrollTOTP. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveConfirmTOTP(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveConfirmTOTPJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveConfirmTOTPProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveConfirmTOTPJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ConfirmTOTP")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(ConfirmTOTPReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ConfirmTOTPResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ConfirmTOTP(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ConfirmTOTPResp and nil error while calling ConfirmTOTP. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveConfirmTOTPProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ConfirmTOTP")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ConfirmTOTPReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ConfirmTOTPResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ConfirmTOTP(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ConfirmTOTPResp and nil error while calling ConfirmTOTP. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveDisableTOTP(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveDisableTOTPJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveDisableTOTPProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveDisableTOTPJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "DisableTOTP")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(DisableTOTPReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *DisableTOTPResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.DisableTOTP(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DisableTOTPResp and nil error while calling DisableTOTP. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveDisableTOTPProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "DisableTOTP")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(DisableTOTPReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *DisableTOTPResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.DisableTOTP(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *DisableTOTPResp and nil error while calling DisableTOTP. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveVerifySecondFactor(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveVerifySecondFactorJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveVerifySecondFactorProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveVerifySecondFactorJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "VerifySecondFactor")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(VerifySecondFactorReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *VerifySecondFactorResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.VerifySecondFactor(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *VerifySecondFactorResp and nil error while calling VerifySecondFactor. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveVerifySecondFactorProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "VerifySecondFactor")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(VerifySecondFactorReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *VerifySecondFactorResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.VerifySecondFactor(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *VerifySecondFactorResp and nil error while calling VerifySecondFactor. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveBeginWebAuthnRegistration(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveBeginWebAuthnRegistrationJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveBeginWebAuthnRegistrationProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveBeginWebAuthnRegistrationJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "BeginWebAuthnRegistration")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(BeginWebAuthnRegistrationReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *BeginWebAuthnRegistrationResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.BeginWebAuthnRegistration(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *BeginWebAuthnRegistrationResp and nil error while calling BeginWebAuthnRegistration. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveBeginWebAuthnRegistrationProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "BeginWebAuthnRegistration")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(BeginWebAuthnRegistrationReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *BeginWebAuthnRegistrationResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.BeginWebAuthnRegistration(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *BeginWebAuthnRegistrationResp and nil error while calling BeginWebAuthnRegistration. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveFinishWebAuthnRegistration(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveFinishWebAuthnRegistrationJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveFinishWebAuthnRegistrationProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveFinishWebAuthnRegistrationJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "FinishWebAuthnRegistration")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(FinishWebAuthnRegistrationReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *FinishWebAuthnRegistrationResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.FinishWebAuthnRegistration(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *FinishWebAuthnRegistrationResp and nil error while calling FinishWebAuthnRegistration. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveFinishWebAuthnRegistrationProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "FinishWebAuthnRegistration")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(FinishWebAuthnRegistrationReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *FinishWebAuthnRegistrationResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.FinishWebAuthnRegistration(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *FinishWebAuthnRegistrationResp and nil error while calling FinishWebAuthnRegistration. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveBeginWebAuthnLogin(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveBeginWebAuthnLoginJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveBeginWebAuthnLoginProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveBeginWebAuthnLoginJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "BeginWebAuthnLogin")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(BeginWebAuthnLoginReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *BeginWebAuthnLoginResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.BeginWebAuthnLogin(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *BeginWebAuthnLoginResp and nil error while calling BeginWebAuthnLogin. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveBeginWebAuthnLoginProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "BeginWebAuthnLogin")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(BeginWebAuthnLoginReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *BeginWebAuthnLoginResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.BeginWebAuthnLogin(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *BeginWebAuthnLoginResp and nil error while calling BeginWebAuthnLogin. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveFinishWebAuthnLogin(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveFinishWebAuthnLoginJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveFinishWebAuthnLoginProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveFinishWebAuthnLoginJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "FinishWebAuthnLogin")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(FinishWebAuthnLoginReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *FinishWebAuthnLoginResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.FinishWebAuthnLogin(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *FinishWebAuthnLoginResp and nil error while calling FinishWebAuthnLogin. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveFinishWebAuthnLoginProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "FinishWebAuthnLogin")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(FinishWebAuthnLoginReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *FinishWebAuthnLoginResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.FinishWebAuthnLogin(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *FinishWebAuthnLoginResp and nil error while calling FinishWebAuthnLogin. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveListAuditEvents(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	switch req.Header.Get("Content-Type") {
+	case "application/json":
+		s.serveListAuditEventsJSON(ctx, resp, req)
+	case "application/protobuf":
+		s.serveListAuditEventsProtobuf(ctx, resp, req)
+	default:
+		msg := fmt.Sprintf("unexpected Content-Type: %q", req.Header.Get("Content-Type"))
+		twerr := badRouteError(msg, req.Method, req.URL.Path)
+		s.writeError(ctx, resp, twerr)
+	}
+}
+
+func (s *usersServer) serveListAuditEventsJSON(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListAuditEvents")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	reqContent := new(ListAuditEventsReq)
+	unmarshaler := jsonpb.Unmarshaler{AllowUnknownFields: true}
+	if err = unmarshaler.Unmarshal(req.Body, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request json")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListAuditEventsResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ListAuditEvents(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListAuditEventsResp and nil error while calling ListAuditEvents. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	var buf bytes.Buffer
+	marshaler := &jsonpb.Marshaler{OrigName: true}
+	if err = marshaler.Marshal(&buf, respContent); err != nil {
+		err = wrapErr(err, "failed to marshal json response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/json")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(buf.Bytes()); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
+func (s *usersServer) serveListAuditEventsProtobuf(ctx context.Context, resp http.ResponseWriter, req *http.Request) {
+	var err error
+	ctx = ctxsetters.WithMethodName(ctx, "ListAuditEvents")
+	ctx, err = callRequestRouted(ctx, s.hooks)
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+
+	defer closebody(req.Body)
+	buf, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		err = wrapErr(err, "failed to read request body")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+	reqContent := new(ListAuditEventsReq)
+	if err = proto.Unmarshal(buf, reqContent); err != nil {
+		err = wrapErr(err, "failed to parse request proto")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	// Call service method
+	var respContent *ListAuditEventsResp
+	func() {
+		defer func() {
+			// In case of a panic, serve a 500 error and then panic.
+			if r := recover(); r != nil {
+				s.writeError(ctx, resp, twirp.InternalError("Internal service panic"))
+				panic(r)
+			}
+		}()
+		respContent, err = s.ListAuditEvents(ctx, reqContent)
+	}()
+
+	if err != nil {
+		s.writeError(ctx, resp, err)
+		return
+	}
+	if respContent == nil {
+		s.writeError(ctx, resp, twirp.InternalError("received a nil *ListAuditEventsResp and nil error while calling ListAuditEvents. nil responses are not supported"))
+		return
+	}
+
+	ctx = callResponsePrepared(ctx, s.hooks)
+
+	respBytes, err := proto.Marshal(respContent)
+	if err != nil {
+		err = wrapErr(err, "failed to marshal proto response")
+		s.writeError(ctx, resp, twirp.InternalErrorWith(err))
+		return
+	}
+
+	ctx = ctxsetters.WithStatusCode(ctx, http.StatusOK)
+	resp.Header().Set("Content-Type", "application/protobuf")
+	resp.WriteHeader(http.StatusOK)
+	if _, err = resp.Write(respBytes); err != nil {
+		log.Printf("errored while writing response to client, but already sent response status code to 200: %s", err)
+	}
+	callResponseSent(ctx, s.hooks)
+}
+
 func (s *usersServer) ServiceDescriptor() ([]byte, int) {
 	return twirpFileDescriptor0, 0
 }
@@ -1182,28 +6057,203 @@ func callError(ctx context.Context, h *twirp.ServerHooks, err twirp.Error) conte
 }
 
 var twirpFileDescriptor0 = []byte{
-	// 367 bytes of a gzipped FileDescriptorProto
-	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xa4, 0x53, 0x51, 0x4b, 0x32, 0x41,
-	0x14, 0x45, 0x71, 0xbf, 0x5d, 0xef, 0x8a, 0xdf, 0xc7, 0xf0, 0x51, 0xba, 0x51, 0xd8, 0x42, 0x11,
-	0x3d, 0xac, 0xa0, 0xe4, 0x43, 0x21, 0x88, 0xe1, 0x43, 0xd0, 0x43, 0xad, 0xf4, 0xd2, 0xdb, 0x66,
-	0x17, 0x1b, 0xc2, 0x9d, 0x71, 0xee, 0x6a, 0xd0, 0x1f, 0xea, 0x6f, 0xc6, 0xce, 0xee, 0x96, 0xba,
-	0xa8, 0x58, 0x8f, 0x77, 0xce, 0xd9, 0x73, 0xce, 0xdc, 0x39, 0x0b, 0xfb, 0x4a, 0x8e, 0x9a, 0x33,
-	0x42, 0x45, 0x4d, 0x42, 0x35, 0xe7, 0x23, 0xf4, 0xa4, 0x12, 0x91, 0x60, 0xff, 0x50, 0xf1, 0xd1,
-	0x44, 0x28, 0x1e, 0xbd, 0x7b, 0x1a, 0x77, 0x07, 0x60, 0xfb, 0x38, 0xe6, 0x14, 0xa1, 0xf2, 0x71,
-	0xca, 0x1c, 0xb0, 0xe2, 0xf3, 0x30, 0x98, 0x60, 0xad, 0xd0, 0x28, 0x9c, 0x95, 0xfd, 0xaf, 0x39,
-	0xc6, 0x64, 0x40, 0xf4, 0x26, 0xd4, 0x73, 0xad, 0x98, 0x60, 0xd9, 0xec, 0x5e, 0x42, 0xe5, 0x5b,
-	0x86, 0x24, 0x3b, 0x87, 0x52, 0xfc, 0x9d, 0xd6, 0xb0, 0x5b, 0x7b, 0xde, 0xaa, 0xaf, 0xf7, 0x40,
-	0xa8, 0x7c, 0xcd, 0x71, 0xfb, 0x60, 0xdd, 0x8a, 0x31, 0x0f, 0x7f, 0xe3, 0xdf, 0x83, 0x72, 0xaa,
-	0x41, 0x92, 0xb5, 0xc1, 0x24, 0x24, 0xe2, 0x22, 0x4c, 0xfd, 0xeb, 0x79, 0xff, 0x61, 0x42, 0xf0,
-	0x33, 0xa6, 0x7b, 0x02, 0xa6, 0xce, 0xb4, 0x12, 0xa2, 0xb8, 0x1c, 0xc2, 0xed, 0x80, 0x95, 0xd0,
-	0x76, 0xbc, 0xe4, 0x00, 0xaa, 0xd7, 0x33, 0xa5, 0x30, 0x8c, 0x32, 0x97, 0x1f, 0xa5, 0xec, 0xc2,
-	0xdf, 0x25, 0x99, 0x1d, 0x53, 0xb8, 0x50, 0x8a, 0xa7, 0x4d, 0x6b, 0x76, 0xaf, 0xc0, 0x4c, 0x6d,
-	0xd9, 0x7f, 0x30, 0x22, 0xf1, 0x8a, 0x61, 0xca, 0x49, 0x86, 0x8d, 0xeb, 0xb9, 0x07, 0xfb, 0x4e,
-	0xf1, 0x79, 0x10, 0xe1, 0x36, 0x1f, 0x76, 0x0a, 0xd5, 0xec, 0xf9, 0x86, 0x2f, 0x41, 0xeb, 0xa2,
-	0xa3, 0xc5, 0x2a, 0xfe, 0xca, 0x69, 0xeb, 0xa3, 0x08, 0x46, 0x2c, 0x46, 0xec, 0x06, 0xac, 0xac,
-	0x64, 0xec, 0x30, 0x7f, 0xcf, 0x85, 0x1e, 0x3b, 0x47, 0x9b, 0x60, 0x92, 0xac, 0x07, 0x86, 0xee,
-	0x0b, 0x73, 0xf2, 0xc4, 0xac, 0x8c, 0xce, 0xc1, 0x5a, 0x8c, 0x24, 0xeb, 0xa6, 0xab, 0xac, 0xaf,
-	0x59, 0x38, 0x4e, 0x1d, 0x67, 0x1d, 0x44, 0x92, 0xf9, 0x60, 0x2f, 0x3c, 0x24, 0x6b, 0xe4, 0xa9,
-	0xcb, 0x75, 0x71, 0x8e, 0xb7, 0x30, 0x48, 0xf6, 0xcd, 0x47, 0x43, 0x03, 0x4f, 0x7f, 0xf4, 0xdf,
-	0xde, 0xfe, 0x0c, 0x00, 0x00, 0xff, 0xff, 0x4e, 0x5d, 0xbb, 0xcd, 0x08, 0x04, 0x00, 0x00,
+	// 3159 bytes of a gzipped FileDescriptorProto
+	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xcc, 0x3b, 0x4b, 0x6f, 0x1c, 0xc7,
+	0xd1, 0x98, 0x5d, 0x2e, 0xc9, 0xad, 0x7d, 0x90, 0x6c, 0xbd, 0x56, 0x63, 0x49, 0x24, 0x5b, 0xb2,
+	0x4c, 0xf9, 0x41, 0xfb, 0xa3, 0x3e, 0x1b, 0xb6, 0x13, 0x23, 0xa6, 0x29, 0xca, 0xa6, 0x23, 0x5b,
+	0xc2, 0x50, 0xb4, 0x13, 0x1b, 0xf1, 0x64, 0xb8, 0xd3, 0x24, 0xc7, 0x1a, 0xce, 0xb4, 0xa6, 0x7b,
+	0x29, 0x51, 0x71, 0xe0, 0x04, 0x39, 0x05, 0x09, 0x72, 0xcc, 0x25, 0xc8, 0x31, 0x81, 0x73, 0xc9,
+	0x39, 0x87, 0x20, 0x40, 0x02, 0x04, 0xf9, 0x01, 0x41, 0xce, 0xf9, 0x19, 0xb9, 0x06, 0xfd, 0x9a,
+	0x9d, 0xd9, 0x9d, 0x7d, 0x70, 0x69, 0x05, 0xbe, 0x6d, 0x57, 0xd5, 0x54, 0x57, 0x57, 0x57, 0x57,
+	0x55, 0x77, 0xd5, 0xc2, 0x85, 0x84, 0xb6, 0x5f, 0xee, 0x30, 0x92, 0xb0, 0x97, 0x19, 0x49, 0x8e,
+	0x82, 0x36, 0x59, 0xa5, 0x49, 0xcc, 0x63, 0x34, 0x4f, 0x92, 0xa0, 0x7d, 0x18, 0x27, 0x01, 0x7f,
+	0xb2, 0x2a, 0xf1, 0xf8, 0x53, 0xa8, 0x39, 0x64, 0x3f, 0x60, 0x9c, 0x24, 0x0e, 0x79, 0x88, 0x6c,
+	0x98, 0x15, 0xf0, 0xc8, 0x3b, 0x24, 0x2d, 0x6b, 0xc9, 0x5a, 0xa9, 0x3a, 0xe9, 0x58, 0xe0, 0xa8,
+	0xc7, 0xd8, 0xa3, 0x38, 0xf1, 0x5b, 0x25, 0x85, 0x33, 0x63, 0x74, 0x16, 0x2a, 0xe4, 0xd0, 0x0b,
+	0xc2, 0x56, 0x59, 0x22, 0xd4, 0x00, 0xbf, 0x09, 0xf5, 0x2e, 0x73, 0x46, 0xd1, 0xf3, 0x30, 0x25,
+	0xb8, 0x49, 0xce, 0xb5, 0xb5, 0xf3, 0xab, 0xbd, 0xd2, 0xac, 0xee, 0x30, 0x92, 0x38, 0x92, 0x06,
+	0xbf, 0x03, 0xb3, 0x77, 0xe2, 0xfd, 0x20, 0x3a, 0x85, 0x54, 0xf8, 0x31, 0x54, 0x35, 0x0f, 0x46,
+	0xd1, 0x4d, 0x98, 0x61, 0x84, 0xb1, 0x20, 0x8e, 0xf4, 0xfc, 0x17, 0xfb, 0xe7, 0xdf, 0x56, 0x04,
+	0x8e, 0xa1, 0x44, 0xaf, 0xc1, 0x05, 0x46, 0xda, 0x71, 0xe4, 0xbb, 0x7b, 0x5e, 0x9b, 0xc7, 0x89,
+	0xdb, 0x3e, 0xf0, 0xc2, 0x90, 0x44, 0xfb, 0x44, 0x4f, 0x76, 0x4e, 0xa1, 0x6f, 0x4b, 0xec, 0x86,
+	0x41, 0xe2, 0x4f, 0x60, 0x46, 0xae, 0x85, 0x3c, 0x9c, 0x6c, 0xde, 0xec, 0x8a, 0x4b, 0xf9, 0x15,
+	0xe3, 0xd7, 0x60, 0x76, 0x87, 0x4d, 0xa0, 0xd1, 0x4d, 0x68, 0x6e, 0x74, 0x92, 0x84, 0x44, 0xfc,
+	0x34, 0xa2, 0xe1, 0xb7, 0x60, 0x2e, 0xc7, 0xe6, 0x84, 0x52, 0xbc, 0x2d, 0xf7, 0x24, 0xee, 0xf0,
+	0x89, 0x05, 0xa8, 0x03, 0x18, 0x0e, 0x8c, 0xe2, 0x0d, 0xa8, 0xab, 0xd1, 0x7a, 0x18, 0x4e, 0xcc,
+	0xf2, 0x06, 0x34, 0x32, 0x4c, 0x18, 0x45, 0x2d, 0x98, 0x49, 0xc8, 0x51, 0xfc, 0x80, 0xf8, 0x92,
+	0x4b, 0xc5, 0x31, 0x43, 0xfc, 0x05, 0xcc, 0x3b, 0xf2, 0xa7, 0x61, 0x32, 0xe9, 0x16, 0x9f, 0x85,
+	0x0a, 0x8f, 0x1f, 0x90, 0x48, 0xef, 0xaf, 0x1a, 0xa0, 0xcb, 0x00, 0x9a, 0xc0, 0x0d, 0x7c, 0x7d,
+	0x9a, 0xaa, 0x1a, 0xb2, 0xe5, 0xe3, 0x33, 0xb0, 0xd0, 0x33, 0x3b, 0xa3, 0xf8, 0x36, 0xcc, 0xdd,
+	0x09, 0x18, 0xd7, 0x20, 0x36, 0xb1, 0x16, 0x3e, 0x80, 0xf9, 0x3c, 0x1f, 0x46, 0xd1, 0x1b, 0x30,
+	0xab, 0xd1, 0xac, 0x65, 0x2d, 0x95, 0x57, 0x6a, 0x6b, 0x97, 0x07, 0x72, 0xda, 0x8a, 0xf6, 0x62,
+	0x27, 0x25, 0xc7, 0x7f, 0xb3, 0x60, 0x61, 0xe3, 0xc0, 0x8b, 0xf6, 0xc9, 0x3d, 0x7d, 0x20, 0x27,
+	0xd6, 0xd5, 0x32, 0xd4, 0xe3, 0xd0, 0x77, 0x7b, 0x0e, 0x7a, 0x2d, 0x0e, 0x7d, 0xc3, 0x5a, 0x90,
+	0x44, 0xe4, 0x51, 0x97, 0x44, 0xa9, 0xae, 0x16, 0x91, 0x47, 0x29, 0xc9, 0x1a, 0x9c, 0x53, 0xbb,
+	0xe8, 0xc6, 0xfc, 0x80, 0x24, 0x6e, 0xba, 0xb0, 0xa9, 0x25, 0x6b, 0x65, 0xd6, 0x39, 0xa3, 0x90,
+	0x77, 0x05, 0xce, 0xe8, 0x00, 0xaf, 0x02, 0xea, 0x5d, 0xc3, 0x50, 0xf3, 0xd8, 0x84, 0x0b, 0x0e,
+	0x79, 0xd8, 0x21, 0x8c, 0x67, 0x3e, 0x20, 0xd2, 0xd8, 0x9f, 0x87, 0x05, 0x73, 0x86, 0xdd, 0x38,
+	0x71, 0x95, 0xbf, 0x54, 0xee, 0x6c, 0xce, 0x20, 0xee, 0x26, 0x9b, 0xd2, 0x73, 0xda, 0xd0, 0x2a,
+	0x66, 0xc3, 0x28, 0xfe, 0x48, 0x58, 0x20, 0x23, 0x3c, 0xab, 0xd5, 0x45, 0xa8, 0x25, 0x02, 0xe6,
+	0x2a, 0x93, 0x52, 0x5c, 0x41, 0x82, 0xee, 0x0b, 0x48, 0x9f, 0x7a, 0x4a, 0x7d, 0xea, 0xc1, 0x2f,
+	0xc1, 0x42, 0x0f, 0xdf, 0xa1, 0x2b, 0xbd, 0x0e, 0xcd, 0x8f, 0x48, 0x12, 0xec, 0x1d, 0x4b, 0x89,
+	0x85, 0x10, 0xa9, 0x45, 0x5b, 0x19, 0x8b, 0x16, 0xfe, 0x22, 0x47, 0x77, 0x42, 0x7f, 0x71, 0x07,
+	0xce, 0x09, 0xa9, 0x22, 0x5f, 0x32, 0x09, 0xda, 0x1e, 0x3f, 0xc5, 0xa1, 0xc3, 0x2d, 0x38, 0x5f,
+	0xc4, 0x8d, 0x51, 0xfc, 0x5b, 0x0b, 0xe6, 0x77, 0xa8, 0xef, 0x71, 0x72, 0x2f, 0x89, 0xf7, 0x82,
+	0x90, 0x4c, 0x6c, 0xac, 0x37, 0x61, 0x86, 0x2a, 0x16, 0xad, 0xd2, 0xa0, 0x8f, 0xcc, 0x1c, 0x86,
+	0x52, 0x6c, 0x60, 0x47, 0xce, 0xee, 0x1e, 0x7a, 0xec, 0x41, 0xab, 0xbc, 0x54, 0x16, 0x1b, 0xa8,
+	0x40, 0x1f, 0x78, 0xec, 0x01, 0xfe, 0x0e, 0x2c, 0xf4, 0x88, 0x77, 0x42, 0x45, 0xfe, 0xce, 0x82,
+	0xfa, 0xbb, 0x89, 0x17, 0x71, 0x27, 0x0e, 0xc9, 0xd3, 0x08, 0x4c, 0x08, 0xc1, 0x54, 0x12, 0x87,
+	0x44, 0x1f, 0x3d, 0xf9, 0x5b, 0xd0, 0x27, 0x84, 0xc5, 0x9d, 0xa4, 0x4d, 0xe4, 0x31, 0xab, 0x3a,
+	0xe9, 0x58, 0xd8, 0xcb, 0x7e, 0x12, 0x77, 0x68, 0xab, 0xa2, 0xec, 0x45, 0x0e, 0xf0, 0x1c, 0x34,
+	0x32, 0x62, 0x32, 0x8a, 0x7f, 0x6f, 0x41, 0x43, 0x39, 0xbd, 0x6f, 0xb8, 0xe4, 0xf3, 0xd0, 0xcc,
+	0xca, 0xc9, 0x28, 0xfe, 0x12, 0xea, 0xeb, 0x1d, 0x7e, 0x10, 0x27, 0xc1, 0x93, 0xc9, 0x05, 0xbf,
+	0x02, 0x40, 0x49, 0x72, 0x18, 0xa8, 0xef, 0x94, 0xe8, 0x19, 0x48, 0x4e, 0xd0, 0x72, 0x5e, 0x50,
+	0xfc, 0x03, 0x68, 0x64, 0x04, 0x50, 0xe7, 0xd9, 0x0b, 0xc3, 0xf8, 0x91, 0x3e, 0xcf, 0xb3, 0x8e,
+	0x19, 0xa2, 0xf3, 0x30, 0x9d, 0x10, 0x8f, 0xa5, 0x53, 0xe8, 0x51, 0x4e, 0x6f, 0xe5, 0x9e, 0x54,
+	0xe4, 0x47, 0xd0, 0xdc, 0x48, 0x88, 0xc7, 0xc9, 0xbb, 0x42, 0x01, 0x13, 0xaf, 0x10, 0xc1, 0x54,
+	0x66, 0x5b, 0xe4, 0x6f, 0xb4, 0x04, 0x35, 0x9f, 0xb0, 0x76, 0x12, 0x50, 0x71, 0x44, 0x8d, 0x3b,
+	0xcf, 0x80, 0xf0, 0xdb, 0x30, 0x97, 0x9b, 0x9c, 0x51, 0xf4, 0x92, 0xd9, 0x17, 0x35, 0xf7, 0x85,
+	0xfe, 0xb9, 0x15, 0xad, 0xde, 0xb0, 0x5f, 0x58, 0x50, 0x5f, 0xf7, 0xfd, 0x0f, 0xc8, 0xe1, 0xee,
+	0x29, 0x72, 0xb5, 0xd4, 0x18, 0x4a, 0x19, 0x63, 0x40, 0xaf, 0xc0, 0xf4, 0xa1, 0xe4, 0x2b, 0x45,
+	0xaf, 0xad, 0xb5, 0xfa, 0x39, 0xe9, 0x79, 0x35, 0x9d, 0x30, 0xfc, 0x8c, 0x30, 0x8c, 0xe2, 0x5f,
+	0x59, 0x30, 0xe7, 0x90, 0xc3, 0xf8, 0x88, 0x7c, 0x43, 0x24, 0x44, 0x30, 0x9f, 0x97, 0x87, 0x51,
+	0xfc, 0x13, 0x0b, 0xce, 0x88, 0xac, 0x41, 0x2a, 0x56, 0xc1, 0xd9, 0xd7, 0x2c, 0xe8, 0x15, 0x00,
+	0x9e, 0x78, 0x11, 0x0b, 0x78, 0x70, 0xa4, 0x6c, 0x70, 0xd6, 0xc9, 0x40, 0xf0, 0xfb, 0x70, 0xb6,
+	0x5f, 0x02, 0x46, 0xd1, 0x1a, 0xcc, 0x28, 0xc1, 0x4d, 0xea, 0x32, 0x78, 0x85, 0x86, 0x10, 0xfb,
+	0xb0, 0x20, 0x78, 0x09, 0xbf, 0x29, 0xf9, 0xb1, 0xa7, 0x92, 0xc2, 0xbf, 0x08, 0xa8, 0x77, 0x16,
+	0x46, 0xc5, 0x09, 0x94, 0x0b, 0x56, 0xe2, 0x56, 0x1d, 0x3d, 0xc2, 0x5f, 0x59, 0x50, 0x37, 0xe4,
+	0x93, 0xcb, 0xf3, 0x0c, 0x54, 0xa9, 0xb7, 0x4f, 0x5c, 0x16, 0x3c, 0x51, 0x02, 0x55, 0xc4, 0x4d,
+	0x69, 0x9f, 0x6c, 0x07, 0x4f, 0x88, 0x48, 0x3b, 0x25, 0x52, 0xc5, 0x6f, 0x9d, 0x76, 0x0a, 0x88,
+	0xca, 0x1e, 0x9e, 0x83, 0x34, 0x43, 0x71, 0x69, 0x42, 0xf6, 0x82, 0xc7, 0xda, 0x25, 0x36, 0x0d,
+	0xf8, 0x9e, 0x84, 0x62, 0x02, 0x8d, 0x8c, 0xa4, 0x8c, 0xa2, 0x17, 0xa1, 0x22, 0xe5, 0xd1, 0x3b,
+	0x30, 0x28, 0x44, 0x29, 0x22, 0x74, 0x1d, 0xe6, 0x22, 0xf2, 0x98, 0xbb, 0x19, 0x59, 0x94, 0xea,
+	0x1a, 0x02, 0x7c, 0xcf, 0xc8, 0x83, 0x3d, 0x68, 0xde, 0x0a, 0x98, 0xb7, 0x1b, 0x92, 0xa7, 0x76,
+	0xcb, 0x7a, 0x01, 0xe6, 0x72, 0x53, 0x0c, 0xcd, 0x85, 0x7e, 0x08, 0x8d, 0x5b, 0x24, 0x24, 0xfc,
+	0xe9, 0x89, 0x33, 0x0f, 0xcd, 0xec, 0x0c, 0x8c, 0xe2, 0x9f, 0xc9, 0xb0, 0x28, 0x90, 0x4f, 0x6b,
+	0x52, 0x93, 0x34, 0xf6, 0xb8, 0x7f, 0x91, 0x34, 0xee, 0x18, 0xb9, 0xbe, 0x2d, 0x62, 0x5e, 0x57,
+	0x88, 0x13, 0xe6, 0x24, 0xb7, 0xa0, 0xb1, 0x19, 0x25, 0x71, 0x18, 0xde, 0xbf, 0x7b, 0xff, 0xde,
+	0xc4, 0x49, 0xdd, 0x16, 0x34, 0xb3, 0x5c, 0xd4, 0x49, 0x62, 0xa4, 0x9d, 0x10, 0xae, 0x53, 0x51,
+	0x3d, 0x12, 0x59, 0x56, 0xcc, 0xa9, 0xd7, 0xe1, 0x07, 0x6e, 0x27, 0x09, 0x4d, 0x2c, 0xd5, 0xa0,
+	0x9d, 0x24, 0xc4, 0xdf, 0x87, 0xe6, 0x46, 0x1c, 0xed, 0x05, 0xc9, 0xe1, 0x69, 0x24, 0x12, 0x01,
+	0xad, 0x1d, 0xfb, 0x69, 0x40, 0x13, 0xbf, 0xf1, 0xeb, 0x30, 0x97, 0x63, 0xcd, 0x28, 0x7a, 0x16,
+	0x9a, 0x09, 0x69, 0xc7, 0x47, 0x24, 0x39, 0x76, 0x05, 0x8d, 0x39, 0xf8, 0x0d, 0x03, 0xdd, 0x10,
+	0x40, 0x21, 0x94, 0x36, 0xc5, 0xaf, 0x5d, 0xa8, 0x05, 0x98, 0xcb, 0xb1, 0x66, 0x14, 0x6f, 0xc1,
+	0x39, 0x95, 0xaf, 0x6f, 0x67, 0x5e, 0x36, 0xc4, 0xa4, 0x97, 0xa0, 0xda, 0x7d, 0xfd, 0x50, 0x7a,
+	0xed, 0x02, 0x0a, 0xb9, 0xff, 0x18, 0xce, 0x17, 0xb1, 0x9a, 0xf4, 0x31, 0x66, 0x15, 0xce, 0xe4,
+	0xd5, 0xe5, 0x86, 0x64, 0x8f, 0x6b, 0x5f, 0xb6, 0x90, 0xd3, 0xd9, 0x1d, 0xb2, 0xc7, 0xf1, 0x36,
+	0x5c, 0x7a, 0x87, 0xec, 0x07, 0xd1, 0xc7, 0x64, 0x57, 0x24, 0x41, 0x91, 0x7a, 0x8b, 0x4a, 0x4e,
+	0x77, 0x83, 0x78, 0x03, 0x2e, 0x0f, 0x61, 0xaa, 0xbc, 0x44, 0x2c, 0x13, 0x14, 0xa6, 0x95, 0x64,
+	0x86, 0xf8, 0xaf, 0x16, 0x5c, 0xbe, 0x1d, 0x44, 0x01, 0x3b, 0xf8, 0x3a, 0x25, 0x42, 0x2b, 0x30,
+	0xdf, 0x0e, 0x03, 0x12, 0x71, 0xd7, 0xf7, 0xb8, 0xe7, 0x7e, 0x6e, 0x52, 0xb8, 0xba, 0xd3, 0x54,
+	0xf0, 0x5b, 0x1e, 0xf7, 0xde, 0x17, 0xa9, 0xdc, 0x4b, 0x80, 0x3c, 0xce, 0x09, 0xe3, 0x72, 0x42,
+	0x37, 0xde, 0xfd, 0x9c, 0xb4, 0xb9, 0x3c, 0xd5, 0x75, 0x67, 0x21, 0x83, 0xb9, 0x2b, 0x11, 0x69,
+	0x5a, 0x36, 0xd5, 0x4d, 0xcb, 0xf0, 0x1e, 0x5c, 0x19, 0xb6, 0x04, 0x46, 0xd1, 0x2d, 0x80, 0x76,
+	0x42, 0x7c, 0x12, 0xf1, 0xc0, 0x0b, 0xf5, 0x32, 0xae, 0xf5, 0x2f, 0xc3, 0x7c, 0xbf, 0x91, 0xd2,
+	0x3a, 0x99, 0xef, 0xf0, 0x4d, 0x38, 0x97, 0x53, 0xf3, 0x38, 0x6f, 0x81, 0x78, 0x0d, 0xce, 0x17,
+	0x7d, 0x34, 0x74, 0x53, 0xfe, 0x65, 0xc1, 0xf9, 0xfc, 0x8a, 0xd2, 0xa9, 0xae, 0x42, 0xa3, 0x2b,
+	0x91, 0x78, 0x8e, 0xb1, 0xa4, 0xa6, 0xea, 0x5d, 0xe0, 0x96, 0x7f, 0x42, 0xed, 0x77, 0xf8, 0x81,
+	0xf8, 0xb2, 0xed, 0x89, 0xb7, 0x44, 0xf1, 0x41, 0xaa, 0xfd, 0x2c, 0x46, 0x7c, 0x22, 0x8e, 0x1b,
+	0x0b, 0xf6, 0x23, 0x8f, 0x77, 0x12, 0xb5, 0x05, 0x75, 0xa7, 0x0b, 0x90, 0xf7, 0x45, 0x46, 0x12,
+	0xf7, 0xc0, 0x8b, 0xfc, 0x90, 0xc8, 0x7b, 0x48, 0xdd, 0x01, 0x01, 0x7a, 0x4f, 0x42, 0xf0, 0x87,
+	0x70, 0xa1, 0x70, 0x59, 0x13, 0x1e, 0x3e, 0xfc, 0x67, 0x4b, 0xe5, 0x2c, 0xeb, 0x1d, 0x3f, 0xe0,
+	0x9b, 0x47, 0x24, 0xe2, 0x93, 0xa7, 0x22, 0xaf, 0xc2, 0xf4, 0x5e, 0x10, 0x72, 0x92, 0xe8, 0x0b,
+	0x72, 0xc1, 0x93, 0x92, 0x9c, 0xe6, 0xb6, 0x24, 0x72, 0x34, 0xf1, 0xa8, 0x24, 0x25, 0x97, 0xe0,
+	0x4c, 0xe5, 0x13, 0x1c, 0xcc, 0x54, 0x96, 0x9a, 0x93, 0x9e, 0x51, 0xf4, 0xff, 0x30, 0x4d, 0xe4,
+	0x48, 0xe7, 0x27, 0x97, 0x06, 0x48, 0x22, 0x3f, 0x71, 0x34, 0xed, 0xd8, 0x69, 0xca, 0xbf, 0x4b,
+	0x30, 0x25, 0xa2, 0xdd, 0xd0, 0x07, 0xec, 0xf4, 0xe9, 0xbc, 0x94, 0x79, 0x3a, 0x17, 0xa1, 0x41,
+	0xfe, 0x70, 0x8f, 0xe4, 0x43, 0x05, 0xf1, 0x75, 0xde, 0xdb, 0x90, 0xd0, 0x8f, 0x34, 0x30, 0xfb,
+	0xd6, 0x30, 0x35, 0xf6, 0x5b, 0xc3, 0xff, 0x41, 0x25, 0x89, 0x43, 0xc2, 0x5a, 0x15, 0xb9, 0xe6,
+	0x67, 0xfa, 0x3f, 0x11, 0x17, 0x58, 0x75, 0x09, 0x57, 0x94, 0x62, 0x01, 0xbe, 0x8a, 0x13, 0x7e,
+	0x6b, 0x5a, 0x0a, 0x92, 0x8e, 0x45, 0x96, 0xc0, 0x63, 0x4e, 0x5d, 0x12, 0x29, 0xfc, 0x8c, 0xc4,
+	0xd7, 0x04, 0x6c, 0x53, 0x81, 0xd0, 0xc7, 0x70, 0xf6, 0x11, 0xd9, 0x15, 0x36, 0x1e, 0xb9, 0xdd,
+	0xd3, 0xc3, 0x5a, 0xb3, 0x4b, 0xe5, 0xb1, 0xbd, 0xc3, 0x19, 0xc3, 0xa1, 0x0b, 0x63, 0xf8, 0x18,
+	0x50, 0x3f, 0x29, 0x6a, 0x42, 0x49, 0x9f, 0xd6, 0xaa, 0x53, 0x0a, 0xfc, 0xc2, 0xfb, 0xe5, 0x65,
+	0xe9, 0xa6, 0x3c, 0x4e, 0x7c, 0xd7, 0x53, 0x3e, 0xb0, 0xec, 0x54, 0x35, 0x64, 0x9d, 0xa3, 0x25,
+	0xa8, 0x87, 0x1e, 0xe3, 0x22, 0xf7, 0x91, 0x04, 0x53, 0x92, 0x00, 0x04, 0x6c, 0x87, 0x09, 0x0a,
+	0x4c, 0xa1, 0x22, 0x73, 0xf7, 0x94, 0xbb, 0x35, 0xf8, 0xf6, 0x5a, 0xea, 0xbb, 0xbd, 0x76, 0x37,
+	0xa1, 0x3c, 0xee, 0x26, 0xe0, 0x37, 0x61, 0x5a, 0x5d, 0x57, 0x46, 0xd9, 0x53, 0xff, 0x1d, 0x0a,
+	0x7f, 0x0b, 0xaa, 0x29, 0xbf, 0xf4, 0xb9, 0xc3, 0x1a, 0xf0, 0xdc, 0x51, 0xea, 0x79, 0x45, 0xf8,
+	0x79, 0x09, 0x66, 0xb4, 0x15, 0x89, 0xdd, 0xf6, 0x03, 0x46, 0x43, 0xef, 0xd8, 0xcd, 0x4c, 0x5f,
+	0xd3, 0xb0, 0x0f, 0xb5, 0x6a, 0xbd, 0x23, 0x8f, 0x7b, 0x49, 0x26, 0xc9, 0xaa, 0x2a, 0xc8, 0x4e,
+	0x12, 0x8a, 0xe4, 0x2c, 0x8c, 0xdb, 0x5e, 0xfa, 0xdc, 0xa2, 0x47, 0x42, 0x02, 0x1e, 0x1c, 0x92,
+	0x27, 0x71, 0x94, 0x3e, 0xb8, 0x98, 0x31, 0xda, 0x02, 0xf0, 0x38, 0x4f, 0x82, 0xdd, 0x0e, 0x4f,
+	0xed, 0xf6, 0xc6, 0x40, 0x53, 0x5f, 0x5d, 0x4f, 0x69, 0x37, 0x23, 0x9e, 0x1c, 0x3b, 0x99, 0x8f,
+	0xed, 0xb7, 0x60, 0xae, 0x07, 0x8d, 0xe6, 0xa1, 0xfc, 0x80, 0x1c, 0xeb, 0xa5, 0x88, 0x9f, 0x42,
+	0x89, 0x47, 0x5e, 0xd8, 0x31, 0xaa, 0x50, 0x83, 0x37, 0x4b, 0xaf, 0x5b, 0xf8, 0x1f, 0x16, 0x40,
+	0xd7, 0x25, 0x14, 0x99, 0x9a, 0x10, 0x5a, 0x7e, 0x57, 0x76, 0xe4, 0x6f, 0xb1, 0x60, 0xaf, 0x9d,
+	0x79, 0xc5, 0xd0, 0x23, 0x31, 0x89, 0xcc, 0x88, 0xf4, 0x6a, 0xd5, 0x40, 0x84, 0x2a, 0xd6, 0x51,
+	0x91, 0x59, 0xbd, 0x2e, 0x99, 0xa1, 0x70, 0x70, 0x3a, 0xd4, 0x04, 0x54, 0x9e, 0xc2, 0xaa, 0x33,
+	0xab, 0x00, 0x5b, 0x2a, 0xc2, 0x75, 0x78, 0x3b, 0x3e, 0x24, 0xad, 0x19, 0x1d, 0xe1, 0xd4, 0x50,
+	0x4c, 0xef, 0x13, 0x2e, 0x3c, 0xcc, 0xac, 0x9a, 0x5e, 0x8d, 0xf0, 0x9f, 0x2c, 0xa8, 0x65, 0xdc,
+	0x6c, 0x46, 0x4c, 0xab, 0x58, 0xcc, 0xd2, 0x00, 0x31, 0xcb, 0x43, 0xc4, 0x9c, 0x1a, 0x2c, 0x66,
+	0x25, 0x2f, 0xe6, 0x59, 0xa8, 0xb0, 0x20, 0x6a, 0x13, 0xb9, 0xb2, 0xb2, 0xa3, 0x06, 0x02, 0xda,
+	0x89, 0x78, 0x10, 0xca, 0x45, 0x95, 0x1d, 0x35, 0xc0, 0xbf, 0xb1, 0x60, 0x66, 0xbb, 0xb7, 0x8e,
+	0x92, 0x7d, 0x75, 0x1e, 0x7a, 0xad, 0x19, 0xf3, 0xe8, 0x33, 0x42, 0xa2, 0x9e, 0xa3, 0xbf, 0x4d,
+	0x48, 0xb4, 0xce, 0x05, 0x03, 0xf2, 0x98, 0x06, 0x09, 0x61, 0x02, 0x5f, 0x51, 0x0c, 0x34, 0x64,
+	0x9d, 0xe3, 0x7f, 0x5a, 0x50, 0xcb, 0x94, 0x44, 0xfa, 0x6c, 0x24, 0x3f, 0x7f, 0x69, 0xd4, 0xfc,
+	0xe5, 0x11, 0xf3, 0x4f, 0xf5, 0xcc, 0x9f, 0xdf, 0x80, 0x4a, 0xcf, 0x06, 0x5c, 0x06, 0x99, 0x25,
+	0xb8, 0xde, 0x3e, 0x89, 0xb8, 0xb6, 0xa2, 0xaa, 0x80, 0xac, 0x0b, 0x80, 0xd8, 0x9f, 0xb6, 0xaa,
+	0xee, 0x69, 0x3f, 0x6e, 0x86, 0xf8, 0xa7, 0x15, 0xa8, 0xdd, 0x4b, 0x82, 0x23, 0x4f, 0xdd, 0x41,
+	0x87, 0xfa, 0xa0, 0xeb, 0xd0, 0x34, 0x95, 0x86, 0xed, 0x03, 0x6f, 0xed, 0xd5, 0xd7, 0x4c, 0x4a,
+	0x94, 0x87, 0x22, 0x0c, 0x75, 0x03, 0x79, 0xcf, 0x63, 0x07, 0xda, 0x92, 0x72, 0xb0, 0x6e, 0x7c,
+	0x9c, 0xca, 0xc6, 0xc7, 0x6b, 0x90, 0x8f, 0x84, 0xad, 0xca, 0x88, 0xf0, 0x38, 0x7d, 0xf2, 0xf0,
+	0x38, 0x33, 0x51, 0x78, 0x9c, 0xed, 0x09, 0x8f, 0x8b, 0x50, 0x33, 0xbf, 0xc5, 0x6e, 0x55, 0xd5,
+	0x6e, 0x1a, 0xd0, 0xba, 0x71, 0x21, 0x30, 0xc0, 0x3c, 0x6a, 0xbd, 0xe6, 0xb1, 0x08, 0x32, 0xb4,
+	0xba, 0xfa, 0x82, 0x5b, 0x57, 0x99, 0x9f, 0x00, 0x6d, 0x4b, 0x48, 0x5f, 0x3c, 0x6e, 0xf4, 0xc7,
+	0xe3, 0x6b, 0xd0, 0x94, 0x24, 0xca, 0xce, 0x38, 0xa1, 0xad, 0xa6, 0x9c, 0x46, 0x7e, 0x78, 0x47,
+	0x18, 0x1a, 0x27, 0xe2, 0x81, 0xf0, 0x6c, 0xee, 0xbe, 0xe5, 0x1e, 0x78, 0xec, 0x80, 0xb0, 0xd6,
+	0x9c, 0xbc, 0xa4, 0xa2, 0xec, 0x85, 0xeb, 0x3d, 0x89, 0x41, 0x9f, 0x0d, 0x88, 0xf3, 0xf3, 0x52,
+	0x93, 0x2f, 0x14, 0x29, 0x5f, 0x1a, 0xd4, 0xb8, 0xe1, 0xfe, 0x2f, 0x16, 0x5c, 0x1c, 0xf8, 0x49,
+	0xe6, 0x9c, 0xd5, 0x8d, 0x22, 0x69, 0x67, 0x37, 0x0c, 0xda, 0xae, 0xf0, 0xee, 0xca, 0x02, 0xab,
+	0x0a, 0xf2, 0x5d, 0x72, 0x2c, 0xd0, 0x22, 0x9f, 0x76, 0xdb, 0x71, 0x27, 0x52, 0xa7, 0xac, 0xa1,
+	0x32, 0xec, 0x0d, 0x01, 0x28, 0xba, 0xfd, 0xf4, 0x6c, 0x4d, 0x65, 0x54, 0xd2, 0x30, 0xdd, 0x97,
+	0x34, 0xfc, 0xc7, 0x82, 0xa6, 0x5e, 0xc0, 0x37, 0xd6, 0x7f, 0x0d, 0x8f, 0x33, 0x79, 0xff, 0x31,
+	0xd3, 0xeb, 0x3f, 0x2e, 0xc0, 0x8c, 0x44, 0x07, 0xbe, 0x89, 0x36, 0x62, 0xb8, 0xe5, 0xe3, 0x3f,
+	0x5a, 0x80, 0xf4, 0xca, 0xd5, 0x83, 0x91, 0x3f, 0xd2, 0x8b, 0x64, 0x78, 0x95, 0xb2, 0xbc, 0x84,
+	0x0c, 0x89, 0xe2, 0xe1, 0xf2, 0xd8, 0x5c, 0x04, 0x34, 0xe4, 0x7e, 0x9c, 0x45, 0x77, 0xdd, 0xa3,
+	0x86, 0xac, 0x73, 0xf5, 0xea, 0xc2, 0x48, 0x72, 0x44, 0x7c, 0x57, 0xc5, 0x16, 0xa5, 0x81, 0x86,
+	0x81, 0xee, 0xc8, 0x18, 0xf3, 0x4b, 0x0b, 0x16, 0x52, 0x81, 0xd3, 0x3a, 0xea, 0x65, 0x00, 0xb9,
+	0x41, 0xf2, 0x2c, 0x98, 0x57, 0x10, 0x09, 0x91, 0xce, 0xea, 0x14, 0xdb, 0x36, 0xdc, 0xa9, 0xe3,
+	0xbf, 0x5b, 0x70, 0x4e, 0x8b, 0x23, 0x6f, 0x72, 0x69, 0xaf, 0x89, 0x58, 0x4f, 0xfa, 0x0c, 0x93,
+	0x15, 0xab, 0x91, 0x42, 0xa5, 0x68, 0xc3, 0xb4, 0x39, 0xb9, 0x5c, 0x62, 0xc5, 0x7b, 0x5e, 0x10,
+	0x76, 0x12, 0x99, 0x97, 0xc9, 0x4b, 0x97, 0x19, 0x8b, 0x23, 0xf4, 0x20, 0x88, 0x7c, 0x6d, 0x43,
+	0xf2, 0x37, 0xfe, 0x83, 0x05, 0x2d, 0xbd, 0x8e, 0x6c, 0x0d, 0xf6, 0xd4, 0xda, 0x2d, 0xec, 0x40,
+	0xea, 0x59, 0xdb, 0xd4, 0xf0, 0xb5, 0xf5, 0x05, 0xf2, 0x5f, 0x5b, 0x50, 0xd7, 0xb2, 0xfe, 0x6f,
+	0x53, 0xfd, 0x11, 0x72, 0xaf, 0x7d, 0xd5, 0x82, 0x8a, 0x7c, 0x63, 0x47, 0x5b, 0x30, 0x6b, 0x5a,
+	0xac, 0x50, 0xc1, 0x35, 0x3a, 0xd3, 0xdb, 0x65, 0x5f, 0x19, 0x86, 0x66, 0x14, 0xbd, 0x0d, 0x15,
+	0x69, 0x59, 0xc8, 0xee, 0x27, 0x34, 0x6f, 0x22, 0xf6, 0x33, 0x03, 0x71, 0x8c, 0xa2, 0xb7, 0xf4,
+	0x75, 0xf7, 0xe2, 0x80, 0x47, 0x5f, 0xf2, 0xd0, 0xb6, 0x07, 0xa1, 0x18, 0x45, 0x0e, 0xd4, 0x32,
+	0x9d, 0x45, 0x68, 0xa9, 0x9f, 0x34, 0xdf, 0xbf, 0x64, 0x2f, 0x8f, 0xa0, 0x60, 0x14, 0x6d, 0xc0,
+	0xb4, 0xea, 0xec, 0x41, 0xc5, 0x92, 0xab, 0x46, 0x24, 0xfb, 0xd2, 0x60, 0x24, 0xa3, 0xe8, 0x8e,
+	0xe9, 0x59, 0x5a, 0x0f, 0x43, 0x74, 0x65, 0x10, 0xa9, 0x6a, 0x40, 0xb2, 0x17, 0x87, 0xe2, 0x19,
+	0x45, 0xdf, 0x33, 0xe5, 0x6c, 0x13, 0x01, 0x70, 0xd1, 0xc6, 0xe4, 0x5b, 0x8c, 0xec, 0xab, 0x23,
+	0x69, 0x18, 0x45, 0x3b, 0xaa, 0x4e, 0xa4, 0x41, 0x0c, 0x15, 0xe8, 0xa7, 0xa7, 0x51, 0xc8, 0xc6,
+	0xa3, 0x48, 0x18, 0x45, 0x9f, 0x42, 0x33, 0xdf, 0x03, 0x83, 0x0a, 0xa4, 0xe9, 0xeb, 0xf4, 0xb1,
+	0xaf, 0x8d, 0x26, 0x62, 0x14, 0x1d, 0xc2, 0xd9, 0xa2, 0x4e, 0x17, 0x74, 0xa3, 0x68, 0xc1, 0x85,
+	0x8d, 0x35, 0xf6, 0xf3, 0xe3, 0x92, 0x1a, 0xe5, 0x67, 0x9a, 0x5c, 0x8a, 0x95, 0x9f, 0xef, 0xae,
+	0xb1, 0xaf, 0x8e, 0xa4, 0x51, 0xd6, 0x9b, 0xe9, 0x73, 0x29, 0xb2, 0xde, 0x7c, 0xbb, 0x8c, 0xbd,
+	0x3c, 0x82, 0x82, 0x51, 0xb4, 0x0f, 0xa8, 0xbf, 0x5d, 0x05, 0x3d, 0x57, 0x2c, 0x4e, 0x5f, 0x8b,
+	0x8c, 0xbd, 0x32, 0x1e, 0xa1, 0x52, 0x4b, 0xae, 0xbb, 0xa4, 0x48, 0x2d, 0xbd, 0xdd, 0x31, 0xf6,
+	0xd5, 0x91, 0x34, 0xea, 0xec, 0xa4, 0xed, 0x1c, 0x45, 0x67, 0x27, 0xdb, 0x92, 0x62, 0x2f, 0x0e,
+	0xc5, 0x33, 0x8a, 0xee, 0x02, 0x74, 0x5b, 0x2c, 0xd0, 0xe2, 0xa0, 0x43, 0x61, 0xf8, 0x2d, 0x0d,
+	0x27, 0x50, 0xe2, 0xa5, 0x0d, 0x12, 0x45, 0xe2, 0x65, 0xdb, 0x37, 0xec, 0xc5, 0xa1, 0x78, 0xed,
+	0xc1, 0xba, 0x2d, 0x09, 0x85, 0x1e, 0x2c, 0xd7, 0x2e, 0x61, 0x2f, 0x8f, 0xa0, 0xd0, 0x12, 0x9a,
+	0xb6, 0x80, 0x42, 0x09, 0x33, 0x0d, 0x0c, 0xf6, 0xe2, 0x50, 0xbc, 0x72, 0x11, 0xd9, 0x12, 0x7e,
+	0x91, 0x8b, 0xe8, 0x69, 0x39, 0xb0, 0xf1, 0x28, 0x12, 0x46, 0x91, 0xa7, 0x5a, 0x07, 0xb3, 0x25,
+	0x78, 0xf4, 0x6c, 0xb1, 0x6b, 0xe9, 0x69, 0x14, 0xb0, 0xaf, 0x8f, 0x43, 0xa6, 0xbc, 0x50, 0xbe,
+	0x66, 0x5e, 0xe4, 0x85, 0xfa, 0x6a, 0xf7, 0xf6, 0xb5, 0xd1, 0x44, 0xda, 0xc3, 0x6b, 0x28, 0x2b,
+	0xf4, 0xf0, 0x99, 0xf2, 0xbb, 0xbd, 0x38, 0x14, 0xaf, 0xcc, 0x20, 0x53, 0x3b, 0x2e, 0x32, 0x83,
+	0x7c, 0xf5, 0xda, 0x5e, 0x1e, 0x41, 0xa1, 0x2c, 0xbf, 0x5b, 0x00, 0x2e, 0xb2, 0xfc, 0x5c, 0x01,
+	0xda, 0x5e, 0x1a, 0x4e, 0x60, 0x8e, 0x92, 0xa9, 0xdc, 0x16, 0x1f, 0xa5, 0x4c, 0x71, 0xd9, 0x5e,
+	0x1a, 0x4e, 0xa0, 0x18, 0x76, 0xcb, 0xb0, 0x45, 0x0c, 0x73, 0xa5, 0x5e, 0x7b, 0x69, 0x38, 0x81,
+	0x3e, 0x4d, 0xdd, 0x8a, 0x69, 0xe1, 0x69, 0xca, 0xd5, 0x6a, 0xed, 0xe5, 0x11, 0x14, 0xb9, 0xad,
+	0x19, 0xc4, 0x33, 0x5f, 0x6a, 0xb5, 0x97, 0x47, 0x50, 0x28, 0x2f, 0xdd, 0x5f, 0xe6, 0x2c, 0xf2,
+	0xd2, 0x85, 0x75, 0x55, 0x7b, 0x65, 0x3c, 0x42, 0x46, 0xd1, 0x17, 0x70, 0x71, 0x60, 0xed, 0x11,
+	0xad, 0xf6, 0xb3, 0x19, 0x56, 0xfd, 0xb4, 0x5f, 0x3e, 0x11, 0x3d, 0xa3, 0xe8, 0x4b, 0xb0, 0x07,
+	0x97, 0xfe, 0x50, 0x01, 0xbb, 0xa1, 0xb5, 0x4e, 0xfb, 0x95, 0x93, 0x7d, 0xa0, 0xf4, 0xdc, 0x5f,
+	0xde, 0x2b, 0xd2, 0x73, 0x61, 0xe5, 0xd0, 0x5e, 0x19, 0x8f, 0x90, 0x51, 0xf4, 0x39, 0x9c, 0x29,
+	0xa8, 0x9d, 0xa1, 0x95, 0x51, 0x12, 0xa7, 0x53, 0xdd, 0x18, 0x93, 0x92, 0x51, 0xf4, 0x99, 0x6a,
+	0xde, 0xce, 0x14, 0xa6, 0xd0, 0x00, 0x97, 0x95, 0xaf, 0xbc, 0xd9, 0xcf, 0x8e, 0x41, 0xc5, 0xe8,
+	0x3b, 0x33, 0x9f, 0xa8, 0xde, 0x9a, 0xdd, 0x69, 0xf9, 0x17, 0x90, 0x9b, 0xff, 0x1d, 0x00, 0x16,
+	0x4f, 0x9a, 0xdb, 0x1d, 0x32, 0x00, 0x00,
 }
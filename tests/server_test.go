@@ -8,73 +8,3047 @@ import (
 	"github.com/twitchtv/twirp"
 	"github.com/ericmoritz/twirp-users/internal/usersservice"
 	"os"
+	"crypto/sha256"
+	"time"
+	"bytes"
+	"log"
+	"strings"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"crypto/sha1"
+	"encoding/hex"
+	"expvar"
+	"errors"
+	"crypto/hmac"
+	"encoding/base32"
+	"encoding/binary"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"regexp"
 )
 
 // Test tests the server
 func Test(t *testing.T) {
 	g := Goblin(t)
 
-	g.Describe("Users API", func() {
+	// Usernames such as eric/x break DefaultPolicy, the suites checking that
+	// a / in a name can not reach another user's records use this one
+	testPolicy := &usersservice.Policy{}
+
+	backends := []struct {
+		name  string
+		store func(name string) usersservice.Option
+	}{
+		{"leveldb", func(name string) usersservice.Option {
+			testDbPath := "/tmp/" + name + ".db"
+			// Delete the db if it exists
+			if _, err := os.Stat(testDbPath); err == nil {
+				if err := os.RemoveAll(testDbPath); err != nil {
+					panic(err)
+				}
+			}
+			return usersservice.WithLevelDB(testDbPath)
+		}},
+		{"sqlite", func(name string) usersservice.Option {
+			testDbPath := "/tmp/" + name + ".sqlite"
+			os.Remove(testDbPath)
+			return usersservice.WithSQLite(testDbPath)
+		}},
+		{"memory", func(name string) usersservice.Option {
+			return usersservice.WithMemoryStore()
+		}},
+	}
+
+	for _, backend := range backends {
+		backend := backend
+		g.Describe("Users API ("+backend.name+")", func() {
+			var service pb.Users
+			var auditLog bytes.Buffer
+			notifier := &recordingNotifier{}
+
+			g.Before(func() {
+				if s, err := usersservice.New(backend.store("usersservice"), usersservice.WithNotifier(notifier)); err == nil {
+					s.AuditLog = log.New(&auditLog, "", 0)
+					service = s
+				} else {
+					panic(err)
+				}
+			})
+
+			g.It("Happy Case", func() {
+				// Test registration
+				resp, err := service.Register(context.Background(), &pb.RegisterReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				g.Assert(resp.User.Username).Equal("eric")
+
+				// Test login
+				loginResp, err := service.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+
+				// Test User request
+				userResp, err := service.User(
+					context.Background(),
+					&pb.UserReq{
+						Username: "eric",
+					},
+				)
+				g.Assert(err).Equal(nil)
+				g.Assert(userResp.User.Username).Equal("eric")
+
+				// Test CurrentUser request
+				currentUserResp, err := service.CurrentUser(
+					context.Background(),
+					&pb.CurrentUserReq{
+						Session: loginResp.Session,
+					},
+				)
+				g.Assert(err).Equal(nil)
+				g.Assert(currentUserResp.User.Username).Equal("eric")
+			})
+
+			g.It("Should fail to register if the username is blank", func() {
+				_, err := service.Register(context.Background(), &pb.RegisterReq{Username: "", Password: "correct horse"})
+				g.Assert(err).Equal(twirp.RequiredArgumentError("RegisterReq.username"))
+			})
+
+			g.It("Should fail to register if the password is blank", func() {
+				_, err := service.Register(context.Background(), &pb.RegisterReq{Username: "eric", Password: ""})
+				g.Assert(err).Equal(twirp.RequiredArgumentError("RegisterReq.password"))
+			})
+
+			g.It("Should fail to login with the wrong password", func() {
+				_, err := service.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "wrong"})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad password"))
+			})
+
+			g.It("Should only trust the session token", func() {
+				_, err := service.Register(context.Background(), &pb.RegisterReq{Username: "mallory", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				loginResp, err := service.Login(context.Background(), &pb.LoginReq{Username: "mallory", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+
+				// The token alone is enough
+				currentUserResp, err := service.CurrentUser(
+					context.Background(),
+					&pb.CurrentUserReq{Session: &pb.Session{Token: loginResp.Session.Token}},
+				)
+				g.Assert(err).Equal(nil)
+				g.Assert(currentUserResp.User.Username).Equal("mallory")
+
+				// Claiming to be someone else is rejected and audited
+				_, err = service.CurrentUser(
+					context.Background(),
+					&pb.CurrentUserReq{Session: &pb.Session{Token: loginResp.Session.Token, Username: "eric"}},
+				)
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid session"))
+				g.Assert(strings.Contains(auditLog.String(), `token for "mallory" presented as "eric"`)).IsTrue()
+			})
+
+			g.It("Should require a session token", func() {
+				_, err := service.CurrentUser(context.Background(), &pb.CurrentUserReq{})
+				g.Assert(err).Equal(twirp.RequiredArgumentError("session.token"))
+			})
+
+			g.It("Should normalize emails and keep them unique", func() {
+				resp, err := service.Register(context.Background(), &pb.RegisterReq{Username: "emily", Password: "correct horse", Email: " Emily@Example.com "})
+				g.Assert(err).Equal(nil)
+				g.Assert(resp.User.Email).Equal("emily@example.com")
+				g.Assert(resp.User.EmailVerified).IsFalse()
+
+				// An unverified email reserves nothing, the first user to verify
+				// it takes it
+				_, err = service.Register(context.Background(), &pb.RegisterReq{Username: "squatter", Password: "correct horse", Email: "EMILY@example.com"})
+				g.Assert(err).Equal(nil)
+				g.Assert(notifier.verify(service, "emily")).Equal(nil)
+				g.Assert(notifier.verify(service, "squatter")).Equal(twirp.NewError(twirp.AlreadyExists, "Email: emily@example.com already in use"))
+
+				_, err = service.Register(context.Background(), &pb.RegisterReq{Username: "emily2", Password: "correct horse", Email: "EMILY@example.com"})
+				g.Assert(err).Equal(twirp.NewError(twirp.AlreadyExists, "Email: emily@example.com already in use"))
+
+				// Users without an email do not collide
+				_, err = service.Register(context.Background(), &pb.RegisterReq{Username: "noemail1", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				_, err = service.Register(context.Background(), &pb.RegisterReq{Username: "noemail2", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+			})
+
+			g.It("Should reject an invalid email", func() {
+				_, err := service.Register(context.Background(), &pb.RegisterReq{Username: "bad", Password: "correct horse", Email: "Bad <bad@example.com>"})
+				g.Assert(err).Equal(twirp.InvalidArgumentError("RegisterReq.email", "must be an email address"))
+			})
+
+			g.It("Should update the profile by field mask", func() {
+				ctx := context.Background()
+				login, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+
+				_, err = service.UpdateProfile(ctx, &pb.UpdateProfileReq{
+					Session: login.Session,
+					Profile: &pb.Profile{
+						DisplayName: "Eric",
+						Locale:      "en-US",
+						Attributes:  map[string]string{"team": "core", "shell": "zsh"},
+					},
+					UpdateMask: []string{"display_name", "attributes"},
+				})
+				g.Assert(err).Equal(nil)
+
+				// Only locale and one attribute change
+				resp, err := service.UpdateProfile(ctx, &pb.UpdateProfileReq{
+					Session:    login.Session,
+					Profile:    &pb.Profile{Locale: "fr-CA", Attributes: map[string]string{"team": "web"}},
+					UpdateMask: []string{"locale", "attributes.team", "attributes.shell"},
+				})
+				g.Assert(err).Equal(nil)
+				g.Assert(resp.User.Profile.DisplayName).Equal("Eric")
+				g.Assert(resp.User.Profile.Locale).Equal("fr-CA")
+
+				user, err := service.User(ctx, &pb.UserReq{Username: "eric"})
+				g.Assert(err).Equal(nil)
+				g.Assert(user.User.Profile.DisplayName).Equal("Eric")
+				g.Assert(user.User.Profile.Locale).Equal("fr-CA")
+				g.Assert(user.User.Profile.Attributes).Equal(map[string]string{"team": "web"})
+			})
+
+			// TODO the rest of the owl.
+		})
+
+		g.Describe("Role based access control ("+backend.name+")", func() {
+			var service pb.Users
+			var grantAdmins func([]string) ([]string, error)
+			var root, alice *pb.Session
+			ctx := context.Background()
+
+			authorize := func(session *pb.Session, permission, resource string) *pb.AuthorizeResp {
+				resp, err := service.Authorize(ctx, &pb.AuthorizeReq{Session: session, Permission: permission, Resource: resource})
+				g.Assert(err).Equal(nil)
+				return resp
+			}
+			grant := func(session *pb.Session, role, resource string) error {
+				_, err := service.GrantRole(ctx, &pb.GrantRoleReq{Session: session, Username: "alice", Role: role, Resource: resource})
+				return err
+			}
+			revoke := func(role, resource string) error {
+				_, err := service.RevokeRole(ctx, &pb.RevokeRoleReq{Session: root, Username: "alice", Role: role, Resource: resource})
+				return err
+			}
+
+			g.Before(func() {
+				s, err := usersservice.New(
+					backend.store("usersservice-rbac"),
+					usersservice.WithPasswordHasher(&usersservice.BcryptHasher{Cost: 4}),
+					usersservice.WithRoles(map[string][]string{
+						"admin":  {"*"},
+						"editor": {"documents.*"},
+						"viewer": {"documents.read"},
+					}),
+				)
+				if err != nil {
+					panic(err)
+				}
+				s.AuditLog = nil
+				service = s
+				grantAdmins = s.GrantAdmins
+
+				sessions := map[string]*pb.Session{}
+				for _, username := range []string{"ops", "alice"} {
+					if _, err := service.Register(ctx, &pb.RegisterReq{Username: username, Password: "correct horse"}); err != nil {
+						panic(err)
+					}
+					login, err := service.Login(ctx, &pb.LoginReq{Username: username, Password: "correct horse"})
+					if err != nil {
+						panic(err)
+					}
+					sessions[username] = login.Session
+				}
+				if _, err := s.GrantAdmins([]string{"ops"}); err != nil {
+					panic(err)
+				}
+				root, alice = sessions["ops"], sessions["alice"]
+			})
+
+			g.It("Should only let admins grant roles", func() {
+				g.Assert(grant(alice, "editor", "")).Equal(twirp.NewError(twirp.PermissionDenied, "admin only"))
+				g.Assert(grant(root, "owner", "")).Equal(twirp.InvalidArgumentError("role", `unknown role "owner"`))
+			})
+
+			g.It("Should grant the admin role to the existing ADMINS users", func() {
+				g.Assert(authorize(root, usersservice.PermissionAdmin, "").Reason).Equal("allowed by role admin")
+
+				missing, err := grantAdmins([]string{"ops", "Alice", "nobody"})
+				g.Assert(err).Equal(nil)
+				g.Assert(missing).Equal([]string{"nobody"})
+				g.Assert(authorize(alice, usersservice.PermissionAdmin, "").Allowed).IsTrue()
+
+				// The role is stored, revoking it is enough
+				g.Assert(revoke("admin", "")).Equal(nil)
+				g.Assert(authorize(alice, usersservice.PermissionAdmin, "").Allowed).IsFalse()
+			})
+
+			g.It("Should allow the permissions of granted roles", func() {
+				resp := authorize(alice, "documents.write", "")
+				g.Assert(resp.Allowed).IsFalse()
+				g.Assert(resp.Reason).Equal("no role grants documents.write")
+
+				g.Assert(grant(root, "editor", "")).Equal(nil)
+				resp = authorize(alice, "documents.write", "")
+				g.Assert(resp.Allowed).IsTrue()
+				g.Assert(resp.Reason).Equal("allowed by role editor")
+				g.Assert(resp.Username).Equal("alice")
+				g.Assert(authorize(alice, "users.admin", "").Allowed).IsFalse()
+
+				current, err := service.CurrentUser(ctx, &pb.CurrentUserReq{Session: alice})
+				g.Assert(err).Equal(nil)
+				g.Assert(len(current.User.Roles)).Equal(1)
+				g.Assert(current.User.Roles[0].Role).Equal("editor")
+
+				g.Assert(revoke("editor", "")).Equal(nil)
+				g.Assert(authorize(alice, "documents.write", "").Allowed).IsFalse()
+				g.Assert(revoke("editor", "")).Equal(twirp.NewError(twirp.NotFound, "role not granted"))
+			})
+
+			g.It("Should find users by their normalized name", func() {
+				_, err := service.GrantRole(ctx, &pb.GrantRoleReq{Session: root, Username: "ALICE", Role: "editor"})
+				g.Assert(err).Equal(nil)
+				g.Assert(authorize(alice, "documents.write", "").Allowed).IsTrue()
+
+				_, err = service.RevokeRole(ctx, &pb.RevokeRoleReq{Session: root, Username: " Alice", Role: "editor"})
+				g.Assert(err).Equal(nil)
+				g.Assert(authorize(alice, "documents.write", "").Allowed).IsFalse()
+
+				_, err = service.GrantRole(ctx, &pb.GrantRoleReq{Session: root, Username: "nobody", Role: "editor"})
+				g.Assert(err).Equal(twirp.NewError(twirp.NotFound, "nobody not found"))
+			})
+
+			g.It("Should scope grants to resources", func() {
+				g.Assert(grant(root, "viewer", "projects/42/*")).Equal(nil)
+				g.Assert(authorize(alice, "documents.read", "projects/42/readme").Allowed).IsTrue()
+
+				resp := authorize(alice, "documents.read", "projects/7/readme")
+				g.Assert(resp.Allowed).IsFalse()
+				g.Assert(resp.Reason).Equal("no role grants documents.read on projects/7/readme")
+			})
+
+			g.It("Should let a user with the admin role call admin rpcs", func() {
+				g.Assert(grant(root, "admin", "")).Equal(nil)
+				g.Assert(grant(alice, "editor", "")).Equal(nil)
+			})
+		})
+
+		g.Describe("Groups ("+backend.name+")", func() {
+			var service pb.Users
+			var root, alice *pb.Session
+			ctx := context.Background()
+
+			addMember := func(group string, member *pb.Member) error {
+				_, err := service.AddMember(ctx, &pb.AddMemberReq{Session: root, Group: group, Member: member})
+				return err
+			}
+			userGroups := func(session *pb.Session, username string) []string {
+				resp, err := service.ListUserGroups(ctx, &pb.ListUserGroupsReq{Session: session, Username: username})
+				g.Assert(err).Equal(nil)
+				return resp.Groups
+			}
+
+			g.Before(func() {
+				s, err := usersservice.New(
+					backend.store("usersservice-groups"),
+					usersservice.WithPolicy(testPolicy),
+					usersservice.WithPasswordHasher(&usersservice.BcryptHasher{Cost: 4}),
+					usersservice.WithRoles(map[string][]string{"admin": {"*"}, "pager": {"pages.*"}}),
+				)
+				if err != nil {
+					panic(err)
+				}
+				s.AuditLog = nil
+				service = s
+
+				sessions := map[string]*pb.Session{}
+				for _, username := range []string{"ops", "alice", "alice/x"} {
+					if _, err := service.Register(ctx, &pb.RegisterReq{Username: username, Password: "correct horse"}); err != nil {
+						panic(err)
+					}
+					login, err := service.Login(ctx, &pb.LoginReq{Username: username, Password: "correct horse"})
+					if err != nil {
+						panic(err)
+					}
+					sessions[username] = login.Session
+				}
+				if _, err := s.GrantAdmins([]string{"ops"}); err != nil {
+					panic(err)
+				}
+				root, alice = sessions["ops"], sessions["alice"]
+			})
+
+			g.It("Should let admins create groups", func() {
+				_, err := service.CreateGroup(ctx, &pb.CreateGroupReq{Session: alice, Name: "eng"})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "admin only"))
+				_, err = service.CreateGroup(ctx, &pb.CreateGroupReq{Session: root, Name: "Eng Team"})
+				g.Assert(err.(twirp.Error).Code()).Equal(twirp.InvalidArgument)
+
+				for _, name := range []string{"eng", "oncall", "other"} {
+					resp, err := service.CreateGroup(ctx, &pb.CreateGroupReq{Session: root, Name: name})
+					g.Assert(err).Equal(nil)
+					g.Assert(resp.Group.Name).Equal(name)
+				}
+				_, err = service.CreateGroup(ctx, &pb.CreateGroupReq{Session: root, Name: "eng"})
+				g.Assert(err).Equal(twirp.NewError(twirp.AlreadyExists, "Group: eng already exists"))
+			})
+
+			g.It("Should resolve nested groups in both directions", func() {
+				g.Assert(addMember("oncall", &pb.Member{Username: "alice"})).Equal(nil)
+				g.Assert(addMember("oncall", &pb.Member{Username: "alice"})).Equal(nil)
+				g.Assert(addMember("eng", &pb.Member{Group: "oncall"})).Equal(nil)
+				g.Assert(addMember("other", &pb.Member{Username: "alice/x"})).Equal(nil)
+
+				g.Assert(userGroups(alice, "")).Equal([]string{"eng", "oncall"})
+
+				direct, err := service.ListGroupMembers(ctx, &pb.ListGroupMembersReq{Session: alice, Group: "eng"})
+				g.Assert(err).Equal(nil)
+				g.Assert(len(direct.Members)).Equal(1)
+				g.Assert(direct.Members[0].Group).Equal("oncall")
+
+				transitive, err := service.ListGroupMembers(ctx, &pb.ListGroupMembersReq{Session: alice, Group: "eng", Transitive: true})
+				g.Assert(err).Equal(nil)
+				g.Assert(len(transitive.Members)).Equal(1)
+				g.Assert(transitive.Members[0].Username).Equal("alice")
+			})
+
+			g.It("Should refuse to create a cycle", func() {
+				err := addMember("oncall", &pb.Member{Group: "eng"})
+				g.Assert(err).Equal(twirp.NewError(twirp.FailedPrecondition, "adding group eng to oncall would create a cycle"))
+				err = addMember("eng", &pb.Member{Group: "eng"})
+				g.Assert(err.(twirp.Error).Code()).Equal(twirp.FailedPrecondition)
+			})
+
+			g.It("Should grant the roles of groups to their members", func() {
+				_, err := service.GrantRole(ctx, &pb.GrantRoleReq{Session: root, Group: "eng", Role: "pager"})
+				g.Assert(err).Equal(nil)
+
+				resp, err := service.Authorize(ctx, &pb.AuthorizeReq{Session: alice, Permission: "pages.ack"})
+				g.Assert(err).Equal(nil)
+				g.Assert(resp.Allowed).IsTrue()
+				g.Assert(resp.Reason).Equal("allowed by role pager via group eng")
+
+				// Members are found by the normalized name
+				_, err = service.RemoveMember(ctx, &pb.RemoveMemberReq{Session: root, Group: "oncall", Member: &pb.Member{Username: "Alice"}})
+				g.Assert(err).Equal(nil)
+				resp, err = service.Authorize(ctx, &pb.AuthorizeReq{Session: alice, Permission: "pages.ack"})
+				g.Assert(err).Equal(nil)
+				g.Assert(resp.Allowed).IsFalse()
+
+				_, err = service.RemoveMember(ctx, &pb.RemoveMemberReq{Session: root, Group: "oncall", Member: &pb.Member{Username: "alice"}})
+				g.Assert(err).Equal(twirp.NewError(twirp.NotFound, "not a member"))
+			})
+
+			g.It("Should only let admins list the groups of other users", func() {
+				_, err := service.ListUserGroups(ctx, &pb.ListUserGroupsReq{Session: alice, Username: "alice/x"})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "admin only"))
+				g.Assert(userGroups(root, "alice/x")).Equal([]string{"other"})
+			})
+		})
+
+		g.Describe("Listing users ("+backend.name+")", func() {
+			var service pb.Users
+			var root, alice *pb.Session
+			ctx := context.Background()
+
+			list := func(req *pb.ListUsersReq) *pb.ListUsersResp {
+				req.Session = root
+				resp, err := service.ListUsers(ctx, req)
+				g.Assert(err).Equal(nil)
+				return resp
+			}
+			usernames := func(resp *pb.ListUsersResp) []string {
+				names := []string{}
+				for _, user := range resp.Users {
+					names = append(names, user.Username)
+				}
+				return names
+			}
+
+			g.Before(func() {
+				s, err := usersservice.New(
+					backend.store("usersservice-list"),
+					usersservice.WithPasswordHasher(&usersservice.BcryptHasher{Cost: 4}),
+				)
+				if err != nil {
+					panic(err)
+				}
+				s.AuditLog = nil
+				service = s
+
+				for _, username := range []string{"ops", "dave", "alice", "dan", "da", "carol", "db"} {
+					if _, err := service.Register(ctx, &pb.RegisterReq{Username: username, Password: "correct horse"}); err != nil {
+						panic(err)
+					}
+				}
+				if _, err := s.GrantAdmins([]string{"ops"}); err != nil {
+					panic(err)
+				}
+				for _, username := range []string{"ops", "alice"} {
+					login, err := service.Login(ctx, &pb.LoginReq{Username: username, Password: "correct horse"})
+					if err != nil {
+						panic(err)
+					}
+					if username == "ops" {
+						root = login.Session
+					} else {
+						alice = login.Session
+					}
+				}
+			})
+
+			g.It("Should only let admins list users", func() {
+				_, err := service.ListUsers(ctx, &pb.ListUsersReq{Session: alice})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "admin only"))
+			})
+
+			g.It("Should list every user in username order", func() {
+				resp := list(&pb.ListUsersReq{})
+				g.Assert(usernames(resp)).Equal([]string{"alice", "carol", "da", "dan", "dave", "db", "ops"})
+				g.Assert(resp.NextPageToken).Equal("")
+			})
+
+			g.It("Should page through users with a prefix", func() {
+				seen := []string{}
+				req := &pb.ListUsersReq{PageSize: 2, UsernamePrefix: "da"}
+				for pages := 1; ; pages++ {
+					resp := list(req)
+					seen = append(seen, usernames(resp)...)
+					if resp.NextPageToken == "" {
+						g.Assert(pages).Equal(2)
+						break
+					}
+					req.PageToken = resp.NextPageToken
+				}
+				g.Assert(seen).Equal([]string{"da", "dan", "dave"})
+			})
+
+			g.It("Should reject a page token from another listing", func() {
+				resp := list(&pb.ListUsersReq{PageSize: 1, UsernamePrefix: "d"})
+				g.Assert(resp.NextPageToken != "").IsTrue()
+
+				for _, req := range []*pb.ListUsersReq{
+					{Session: root, PageToken: resp.NextPageToken, UsernamePrefix: "da"},
+					{Session: root, PageToken: "not a token"},
+					{Session: root, PageSize: -1},
+				} {
+					_, err := service.ListUsers(ctx, req)
+					g.Assert(err.(twirp.Error).Code()).Equal(twirp.InvalidArgument)
+				}
+			})
+
+			g.It("Should cap the page size", func() {
+				resp := list(&pb.ListUsersReq{PageSize: usersservice.MaxListUsersPageSize + 1})
+				g.Assert(len(resp.Users)).Equal(7)
+			})
+		})
+
+		g.Describe("Account lifecycle ("+backend.name+")", func() {
+			var service pb.Users
+			var auditLog bytes.Buffer
+			sessions := map[string]*pb.Session{}
+			ctx := context.Background()
+
+			login := func(username, password string) (*pb.Session, error) {
+				resp, err := service.Login(ctx, &pb.LoginReq{Username: username, Password: password})
+				if err != nil {
+					return nil, err
+				}
+				return resp.Session, nil
+			}
+
+			g.Before(func() {
+				s, err := usersservice.New(
+					backend.store("usersservice-lifecycle"),
+					usersservice.WithPasswordHasher(&usersservice.BcryptHasher{Cost: 4}),
+					usersservice.WithNotifier(&recordingNotifier{}),
+				)
+				if err != nil {
+					panic(err)
+				}
+				s.AuditLog = log.New(&auditLog, "", 0)
+				service = s
+
+				for _, username := range []string{"ops", "alice", "bob"} {
+					_, err := service.Register(ctx, &pb.RegisterReq{Username: username, Password: "correct horse", Email: username + "@example.com"})
+					if err != nil {
+						panic(err)
+					}
+					if sessions[username], err = login(username, "correct horse"); err != nil {
+						panic(err)
+					}
+				}
+				if _, err := s.GrantAdmins([]string{"ops"}); err != nil {
+					panic(err)
+				}
+				if _, err := service.CreateGroup(ctx, &pb.CreateGroupReq{Session: sessions["ops"], Name: "eng"}); err != nil {
+					panic(err)
+				}
+				if _, err := service.AddMember(ctx, &pb.AddMemberReq{Session: sessions["ops"], Group: "eng", Member: &pb.Member{Username: "bob"}}); err != nil {
+					panic(err)
+				}
+			})
+
+			g.It("Should only let admins disable and delete users", func() {
+				_, err := service.DisableUser(ctx, &pb.DisableUserReq{Session: sessions["alice"], Username: "bob"})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "admin only"))
+				_, err = service.DeleteUser(ctx, &pb.DeleteUserReq{Session: sessions["alice"], Username: "bob"})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "admin only"))
+				_, err = service.DeleteUser(ctx, &pb.DeleteUserReq{Session: sessions["ops"], Username: "ops"})
+				g.Assert(err.(twirp.Error).Code()).Equal(twirp.FailedPrecondition)
+			})
+
+			g.It("Should block a disabled user from logging in", func() {
+				if _, err := login("alice", "correct horse"); err != nil {
+					panic(err)
+				}
+				resp, err := service.DisableUser(ctx, &pb.DisableUserReq{Session: sessions["ops"], Username: "alice"})
+				g.Assert(err).Equal(nil)
+				g.Assert(resp.Revoked).Equal(int32(2))
+				g.Assert(strings.Contains(auditLog.String(), "ops disabled alice, revoking 2 sessions")).IsTrue()
+
+				_, err = service.CurrentUser(ctx, &pb.CurrentUserReq{Session: sessions["alice"]})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid session token"))
+				_, err = login("alice", "correct horse")
+				g.Assert(err).Equal(twirp.NewError(twirp.FailedPrecondition, "account disabled"))
+				_, err = login("alice", "wrong")
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad password"))
+
+				// Only admins and the user see that the account is disabled
+				user, err := service.User(ctx, &pb.UserReq{Username: "alice"})
+				g.Assert(err).Equal(nil)
+				g.Assert(user.User.Disabled).IsFalse()
+				user, err = service.User(ctx, &pb.UserReq{Session: sessions["bob"], Username: "alice"})
+				g.Assert(err).Equal(nil)
+				g.Assert(user.User.Disabled).IsFalse()
+				user, err = service.User(ctx, &pb.UserReq{Session: sessions["ops"], Username: "alice"})
+				g.Assert(err).Equal(nil)
+				g.Assert(user.User.Disabled).IsTrue()
+			})
+
+			g.It("Should delete a user with their sessions and memberships", func() {
+				_, err := service.DeleteUser(ctx, &pb.DeleteUserReq{Session: sessions["ops"], Username: "bob"})
+				g.Assert(err).Equal(nil)
+				g.Assert(strings.Contains(auditLog.String(), "ops deleted bob")).IsTrue()
+
+				_, err = service.User(ctx, &pb.UserReq{Username: "bob"})
+				g.Assert(err).Equal(twirp.NewError(twirp.NotFound, "bob not found"))
+				_, err = service.CurrentUser(ctx, &pb.CurrentUserReq{Session: sessions["bob"]})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid session token"))
+				members, err := service.ListGroupMembers(ctx, &pb.ListGroupMembersReq{Session: sessions["ops"], Group: "eng"})
+				g.Assert(err).Equal(nil)
+				g.Assert(len(members.Members)).Equal(0)
+
+				_, err = service.DeleteUser(ctx, &pb.DeleteUserReq{Session: sessions["ops"], Username: "bob"})
+				g.Assert(err).Equal(twirp.NewError(twirp.NotFound, "bob not found"))
+
+				// The username and email are free again, without bob's groups
+				_, err = service.Register(ctx, &pb.RegisterReq{Username: "bob", Password: "correct horse", Email: "bob@example.com"})
+				g.Assert(err).Equal(nil)
+				groups, err := service.ListUserGroups(ctx, &pb.ListUserGroupsReq{Session: sessions["ops"], Username: "bob"})
+				g.Assert(err).Equal(nil)
+				g.Assert(groups.Groups).Equal([]string{})
+			})
+		})
+
+		g.Describe("Renaming users ("+backend.name+")", func() {
+			var service pb.Users
+			var root, alice *pb.Session
+			notifier := &recordingNotifier{}
+			now := time.Now()
+			ctx := context.Background()
+
+			rename := func(session *pb.Session, username, newUsername string) error {
+				_, err := service.RenameUser(ctx, &pb.RenameUserReq{Session: session, Username: username, NewUsername: newUsername})
+				return err
+			}
+
+			g.Before(func() {
+				s, err := usersservice.New(
+					backend.store("usersservice-rename"),
+					usersservice.WithPasswordHasher(&usersservice.BcryptHasher{Cost: 4}),
+					usersservice.WithNotifier(notifier),
+				)
+				if err != nil {
+					panic(err)
+				}
+				s.AuditLog = nil
+				s.Now = func() time.Time { return now }
+				service = s
+
+				sessions := map[string]*pb.Session{}
+				for _, username := range []string{"ops", "alice", "bob"} {
+					if _, err := service.Register(ctx, &pb.RegisterReq{Username: username, Password: "correct horse", Email: username + "@example.com"}); err != nil {
+						panic(err)
+					}
+					if err := notifier.verify(service, username); err != nil {
+						panic(err)
+					}
+					login, err := service.Login(ctx, &pb.LoginReq{Username: username, Password: "correct horse"})
+					if err != nil {
+						panic(err)
+					}
+					sessions[username] = login.Session
+				}
+				if _, err := s.GrantAdmins([]string{"ops"}); err != nil {
+					panic(err)
+				}
+				root, alice = sessions["ops"], sessions["alice"]
+				if _, err := service.CreateGroup(ctx, &pb.CreateGroupReq{Session: root, Name: "eng"}); err != nil {
+					panic(err)
+				}
+				if _, err := service.AddMember(ctx, &pb.AddMemberReq{Session: root, Group: "eng", Member: &pb.Member{Username: "alice"}}); err != nil {
+					panic(err)
+				}
+			})
+
+			g.It("Should only let admins rename other users", func() {
+				g.Assert(rename(alice, "bob", "robert")).Equal(twirp.NewError(twirp.PermissionDenied, "admin only"))
+				g.Assert(rename(alice, "", "bob")).Equal(twirp.NewError(twirp.AlreadyExists, "Username: bob already exists"))
+				g.Assert(rename(alice, "", "alice").(twirp.Error).Code()).Equal(twirp.InvalidArgument)
+			})
+
+			g.It("Should keep sessions and memberships across a rename", func() {
+				resp, err := service.RenameUser(ctx, &pb.RenameUserReq{Session: alice, NewUsername: "alicia"})
+				g.Assert(err).Equal(nil)
+				g.Assert(resp.User.Username).Equal("alicia")
+				g.Assert(resp.User.Email).Equal("alice@example.com")
+
+				// alice's session still says "alice"
+				current, err := service.CurrentUser(ctx, &pb.CurrentUserReq{Session: alice})
+				g.Assert(err).Equal(nil)
+				g.Assert(current.User.Username).Equal("alicia")
+				groups, err := service.ListUserGroups(ctx, &pb.ListUserGroupsReq{Session: alice})
+				g.Assert(err).Equal(nil)
+				g.Assert(groups.Groups).Equal([]string{"eng"})
+				sessions, err := service.ListSessions(ctx, &pb.ListSessionsReq{Session: alice})
+				g.Assert(err).Equal(nil)
+				g.Assert(len(sessions.Sessions)).Equal(1)
+
+				_, err = service.Login(ctx, &pb.LoginReq{Username: "alicia", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				_, err = service.User(ctx, &pb.UserReq{Username: "alice"})
+				g.Assert(err).Equal(twirp.NewError(twirp.NotFound, "alice not found"))
+				_, err = service.Register(ctx, &pb.RegisterReq{Username: "alice2", Password: "correct horse", Email: "alice@example.com"})
+				g.Assert(err).Equal(twirp.NewError(twirp.AlreadyExists, "Email: alice@example.com already in use"))
+			})
+
+			g.It("Should reserve the old username for its previous owner", func() {
+				_, err := service.Register(ctx, &pb.RegisterReq{Username: "alice", Password: "correct horse"})
+				g.Assert(err).Equal(twirp.NewError(twirp.AlreadyExists, "Username: alice is reserved"))
+				g.Assert(rename(root, "bob", "alice")).Equal(twirp.NewError(twirp.AlreadyExists, "Username: alice is reserved"))
+
+				g.Assert(rename(alice, "", "alice")).Equal(nil)
+				g.Assert(rename(root, "alice", "al")).Equal(nil)
+
+				now = now.Add(usersservice.DefaultRenameReservation)
+				_, err = service.Register(ctx, &pb.RegisterReq{Username: "alice", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+			})
+		})
+
+		g.Describe("Legacy usernames ("+backend.name+")", func() {
+			var service pb.Users
+			var store usersservice.Store
+			var normalize func() (int, []string, error)
+			var wait func()
+			var root *pb.Session
+			notifier := &recordingNotifier{}
+			ctx := context.Background()
+
+			g.Before(func() {
+				s, err := usersservice.New(backend.store("usersservice-legacy-names"), usersservice.WithNotifier(notifier))
+				if err != nil {
+					panic(err)
+				}
+				s.AuditLog = nil
+				service, store, normalize, wait = s, s.Store, s.NormalizeUsernames, s.WaitForNotifications
+
+				// Write users the way the service did before normalizing names
+				digest := sha256.Sum256([]byte("correct horse"))
+				for _, username := range []string{"Eric", "Dana", "dana"} {
+					if err := store.CreateUser(&pb.PrivateUser{Username: username, PasswordSha256: digest[:]}); err != nil {
+						panic(err)
+					}
+				}
+				if _, err := service.Register(ctx, &pb.RegisterReq{Username: "ops", Password: "correct horse"}); err != nil {
+					panic(err)
+				}
+				if _, err := s.GrantAdmins([]string{"ops"}); err != nil {
+					panic(err)
+				}
+				login, err := service.Login(ctx, &pb.LoginReq{Username: "ops", Password: "correct horse"})
+				if err != nil {
+					panic(err)
+				}
+				root = login.Session
+			})
+
+			g.It("Should rename legacy users to their normalized name", func() {
+				renamed, conflicts, err := normalize()
+				g.Assert(err).Equal(nil)
+				g.Assert(renamed).Equal(1)
+				g.Assert(conflicts).Equal([]string{"Dana"})
+
+				_, err = store.GetUser("Eric")
+				g.Assert(err).Equal(usersservice.ErrNotFound)
+				login, err := service.Login(ctx, &pb.LoginReq{Username: "Eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				current, err := service.CurrentUser(ctx, &pb.CurrentUserReq{Session: login.Session})
+				g.Assert(err).Equal(nil)
+				g.Assert(current.User.Username).Equal("eric")
+
+				// The case variant can not be registered by someone else
+				_, err = service.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(twirp.NewError(twirp.AlreadyExists, "Username: eric already exists"))
+
+				renamed, conflicts, err = normalize()
+				g.Assert(err).Equal(nil)
+				g.Assert(renamed).Equal(0)
+				g.Assert(conflicts).Equal([]string{"Dana"})
+			})
+
+			g.It("Should normalize the usernames admins and resets are given", func() {
+				_, err := service.CreateGroup(ctx, &pb.CreateGroupReq{Session: root, Name: "eng"})
+				g.Assert(err).Equal(nil)
+				_, err = service.AddMember(ctx, &pb.AddMemberReq{Session: root, Group: "eng", Member: &pb.Member{Username: "ERIC"}})
+				g.Assert(err).Equal(nil)
+				members, err := service.ListGroupMembers(ctx, &pb.ListGroupMembersReq{Session: root, Group: "eng"})
+				g.Assert(err).Equal(nil)
+				g.Assert(members.Members).Equal([]*pb.Member{{Username: "eric"}})
+
+				_, err = service.RequestPasswordReset(ctx, &pb.RequestPasswordResetReq{UsernameOrEmail: "ERIC"})
+				g.Assert(err).Equal(nil)
+				wait()
+				g.Assert(notifier.sent[len(notifier.sent)-1].Username).Equal("eric")
+
+				_, err = service.DisableUser(ctx, &pb.DisableUserReq{Session: root, Username: "OPS"})
+				g.Assert(err).Equal(twirp.NewError(twirp.FailedPrecondition, "admins can not disable themselves"))
+				_, err = service.DeleteUser(ctx, &pb.DeleteUserReq{Session: root, Username: "OPS"})
+				g.Assert(err).Equal(twirp.NewError(twirp.FailedPrecondition, "admins can not delete themselves"))
+				_, err = service.DisableUser(ctx, &pb.DisableUserReq{Session: root, Username: "ERIC"})
+				g.Assert(err).Equal(nil)
+				_, err = service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(twirp.NewError(twirp.FailedPrecondition, "account disabled"))
+				_, err = service.DeleteUser(ctx, &pb.DeleteUserReq{Session: root, Username: "ERIC"})
+				g.Assert(err).Equal(nil)
+				_, err = service.User(ctx, &pb.UserReq{Username: "eric"})
+				g.Assert(err).Equal(twirp.NewError(twirp.NotFound, "eric not found"))
+			})
+		})
+
+		g.Describe("Two-factor authentication ("+backend.name+")", func() {
+			var service pb.Users
+			var store usersservice.Store
+			var session *pb.Session
+			var secret string
+			var recoveryCodes []string
+			now := time.Unix(1500000000, 0)
+			ctx := context.Background()
+			key := bytes.Repeat([]byte{7}, usersservice.SecretKeySize)
+
+			code := func() string {
+				return totpCode(secret, now)
+			}
+			login := func() *pb.LoginResp {
+				resp, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				return resp
+			}
+			verify := func(challenge, code string) (*pb.VerifySecondFactorResp, error) {
+				return service.VerifySecondFactor(ctx, &pb.VerifySecondFactorReq{Challenge: challenge, Code: code})
+			}
+
+			g.Before(func() {
+				s, err := usersservice.New(
+					backend.store("usersservice-totp"),
+					usersservice.WithPasswordHasher(&usersservice.BcryptHasher{Cost: 4}),
+					usersservice.WithSecretKey(key),
+				)
+				if err != nil {
+					panic(err)
+				}
+				s.AuditLog = nil
+				s.Now = func() time.Time { return now }
+				service, store = s, s.Store
+
+				if _, err := service.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"}); err != nil {
+					panic(err)
+				}
+				session = login().Session
+			})
+
+			g.It("Should need a secret key to enroll", func() {
+				s, err := usersservice.New(usersservice.WithMemoryStore())
+				g.Assert(err).Equal(nil)
+				s.AuditLog = nil
+				_, err = s.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				resp, err := s.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+
+				_, err = s.EnrollTOTP(ctx, &pb.EnrollTOTPReq{Session: resp.Session})
+				g.Assert(err).Equal(twirp.NewError(twirp.FailedPrecondition, "two-factor authentication is not configured"))
+
+				_, err = usersservice.New(usersservice.WithMemoryStore(), usersservice.WithSecretKey([]byte("short")))
+				g.Assert(err == nil).IsFalse()
+			})
+
+			g.It("Should enroll and confirm with a current code", func() {
+				enroll, err := service.EnrollTOTP(ctx, &pb.EnrollTOTPReq{Session: session})
+				g.Assert(err).Equal(nil)
+				secret = enroll.Secret
+				g.Assert(strings.HasPrefix(enroll.OtpauthUrl, "otpauth://totp/twirp-users:eric?")).IsTrue()
+				g.Assert(strings.Contains(enroll.OtpauthUrl, "secret="+secret)).IsTrue()
+
+				// Not on until confirmed
+				g.Assert(login().Session == nil).IsFalse()
+
+				_, err = service.ConfirmTOTP(ctx, &pb.ConfirmTOTPReq{Session: session, Code: "000000"})
+				g.Assert(err).Equal(twirp.InvalidArgumentError("code", "is not a current code"))
+
+				confirm, err := service.ConfirmTOTP(ctx, &pb.ConfirmTOTPReq{Session: session, Code: code()})
+				g.Assert(err).Equal(nil)
+				g.Assert(len(confirm.RecoveryCodes)).Equal(usersservice.RecoveryCodeCount)
+				recoveryCodes = confirm.RecoveryCodes
+
+				_, err = service.EnrollTOTP(ctx, &pb.EnrollTOTPReq{Session: session})
+				g.Assert(err).Equal(twirp.NewError(twirp.FailedPrecondition, "two-factor authentication is already enabled"))
+
+				current, err := service.CurrentUser(ctx, &pb.CurrentUserReq{Session: session})
+				g.Assert(err).Equal(nil)
+				g.Assert(current.User.TotpEnabled).IsTrue()
+			})
+
+			g.It("Should store the secret encrypted and the recovery codes hashed", func() {
+				user, err := store.GetUser("eric")
+				g.Assert(err).Equal(nil)
+				raw, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+				g.Assert(err).Equal(nil)
+				g.Assert(len(user.TotpSecret) > len(raw)).IsTrue()
+				g.Assert(bytes.Contains(user.TotpSecret, raw)).IsFalse()
+				g.Assert(len(user.RecoveryCodeHashes)).Equal(usersservice.RecoveryCodeCount)
+				for _, hash := range user.RecoveryCodeHashes {
+					for _, code := range recoveryCodes {
+						g.Assert(strings.Contains(hash, strings.Replace(code, "-", "", -1))).IsFalse()
+					}
+				}
+			})
+
+			g.It("Should not open a secret copied to another user", func() {
+				_, err := service.Register(ctx, &pb.RegisterReq{Username: "mallory", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				eric, err := store.GetUser("eric")
+				g.Assert(err).Equal(nil)
+				err = store.UpdateUser("mallory", func(user *pb.PrivateUser) error {
+					user.TotpSecret, user.TotpEnabled = eric.TotpSecret, true
+					return nil
+				})
+				g.Assert(err).Equal(nil)
+
+				resp, err := service.Login(ctx, &pb.LoginReq{Username: "mallory", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				verified, err := verify(resp.SecondFactorChallenge, code())
+				g.Assert(err == nil).IsFalse()
+				g.Assert(verified == nil).IsTrue()
+			})
+
+			g.It("Should challenge Login for a code and not accept it twice", func() {
+				resp := login()
+				g.Assert(resp.Session == nil).IsTrue()
+				g.Assert(resp.SecondFactorChallenge == "").IsFalse()
+
+				// The code confirmed with was already used in this time step
+				_, err := verify(resp.SecondFactorChallenge, code())
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad code"))
+
+				now = now.Add(usersservice.TOTPPeriod)
+				verified, err := verify(resp.SecondFactorChallenge, code())
+				g.Assert(err).Equal(nil)
+				_, err = service.CurrentUser(ctx, &pb.CurrentUserReq{Session: verified.Session})
+				g.Assert(err).Equal(nil)
+
+				// Challenges are single use
+				_, err = verify(resp.SecondFactorChallenge, code())
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid challenge"))
+
+				// So is the code, even for a new challenge
+				_, err = verify(login().SecondFactorChallenge, code())
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad code"))
+			})
+
+			g.It("Should accept each recovery code once", func() {
+				verified, err := verify(login().SecondFactorChallenge, strings.ToUpper(recoveryCodes[0]))
+				g.Assert(err).Equal(nil)
+				g.Assert(verified.RecoveryCodesLeft).Equal(int32(usersservice.RecoveryCodeCount - 1))
+
+				_, err = verify(login().SecondFactorChallenge, recoveryCodes[0])
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad code"))
+			})
+
+			g.It("Should drop a challenge after too many bad codes or when it expires", func() {
+				challenge := login().SecondFactorChallenge
+				for i := 0; i < usersservice.MaxSecondFactorFailures; i++ {
+					_, err := verify(challenge, "000000")
+					g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad code"))
+				}
+				now = now.Add(usersservice.TOTPPeriod)
+				_, err := verify(challenge, code())
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid challenge"))
+
+				challenge = login().SecondFactorChallenge
+				now = now.Add(usersservice.DefaultLoginChallengeTTL)
+				_, err = verify(challenge, code())
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "challenge expired"))
+			})
+
+			g.It("Should disable with a code", func() {
+				_, err := service.DisableTOTP(ctx, &pb.DisableTOTPReq{Session: session, Code: "000000"})
+				g.Assert(err).Equal(twirp.InvalidArgumentError("code", "is not a current code or an unused recovery code"))
+
+				now = now.Add(usersservice.TOTPPeriod)
+				_, err = service.DisableTOTP(ctx, &pb.DisableTOTPReq{Session: session, Code: code()})
+				g.Assert(err).Equal(nil)
+				g.Assert(login().Session == nil).IsFalse()
+
+				user, err := store.GetUser("eric")
+				g.Assert(err).Equal(nil)
+				g.Assert(user.TotpSecret == nil).IsTrue()
+				g.Assert(len(user.RecoveryCodeHashes)).Equal(0)
+
+				g.Assert(storedActions(store, "totp.")).Equal([]string{
+					"totp.enroll eric", "totp.enable eric", "totp.disable eric",
+				})
+			})
+		})
+
+		g.Describe("WebAuthn ("+backend.name+")", func() {
+			var service pb.Users
+			var store usersservice.Store
+			var session *pb.Session
+			var authenticator *softAuthenticator
+			now := time.Unix(1500000000, 0)
+			ctx := context.Background()
+			origin := "https://example.com"
+
+			register := func(a *softAuthenticator) (*pb.FinishWebAuthnRegistrationResp, error) {
+				begin, err := service.BeginWebAuthnRegistration(ctx, &pb.BeginWebAuthnRegistrationReq{Session: session})
+				g.Assert(err).Equal(nil)
+				clientData, attestation := a.create(begin.Options)
+				return service.FinishWebAuthnRegistration(ctx, &pb.FinishWebAuthnRegistrationReq{
+					Session: session, ClientDataJson: clientData, AttestationObject: attestation, Name: "laptop",
+				})
+			}
+			login := func(a *softAuthenticator, username string) (*pb.FinishWebAuthnLoginResp, error) {
+				begin, err := service.BeginWebAuthnLogin(ctx, &pb.BeginWebAuthnLoginReq{Username: username})
+				g.Assert(err).Equal(nil)
+				return service.FinishWebAuthnLogin(ctx, a.get(begin.Options))
+			}
+
+			g.Before(func() {
+				s, err := usersservice.New(
+					backend.store("usersservice-webauthn"),
+					usersservice.WithPasswordHasher(&usersservice.BcryptHasher{Cost: 4}),
+					usersservice.WithWebAuthn(&usersservice.WebAuthn{RPID: "example.com", Origins: []string{origin}}),
+				)
+				if err != nil {
+					panic(err)
+				}
+				s.AuditLog = nil
+				s.Now = func() time.Time { return now }
+				service, store = s, s.Store
+
+				if _, err := service.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"}); err != nil {
+					panic(err)
+				}
+				resp, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				if err != nil {
+					panic(err)
+				}
+				session = resp.Session
+				authenticator = newSoftAuthenticator("example.com", origin)
+			})
+
+			g.It("Should need to be configured", func() {
+				s, err := usersservice.New(usersservice.WithMemoryStore())
+				g.Assert(err).Equal(nil)
+				_, err = s.BeginWebAuthnLogin(ctx, &pb.BeginWebAuthnLoginReq{})
+				g.Assert(err).Equal(twirp.NewError(twirp.FailedPrecondition, "WebAuthn is not configured"))
+			})
+
+			g.It("Should register a credential", func() {
+				resp, err := register(authenticator)
+				g.Assert(err).Equal(nil)
+				g.Assert(resp.Credential.Name).Equal("laptop")
+				g.Assert(resp.Credential.CreatedAt).Equal(now.Unix())
+				g.Assert(storedActions(store, "webauthn.")).Equal([]string{"webauthn.register eric"})
+
+				current, err := service.CurrentUser(ctx, &pb.CurrentUserReq{Session: session})
+				g.Assert(err).Equal(nil)
+				g.Assert(len(current.User.WebauthnCredentials)).Equal(1)
+				g.Assert(current.User.WebauthnCredentials[0].Id).Equal(resp.Credential.Id)
+
+				_, err = register(authenticator)
+				g.Assert(err).Equal(twirp.NewError(twirp.AlreadyExists, "credential already registered"))
+			})
+
+			g.It("Should not register a credential another user has", func() {
+				_, err := service.Register(ctx, &pb.RegisterReq{Username: "mallory", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				mallory, err := service.Login(ctx, &pb.LoginReq{Username: "mallory", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+
+				// A copy, create remembers the user handle
+				clone := *authenticator
+				eric := session
+				session = mallory.Session
+				defer func() { session = eric }()
+				_, err = register(&clone)
+				g.Assert(err).Equal(twirp.NewError(twirp.AlreadyExists, "credential already registered"))
+
+				current, err := service.CurrentUser(ctx, &pb.CurrentUserReq{Session: mallory.Session})
+				g.Assert(err).Equal(nil)
+				g.Assert(len(current.User.WebauthnCredentials)).Equal(0)
+			})
+
+			g.It("Should use each challenge once", func() {
+				begin, err := service.BeginWebAuthnRegistration(ctx, &pb.BeginWebAuthnRegistrationReq{Session: session})
+				g.Assert(err).Equal(nil)
+				clientData, attestation := newSoftAuthenticator("example.com", origin).create(begin.Options)
+				req := &pb.FinishWebAuthnRegistrationReq{Session: session, ClientDataJson: clientData, AttestationObject: attestation}
+				_, err = service.FinishWebAuthnRegistration(ctx, req)
+				g.Assert(err).Equal(nil)
+				_, err = service.FinishWebAuthnRegistration(ctx, req)
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid challenge"))
+
+				// A registration challenge can not be used to log in
+				begin, err = service.BeginWebAuthnRegistration(ctx, &pb.BeginWebAuthnRegistrationReq{Session: session})
+				g.Assert(err).Equal(nil)
+				_, err = service.FinishWebAuthnLogin(ctx, authenticator.get(begin.Options))
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid challenge"))
+			})
+
+			g.It("Should log in with a username or a discoverable credential", func() {
+				resp, err := login(authenticator, "Eric")
+				g.Assert(err).Equal(nil)
+				current, err := service.CurrentUser(ctx, &pb.CurrentUserReq{Session: resp.Session})
+				g.Assert(err).Equal(nil)
+				g.Assert(current.User.Username).Equal("eric")
+				g.Assert(current.User.WebauthnCredentials[0].LastUsedAt).Equal(now.Unix())
+
+				resp, err = login(authenticator, "")
+				g.Assert(err).Equal(nil)
+				g.Assert(resp.Session.Username).Equal("eric")
+			})
+
+			g.It("Should not tell which usernames have credentials", func() {
+				unknown, err := service.BeginWebAuthnLogin(ctx, &pb.BeginWebAuthnLoginReq{Username: "nobody"})
+				g.Assert(err).Equal(nil)
+				anonymous, err := service.BeginWebAuthnLogin(ctx, &pb.BeginWebAuthnLoginReq{})
+				g.Assert(err).Equal(nil)
+				challenge := regexp.MustCompile(`"challenge":"[^"]*"`)
+				g.Assert(challenge.ReplaceAllString(unknown.Options, "")).Equal(challenge.ReplaceAllString(anonymous.Options, ""))
+			})
+
+			g.It("Should reject a cloned authenticator", func() {
+				clone := *authenticator
+				_, err := login(authenticator, "eric")
+				g.Assert(err).Equal(nil)
+				_, err = login(&clone, "eric")
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "credential sign count did not increase"))
+			})
+
+			g.It("Should reject assertions that do not check out", func() {
+				begin, err := service.BeginWebAuthnLogin(ctx, &pb.BeginWebAuthnLoginReq{Username: "eric"})
+				g.Assert(err).Equal(nil)
+				req := authenticator.get(begin.Options)
+				req.Signature[len(req.Signature)-1] ^= 1
+				_, err = service.FinishWebAuthnLogin(ctx, req)
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad signature"))
+
+				phished := *authenticator
+				phished.origin = "https://example.com.evil.test"
+				_, err = login(&phished, "eric")
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "origin not allowed"))
+
+				other := *authenticator
+				other.rpID = "evil.test"
+				_, err = login(&other, "eric")
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "credential is for another relying party"))
+
+				unverified := *authenticator
+				unverified.skipVerification = true
+				_, err = login(&unverified, "eric")
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "user was not verified by the authenticator"))
+
+				stranger := newSoftAuthenticator("example.com", origin)
+				stranger.userHandle = authenticator.userHandle
+				_, err = login(stranger, "")
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "unknown credential"))
+			})
+
+			g.It("Should expire challenges", func() {
+				begin, err := service.BeginWebAuthnLogin(ctx, &pb.BeginWebAuthnLoginReq{Username: "eric"})
+				g.Assert(err).Equal(nil)
+				now = now.Add(usersservice.DefaultLoginChallengeTTL)
+				_, err = service.FinishWebAuthnLogin(ctx, authenticator.get(begin.Options))
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "challenge expired"))
+			})
+		})
+
+		g.Describe("Session renewal ("+backend.name+")", func() {
+			ctx := context.Background()
+
+			g.It("Should not bring back a session revoked while it was renewed", func() {
+				s, err := usersservice.New(backend.store("usersservice-renewal"))
+				g.Assert(err).Equal(nil)
+				defer s.Close()
+				s.AuditLog = nil
+				store := &interleavingStore{Store: s.Store}
+				s.Store = store
+
+				_, err = s.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				login, err := s.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+
+				// Logout lands between CurrentUser reading the session and renewing it
+				store.afterGetSession = func() {
+					store.afterGetSession = nil
+					_, err := s.Logout(ctx, &pb.LogoutReq{Session: login.Session})
+					g.Assert(err).Equal(nil)
+				}
+				_, err = s.CurrentUser(ctx, &pb.CurrentUserReq{Session: login.Session})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid session token"))
+
+				_, err = s.CurrentUser(ctx, &pb.CurrentUserReq{Session: login.Session})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid session token"))
+			})
+		})
+
+		g.Describe("Audit log ("+backend.name+")", func() {
+			var service pb.Users
+			var export func(w io.Writer, filter *pb.AuditFilter) (int, error)
+			var root *pb.Session
+			now := time.Unix(1500000000, 0)
+			ctx := context.Background()
+
+			list := func(filter *pb.AuditFilter) []*pb.AuditEvent {
+				resp, err := service.ListAuditEvents(ctx, &pb.ListAuditEventsReq{Session: root, Filter: filter, PageSize: 500})
+				g.Assert(err).Equal(nil)
+				g.Assert(resp.NextPageToken).Equal("")
+				return resp.Events
+			}
+			actions := func(events []*pb.AuditEvent) []string {
+				names := []string{}
+				for _, event := range events {
+					names = append(names, event.Action+" "+event.Outcome)
+				}
+				return names
+			}
+
+			g.Before(func() {
+				s, err := usersservice.New(backend.store("usersservice-audit"))
+				if err != nil {
+					panic(err)
+				}
+				s.AuditLog = nil
+				s.Now = func() time.Time { return now }
+				service, export = s, s.ExportAuditEvents
+
+				for _, username := range []string{"ops", "eric"} {
+					if _, err := service.Register(ctx, &pb.RegisterReq{Username: username, Password: "correct horse"}); err != nil {
+						panic(err)
+					}
+				}
+				if _, err := s.GrantAdmins([]string{"ops"}); err != nil {
+					panic(err)
+				}
+				login, err := service.Login(ctx, &pb.LoginReq{Username: "ops", Password: "correct horse"})
+				if err != nil {
+					panic(err)
+				}
+				root = login.Session
+			})
+
+			g.It("Should record logins with the client ip and outcome", func() {
+				server := httptest.NewServer(usersservice.WithClientInfo(pb.NewUsersServer(service, nil)))
+				defer server.Close()
+				client := pb.NewUsersJSONClient(server.URL, http.DefaultClient)
+
+				_, err := client.Login(ctx, &pb.LoginReq{Username: "eric", Password: "wrong"})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad password"))
+				_, err = client.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				_, err = client.Login(ctx, &pb.LoginReq{Username: "NoBody", Password: "correct horse"})
+				g.Assert(err != nil).IsTrue()
+
+				events := list(&pb.AuditFilter{Action: usersservice.AuditLogin, Subject: "eric"})
+				g.Assert(actions(events)).Equal([]string{"login failure", "login success"})
+				g.Assert(events[0].Actor).Equal("")
+				g.Assert(events[0].Detail).Equal("eric login failed: bad password")
+				g.Assert(events[1].Actor).Equal("eric")
+				g.Assert(events[1].ClientIp).Equal("127.0.0.1")
+				g.Assert(events[1].Time).Equal(now.Unix())
+				g.Assert(events[0].Id < events[1].Id).IsTrue()
+
+				// Guessed usernames are not stored, only counted
+				g.Assert(len(list(&pb.AuditFilter{Subject: "nobody"}))).Equal(0)
+				_, err = client.Login(ctx, &pb.LoginReq{Username: "someone", Password: "correct horse"})
+				g.Assert(err != nil).IsTrue()
+				now = now.Add(time.Minute)
+				_, err = client.Login(ctx, &pb.LoginReq{Username: "anyone", Password: "correct horse"})
+				g.Assert(err != nil).IsTrue()
+				events = list(&pb.AuditFilter{Subject: usersservice.AuditAnySubject})
+				g.Assert(actions(events)).Equal([]string{"login failure", "login failure"})
+				g.Assert(events[0].Detail).Equal("1 failed login(s): unknown user")
+				g.Assert(events[1].Detail).Equal("2 failed login(s): unknown user")
+			})
+
+			g.It("Should record group, membership and rename changes", func() {
+				_, err := service.CreateGroup(ctx, &pb.CreateGroupReq{Session: root, Name: "staff"})
+				g.Assert(err).Equal(nil)
+				_, err = service.AddMember(ctx, &pb.AddMemberReq{Session: root, Group: "staff", Member: &pb.Member{Username: "eric"}})
+				g.Assert(err).Equal(nil)
+				_, err = service.RemoveMember(ctx, &pb.RemoveMemberReq{Session: root, Group: "staff", Member: &pb.Member{Username: "eric"}})
+				g.Assert(err).Equal(nil)
+				events := list(&pb.AuditFilter{Actor: "ops", Subject: "staff"})
+				g.Assert(actions(events)).Equal([]string{"group.create success", "group.add_member success", "group.remove_member success"})
+				g.Assert(events[1].Detail).Equal("ops added user:eric to group staff")
+
+				_, err = service.Register(ctx, &pb.RegisterReq{Username: "bob", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				_, err = service.RenameUser(ctx, &pb.RenameUserReq{Session: root, Username: "bob", NewUsername: "robert"})
+				g.Assert(err).Equal(nil)
+				events = list(&pb.AuditFilter{Action: usersservice.AuditRenameUser})
+				g.Assert(actions(events)).Equal([]string{"user.rename success"})
+				g.Assert(events[0].Actor).Equal("ops")
+				g.Assert(events[0].Subject).Equal("robert")
+				g.Assert(events[0].Detail).Equal("ops renamed bob to robert")
+			})
+
+			g.It("Should record registrations, password and role changes and revocations", func() {
+				_, err := service.Register(ctx, &pb.RegisterReq{Username: "alice", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				_, err = service.Register(ctx, &pb.RegisterReq{Username: "alice", Password: "correct horse"})
+				g.Assert(err != nil).IsTrue()
+				login, err := service.Login(ctx, &pb.LoginReq{Username: "alice", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				_, err = service.ChangePassword(ctx, &pb.ChangePasswordReq{Session: login.Session, OldPassword: "correct horse", NewPassword: "battery staple"})
+				g.Assert(err).Equal(nil)
+				_, err = service.GrantRole(ctx, &pb.GrantRoleReq{Session: root, Username: "alice", Role: "admin"})
+				g.Assert(err).Equal(nil)
+				_, err = service.RevokeRole(ctx, &pb.RevokeRoleReq{Session: root, Username: "alice", Role: "admin"})
+				g.Assert(err).Equal(nil)
+				_, err = service.RevokeSession(ctx, &pb.RevokeSessionReq{Session: root, Token: login.Session.Token})
+				g.Assert(err).Equal(nil)
+
+				events := list(&pb.AuditFilter{Subject: "alice"})
+				g.Assert(actions(events)).Equal([]string{
+					"register success",
+					"register failure",
+					"login success",
+					"password.change success",
+					"role.grant success",
+					"role.revoke success",
+					"session.revoke success",
+				})
+				g.Assert(events[4].Actor).Equal("ops")
+				g.Assert(events[4].Detail).Equal("ops granted role admin to alice")
+
+				// Only ops acted on alice
+				g.Assert(len(list(&pb.AuditFilter{Subject: "alice", Actor: "ops"}))).Equal(3)
+			})
+
+			g.It("Should only let admins list audit events", func() {
+				login, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				_, err = service.ListAuditEvents(ctx, &pb.ListAuditEventsReq{Session: login.Session})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "admin only"))
+
+				events := list(&pb.AuditFilter{Action: usersservice.AuditAdminAccess})
+				g.Assert(actions(events)).Equal([]string{"admin.access failure"})
+				g.Assert(events[0].Actor).Equal("eric")
+			})
+
+			g.It("Should record sessions presented as another user", func() {
+				login, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				forged := &pb.Session{Token: login.Session.Token, Username: "ops"}
+				_, err = service.ListAuditEvents(ctx, &pb.ListAuditEventsReq{Session: forged})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid session"))
+
+				events := list(&pb.AuditFilter{Action: usersservice.AuditForgedSession})
+				g.Assert(actions(events)).Equal([]string{"session.forged failure"})
+				g.Assert(events[0].Subject).Equal("eric")
+				g.Assert(events[0].Detail).Equal(`session username mismatch: token for "eric" presented as "ops"`)
+			})
+
+			g.It("Should page through events and filter by time", func() {
+				_, err := service.Register(ctx, &pb.RegisterReq{Username: "pager", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				start := now
+				for i := 0; i < 5; i++ {
+					now = now.Add(time.Hour)
+					service.Login(ctx, &pb.LoginReq{Username: "pager", Password: "wrong"})
+				}
+
+				filter := &pb.AuditFilter{Action: usersservice.AuditLogin, Subject: "pager"}
+				var pages [][]*pb.AuditEvent
+				token := ""
+				for {
+					resp, err := service.ListAuditEvents(ctx, &pb.ListAuditEventsReq{Session: root, Filter: filter, PageSize: 2, PageToken: token})
+					g.Assert(err).Equal(nil)
+					pages = append(pages, resp.Events)
+					if resp.NextPageToken == "" {
+						break
+					}
+					token = resp.NextPageToken
+				}
+				g.Assert(len(pages)).Equal(3)
+				g.Assert(len(pages[2])).Equal(1)
+				g.Assert(pages[2][0].Time).Equal(start.Add(5 * time.Hour).Unix())
+
+				_, err = service.ListAuditEvents(ctx, &pb.ListAuditEventsReq{Session: root, Filter: &pb.AuditFilter{Subject: "eric"}, PageToken: token})
+				g.Assert(err).Equal(twirp.InvalidArgumentError("page_token", "is not from a ListAuditEvents call with this filter"))
+
+				// Since is inclusive, until is not
+				events := list(&pb.AuditFilter{Action: usersservice.AuditLogin, Subject: "pager", Since: start.Add(2 * time.Hour).Unix(), Until: start.Add(4 * time.Hour).Unix()})
+				g.Assert(len(events)).Equal(2)
+				g.Assert(events[0].Time).Equal(start.Add(2 * time.Hour).Unix())
+				g.Assert(events[1].Time).Equal(start.Add(3 * time.Hour).Unix())
+			})
+
+			g.It("Should export events as JSON Lines", func() {
+				var out bytes.Buffer
+				count, err := export(&out, &pb.AuditFilter{Action: usersservice.AuditRegister, Outcome: usersservice.AuditSuccess})
+				g.Assert(err).Equal(nil)
+				g.Assert(count >= 2).IsTrue()
+
+				lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+				g.Assert(len(lines)).Equal(count)
+				var first map[string]string
+				g.Assert(json.Unmarshal([]byte(lines[0]), &first)).Equal(nil)
+				g.Assert(first["action"]).Equal("register")
+				g.Assert(first["subject"]).Equal("ops")
+				g.Assert(first["actor"]).Equal("ops")
+				g.Assert(first["outcome"]).Equal("success")
+				g.Assert(first["time"]).Equal("2017-07-14T02:40:00Z")
+				g.Assert(first["detail"]).Equal("ops registered")
+			})
+		})
+
+		g.Describe("Concurrent registration ("+backend.name+")", func() {
+			const racers = 50
+			var service pb.Users
+
+			g.Before(func() {
+				hasher := &barrierHasher{PasswordHasher: &usersservice.BcryptHasher{Cost: 4}}
+				hasher.ready.Add(racers)
+				s, err := usersservice.New(backend.store("usersservice-race"), usersservice.WithPasswordHasher(hasher))
+				if err != nil {
+					panic(err)
+				}
+				// The test tries every racer's password, which is a lot of failures
+				s.MaxLoginBackoff = 0
+				service = s
+			})
+
+			g.It("Should let exactly one Register win for a username", func() {
+				errs := make(chan error, racers)
+				var wg sync.WaitGroup
+				for i := 0; i < racers; i++ {
+					wg.Add(1)
+					go func(i int) {
+						defer wg.Done()
+						_, err := service.Register(context.Background(), &pb.RegisterReq{
+							Username: "racer",
+							Password: fmt.Sprintf("password-%d", i),
+						})
+						errs <- err
+					}(i)
+				}
+				wg.Wait()
+				close(errs)
+
+				won := 0
+				for err := range errs {
+					if err == nil {
+						won++
+					} else {
+						g.Assert(err.(twirp.Error).Code()).Equal(twirp.AlreadyExists)
+					}
+				}
+				g.Assert(won).Equal(1)
+
+				// The winner's password was not overwritten by a loser
+				logins := 0
+				for i := 0; i < racers; i++ {
+					_, err := service.Login(context.Background(), &pb.LoginReq{
+						Username: "racer",
+						Password: fmt.Sprintf("password-%d", i),
+					})
+					if err == nil {
+						logins++
+					}
+				}
+				g.Assert(logins).Equal(1)
+			})
+		})
+	}
+
+	g.Describe("Legacy password migration", func() {
+		var service pb.Users
+		var store usersservice.Store
+		var report func() (*usersservice.PasswordReport, error)
+		testDbPath := "/tmp/usersservice-legacy.db"
+
+		g.Before(func() {
+			if err := os.RemoveAll(testDbPath); err != nil {
+				panic(err)
+			}
+
+			s, err := usersservice.New(usersservice.WithLevelDB(testDbPath))
+			if err != nil {
+				panic(err)
+			}
+			service, store, report = s, s.Store, s.PasswordReport
+
+			// Write a user the way the service used to
+			digest := sha256.Sum256([]byte("correct horse"))
+			if err := store.CreateUser(&pb.PrivateUser{Username: "legacy", PasswordSha256: digest[:]}); err != nil {
+				panic(err)
+			}
+		})
+
+		g.It("Should not upgrade on a bad password", func() {
+			_, err := service.Login(context.Background(), &pb.LoginReq{Username: "legacy", Password: "wrong"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad password"))
+
+			r, err := report()
+			g.Assert(err).Equal(nil)
+			g.Assert(r.Legacy).Equal(1)
+		})
+
+		g.It("Should rehash a legacy record on login", func() {
+			_, err := service.Login(context.Background(), &pb.LoginReq{Username: "legacy", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+
+			user, err := store.GetUser("legacy")
+			g.Assert(err).Equal(nil)
+			g.Assert(len(user.PasswordSha256)).Equal(0)
+			g.Assert(user.PasswordHash == "").IsFalse()
+
+			r, err := report()
+			g.Assert(err).Equal(nil)
+			g.Assert(r.Total).Equal(1)
+			g.Assert(r.Legacy).Equal(0)
+			g.Assert(r.Schemes["argon2id"]).Equal(1)
+
+			// The new hash still accepts the password
+			_, err = service.Login(context.Background(), &pb.LoginReq{Username: "legacy", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+		})
+	})
+
+	g.Describe("Session expiry", func() {
+		var service pb.Users
+		var store usersservice.Store
+		var reap func() (int, error)
+		var now time.Time
+		testDbPath := "/tmp/usersservice-sessions.db"
+
+		login := func() *pb.Session {
+			resp, err := service.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			return resp.Session
+		}
+		currentUser := func(session *pb.Session) error {
+			_, err := service.CurrentUser(context.Background(), &pb.CurrentUserReq{Session: session})
+			return err
+		}
+
+		g.Before(func() {
+			if err := os.RemoveAll(testDbPath); err != nil {
+				panic(err)
+			}
+
+			s, err := usersservice.New(usersservice.WithLevelDB(testDbPath))
+			if err != nil {
+				panic(err)
+			}
+			now = time.Unix(1500000000, 0)
+			s.Now = func() time.Time { return now }
+			s.SessionIdleTimeout = time.Hour
+			s.SessionLifetime = 3 * time.Hour
+			service, store, reap = s, s.Store, s.ReapSessions
+
+			if _, err := service.Register(context.Background(), &pb.RegisterReq{Username: "eric", Password: "correct horse"}); err != nil {
+				panic(err)
+			}
+		})
+
+		g.It("Should stamp new sessions", func() {
+			session := login()
+			g.Assert(session.CreatedAt).Equal(now.Unix())
+			g.Assert(session.LastSeenAt).Equal(now.Unix())
+			g.Assert(session.ExpiresAt).Equal(now.Add(time.Hour).Unix())
+		})
+
+		g.It("Should renew the idle timeout on CurrentUser", func() {
+			session := login()
+			now = now.Add(50 * time.Minute)
+			g.Assert(currentUser(session)).Equal(nil)
+			now = now.Add(50 * time.Minute)
+			g.Assert(currentUser(session)).Equal(nil)
+		})
+
+		g.It("Should expire idle sessions", func() {
+			session := login()
+			now = now.Add(61 * time.Minute)
+			g.Assert(currentUser(session)).Equal(twirp.NewError(twirp.PermissionDenied, "session expired"))
+			g.Assert(currentUser(session)).Equal(twirp.NewError(twirp.PermissionDenied, "invalid session token"))
+		})
+
+		g.It("Should expire sessions after their absolute lifetime", func() {
+			session := login()
+			for i := 0; i < 3; i++ {
+				now = now.Add(50 * time.Minute)
+				g.Assert(currentUser(session)).Equal(nil)
+			}
+			now = now.Add(50 * time.Minute)
+			g.Assert(currentUser(session)).Equal(twirp.NewError(twirp.PermissionDenied, "session expired"))
+		})
+
+		g.It("Should reap expired sessions", func() {
+			expired := login()
+			now = now.Add(30 * time.Minute)
+			live := login()
+			now = now.Add(31 * time.Minute)
+
+			n, err := reap()
+			g.Assert(err).Equal(nil)
+			g.Assert(n > 0).IsTrue()
+
+			_, err = store.GetSession(expired.Token)
+			g.Assert(err).Equal(usersservice.ErrNotFound)
+			g.Assert(currentUser(live)).Equal(nil)
+		})
+	})
+
+	g.Describe("Session revocation", func() {
+		var service pb.Users
+		testDbPath := "/tmp/usersservice-revocation.db"
+
+		login := func(username string) *pb.Session {
+			resp, err := service.Login(context.Background(), &pb.LoginReq{Username: username, Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			return resp.Session
+		}
+		currentUser := func(session *pb.Session) error {
+			_, err := service.CurrentUser(context.Background(), &pb.CurrentUserReq{Session: session})
+			return err
+		}
+		invalid := twirp.NewError(twirp.PermissionDenied, "invalid session token")
+
+		g.Before(func() {
+			if err := os.RemoveAll(testDbPath); err != nil {
+				panic(err)
+			}
+
+			s, err := usersservice.New(usersservice.WithLevelDB(testDbPath), usersservice.WithPolicy(testPolicy))
+			if err != nil {
+				panic(err)
+			}
+			s.AuditLog = nil
+			service = s
+
+			for _, username := range []string{"eric", "eric/x", "admin"} {
+				if _, err := service.Register(context.Background(), &pb.RegisterReq{Username: username, Password: "correct horse"}); err != nil {
+					panic(err)
+				}
+			}
+			if _, err := s.GrantAdmins([]string{"admin"}); err != nil {
+				panic(err)
+			}
+		})
+
+		g.It("Should end a session on Logout", func() {
+			session := login("eric")
+			other := login("eric")
+
+			_, err := service.Logout(context.Background(), &pb.LogoutReq{Session: session})
+			g.Assert(err).Equal(nil)
+			g.Assert(currentUser(session)).Equal(invalid)
+			g.Assert(currentUser(other)).Equal(nil)
+		})
+
+		g.It("Should end every session of the user on LogoutAll", func() {
+			first := login("eric")
+			second := login("eric")
+			bystander := login("eric/x")
+
+			resp, err := service.LogoutAll(context.Background(), &pb.LogoutAllReq{Session: first})
+			g.Assert(err).Equal(nil)
+			g.Assert(resp.Revoked >= 2).IsTrue()
+			g.Assert(currentUser(first)).Equal(invalid)
+			g.Assert(currentUser(second)).Equal(invalid)
+			g.Assert(currentUser(bystander)).Equal(nil)
+		})
+
+		g.It("Should only let admins revoke sessions", func() {
+			session := login("eric")
+			attacker := login("eric/x")
+
+			_, err := service.RevokeSession(context.Background(), &pb.RevokeSessionReq{Session: attacker, Token: session.Token})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "admin only"))
+			g.Assert(currentUser(session)).Equal(nil)
+
+			admin := login("admin")
+			_, err = service.RevokeSession(context.Background(), &pb.RevokeSessionReq{Session: admin, Token: session.Token})
+			g.Assert(err).Equal(nil)
+			g.Assert(currentUser(session)).Equal(invalid)
+
+			_, err = service.RevokeSession(context.Background(), &pb.RevokeSessionReq{Session: admin, Token: session.Token})
+			g.Assert(err).Equal(twirp.NewError(twirp.NotFound, "session not found"))
+		})
+
+		g.It("Should list the user's sessions with client details", func() {
+			server := httptest.NewServer(usersservice.WithClientInfo(pb.NewUsersServer(service, nil)))
+			defer server.Close()
+			client := pb.NewUsersJSONClient(server.URL, http.DefaultClient)
+
+			// Start from a clean slate
+			_, err := service.LogoutAll(context.Background(), &pb.LogoutAllReq{Session: login("eric")})
+			g.Assert(err).Equal(nil)
+
+			remote, err := client.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			local := login("eric")
+
+			resp, err := client.ListSessions(context.Background(), &pb.ListSessionsReq{Session: remote.Session})
+			g.Assert(err).Equal(nil)
+			g.Assert(len(resp.Sessions)).Equal(2)
+
+			var info *pb.SessionInfo
+			for _, s := range resp.Sessions {
+				g.Assert(s.Id == remote.Session.Token || s.Id == local.Token).IsFalse()
+				if s.Current {
+					info = s
+				}
+			}
+			g.Assert(info.ClientIp).Equal("127.0.0.1")
+			g.Assert(info.UserAgent).Equal("Go-http-client/1.1")
+		})
+
+		g.It("Should let users revoke their own sessions by id", func() {
+			_, err := service.LogoutAll(context.Background(), &pb.LogoutAllReq{Session: login("eric")})
+			g.Assert(err).Equal(nil)
+			session := login("eric")
+			other := login("eric")
+			attacker := login("eric/x")
+
+			resp, err := service.ListSessions(context.Background(), &pb.ListSessionsReq{Session: session})
+			g.Assert(err).Equal(nil)
+			g.Assert(len(resp.Sessions)).Equal(2)
+			var id string
+			for _, s := range resp.Sessions {
+				if !s.Current {
+					id = s.Id
+				}
+			}
+
+			// Ids only resolve within the caller's own sessions
+			_, err = service.RevokeSession(context.Background(), &pb.RevokeSessionReq{Session: attacker, SessionId: id})
+			g.Assert(err).Equal(twirp.NewError(twirp.NotFound, "session not found"))
+
+			_, err = service.RevokeSession(context.Background(), &pb.RevokeSessionReq{Session: session, SessionId: id})
+			g.Assert(err).Equal(nil)
+			g.Assert(currentUser(other)).Equal(invalid)
+			g.Assert(currentUser(session)).Equal(nil)
+		})
+	})
+
+	g.Describe("Login throttling", func() {
+		var now time.Time
+
+		// newService starts a fresh throttle, maxBackoff 0 turns off the per
+		// username backoff and burst sizes the per ip token bucket
+		newService := func(maxBackoff time.Duration, burst int) (pb.Users, *twirp.ServerHooks) {
+			s, err := usersservice.New(usersservice.WithMemoryStore())
+			if err != nil {
+				panic(err)
+			}
+			s.AuditLog = nil
+			now = time.Unix(1500000000, 0)
+			s.Now = func() time.Time { return now }
+			s.MaxLoginBackoff = maxBackoff
+			s.IPLoginBurst = burst
+			if _, err := s.Register(context.Background(), &pb.RegisterReq{Username: "eric", Password: "correct horse"}); err != nil {
+				panic(err)
+			}
+			return s, s.ServerHooks()
+		}
+		retryAfter := func(err error) string {
+			twerr, ok := err.(twirp.Error)
+			if !ok || twerr.Code() != twirp.ResourceExhausted {
+				return ""
+			}
+			return twerr.Meta("retry_after")
+		}
+		// post logs in as eric with a bad password over http, as the
+		// client at X-Forwarded-For forwarded if that is set
+		post := func(url, forwarded string) *http.Response {
+			req, err := http.NewRequest("POST", url+pb.UsersPathPrefix+"Login", strings.NewReader(`{"username":"eric","password":"wrong"}`))
+			if err != nil {
+				panic(err)
+			}
+			req.Header.Set("Content-Type", "application/json")
+			if forwarded != "" {
+				req.Header.Set("X-Forwarded-For", forwarded)
+			}
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				panic(err)
+			}
+			ioutil.ReadAll(resp.Body)
+			resp.Body.Close()
+			return resp
+		}
+
+		g.It("Should back off exponentially after repeated bad passwords", func() {
+			service, _ := newService(usersservice.DefaultMaxLoginBackoff, 0)
+			login := func(password string) error {
+				_, err := service.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: password})
+				return err
+			}
+
+			badPassword := twirp.NewError(twirp.PermissionDenied, "bad password")
+			for i := 0; i < usersservice.DefaultLoginFreeAttempts; i++ {
+				g.Assert(login("wrong")).Equal(badPassword)
+			}
+			g.Assert(login("wrong")).Equal(badPassword)
+
+			// Even the right password waits out the lockout
+			err := login("correct horse")
+			g.Assert(retryAfter(err)).Equal("1")
+			g.Assert(err.Error()).Equal("twirp error resource_exhausted: too many login attempts")
+
+			now = now.Add(time.Second)
+			g.Assert(login("wrong")).Equal(badPassword)
+			g.Assert(retryAfter(login("correct horse"))).Equal("2")
+
+			now = now.Add(2 * time.Second)
+			g.Assert(login("correct horse")).Equal(nil)
+
+			// A good login starts the count over
+			for i := 0; i < usersservice.DefaultLoginFreeAttempts; i++ {
+				g.Assert(login("wrong")).Equal(badPassword)
+			}
+			g.Assert(login("correct horse")).Equal(nil)
+		})
+
+		g.It("Should cap the backoff and forget old failures", func() {
+			service, _ := newService(time.Minute, 0)
+			for i := 0; i < 20; i++ {
+				service.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "wrong"})
+				now = now.Add(time.Hour)
+			}
+			now = now.Add(-time.Hour)
+			_, err := service.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(retryAfter(err)).Equal("60")
+
+			now = now.Add(usersservice.DefaultLoginFailureWindow)
+			_, err = service.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+		})
+
+		g.It("Should throttle unknown usernames the same way", func() {
+			service, _ := newService(usersservice.DefaultMaxLoginBackoff, 0)
+			for i := 0; i <= usersservice.DefaultLoginFreeAttempts; i++ {
+				_, err := service.Login(context.Background(), &pb.LoginReq{Username: "nobody", Password: "wrong"})
+				g.Assert(retryAfter(err)).Equal("")
+			}
+			_, err := service.Login(context.Background(), &pb.LoginReq{Username: "Nobody", Password: "wrong"})
+			g.Assert(retryAfter(err)).Equal("1")
+		})
+
+		g.It("Should count concurrent guesses before checking them", func() {
+			service, _ := newService(usersservice.DefaultMaxLoginBackoff, 0)
+			const guesses = 20
+			results := make(chan error, guesses)
+			var wg sync.WaitGroup
+			for i := 0; i < guesses; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					_, err := service.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "wrong"})
+					results <- err
+				}()
+			}
+			wg.Wait()
+			close(results)
+
+			checked := 0
+			for err := range results {
+				if retryAfter(err) == "" {
+					g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad password"))
+					checked++
+				}
+			}
+			g.Assert(checked).Equal(usersservice.DefaultLoginFreeAttempts + 1)
+		})
+
+		g.It("Should keep a locked username through a flood of others", func() {
+			s, err := usersservice.New(usersservice.WithMemoryStore())
+			if err != nil {
+				panic(err)
+			}
+			s.AuditLog = nil
+			now = time.Unix(1500000000, 0)
+			s.Now = func() time.Time { return now }
+			s.MaxLoginFailureUsernames = 10
+			ctx := context.Background()
+			_, err = s.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+
+			for i := 0; i <= usersservice.DefaultLoginFreeAttempts; i++ {
+				s.Login(ctx, &pb.LoginReq{Username: "eric", Password: "wrong"})
+			}
+			for i := 0; i < 100; i++ {
+				_, err = s.Login(ctx, &pb.LoginReq{Username: fmt.Sprintf("spray%d", i), Password: "wrong"})
+				g.Assert(retryAfter(err)).Equal("")
+			}
+			_, err = s.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(retryAfter(err)).Equal("1")
+
+			now = now.Add(time.Second)
+			_, err = s.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+		})
+
+		g.It("Should count bad second factor codes and reset only after the whole login", func() {
+			s, err := usersservice.New(usersservice.WithMemoryStore(), usersservice.WithSecretKey(bytes.Repeat([]byte{7}, usersservice.SecretKeySize)))
+			if err != nil {
+				panic(err)
+			}
+			s.AuditLog = nil
+			now = time.Unix(1500000000, 0)
+			s.Now = func() time.Time { return now }
+			ctx := context.Background()
+			_, err = s.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			resp, err := s.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			enroll, err := s.EnrollTOTP(ctx, &pb.EnrollTOTPReq{Session: resp.Session})
+			g.Assert(err).Equal(nil)
+			_, err = s.ConfirmTOTP(ctx, &pb.ConfirmTOTPReq{Session: resp.Session, Code: totpCode(enroll.Secret, now)})
+			g.Assert(err).Equal(nil)
+			now = now.Add(usersservice.TOTPPeriod)
+
+			// The right password alone does not start the count over
+			var challenge string
+			for i := 0; i <= usersservice.DefaultLoginFreeAttempts; i++ {
+				resp, err = s.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+				g.Assert(err).Equal(nil)
+				challenge = resp.SecondFactorChallenge
+				_, err = s.VerifySecondFactor(ctx, &pb.VerifySecondFactorReq{Challenge: challenge, Code: "000000"})
+				g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad code"))
+			}
+			_, err = s.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(retryAfter(err)).Equal("1")
+			_, err = s.VerifySecondFactor(ctx, &pb.VerifySecondFactorReq{Challenge: challenge, Code: totpCode(enroll.Secret, now)})
+			g.Assert(retryAfter(err)).Equal("1")
+
+			now = now.Add(time.Second)
+			_, err = s.VerifySecondFactor(ctx, &pb.VerifySecondFactorReq{Challenge: challenge, Code: totpCode(enroll.Secret, now)})
+			g.Assert(err).Equal(nil)
+
+			// Finishing the login does
+			_, err = s.Login(ctx, &pb.LoginReq{Username: "eric", Password: "wrong"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad password"))
+			_, err = s.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+		})
+
+		g.It("Should rate limit login attempts per client ip", func() {
+			service, hooks := newService(0, 3)
+			server := httptest.NewServer(usersservice.WithClientInfo(pb.NewUsersServer(service, hooks)))
+			defer server.Close()
+			client := pb.NewUsersJSONClient(server.URL, http.DefaultClient)
+
+			for i := 0; i < 3; i++ {
+				_, err := client.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "wrong"})
+				g.Assert(retryAfter(err)).Equal("")
+			}
+			_, err := client.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(retryAfter(err)).Equal("1")
+			resp := post(server.URL, "")
+			g.Assert(resp.StatusCode).Equal(http.StatusTooManyRequests)
+			g.Assert(resp.Header.Get("Retry-After")).Equal("1")
+
+			// Other rpcs are not limited
+			_, err = client.Register(context.Background(), &pb.RegisterReq{Username: "erin", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			// Guessing codes to turn off two-factor is
+			_, err = client.DisableTOTP(context.Background(), &pb.DisableTOTPReq{Code: "000000"})
+			g.Assert(retryAfter(err)).Equal("1")
+			// And so is flooding someone with reset emails
+			_, err = client.RequestPasswordReset(context.Background(), &pb.RequestPasswordResetReq{UsernameOrEmail: "eric"})
+			g.Assert(retryAfter(err)).Equal("1")
+
+			now = now.Add(time.Second)
+			_, err = client.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+		})
+
+		g.It("Should only believe X-Forwarded-For from trusted proxies", func() {
+			service, hooks := newService(0, 1)
+			untrusted := httptest.NewServer(usersservice.WithProxiedClientInfo(pb.NewUsersServer(service, hooks), nil))
+			defer untrusted.Close()
+			// Spoofed headers all count against the connection's address
+			g.Assert(post(untrusted.URL, "203.0.113.1").StatusCode).Equal(http.StatusForbidden)
+			g.Assert(post(untrusted.URL, "203.0.113.2").StatusCode).Equal(http.StatusTooManyRequests)
+
+			trusted, err := usersservice.ParseTrustedProxies("127.0.0.1, 10.0.0.0/8")
+			g.Assert(err).Equal(nil)
+			proxied := httptest.NewServer(usersservice.WithProxiedClientInfo(pb.NewUsersServer(service, hooks), trusted))
+			defer proxied.Close()
+			g.Assert(post(proxied.URL, "203.0.113.1").StatusCode).Equal(http.StatusForbidden)
+			g.Assert(post(proxied.URL, "203.0.113.2, 10.1.2.3").StatusCode).Equal(http.StatusForbidden)
+			g.Assert(post(proxied.URL, "203.0.113.1").StatusCode).Equal(http.StatusTooManyRequests)
+			// Only the first untrusted hop from the right counts, the rest
+			// is up to the client
+			g.Assert(post(proxied.URL, "198.51.100.7, 203.0.113.2").StatusCode).Equal(http.StatusTooManyRequests)
+
+			_, err = usersservice.ParseTrustedProxies("10.0.0.0/8,proxy.local")
+			g.Assert(err != nil).IsTrue()
+		})
+	})
+
+	g.Describe("Hidden usernames", func() {
+		var service pb.Users
+		var store usersservice.Store
+		var hooks *twirp.ServerHooks
+		hasher := &countingHasher{PasswordHasher: usersservice.DefaultPasswordHasher}
+		ctx := context.Background()
+
+		g.Before(func() {
+			s, err := usersservice.New(usersservice.WithMemoryStore(), usersservice.WithPasswordHasher(hasher))
+			if err != nil {
+				panic(err)
+			}
+			s.AuditLog = nil
+			s.HideUsernames = true
+			s.MaxLoginBackoff = 0
+			s.IPLoginBurst = 2
+			service, store, hooks = s, s.Store, s.ServerHooks()
+
+			if _, err := service.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"}); err != nil {
+				panic(err)
+			}
+		})
+
+		g.It("Should fail the same way for unknown users and wrong passwords", func() {
+			_, wrongPassword := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "wrong"})
+			_, unknownUser := service.Login(ctx, &pb.LoginReq{Username: "nobody", Password: "wrong"})
+			_, blankUser := service.Login(ctx, &pb.LoginReq{Username: "", Password: "wrong"})
+			g.Assert(wrongPassword).Equal(twirp.NewError(twirp.PermissionDenied, "bad username or password"))
+			g.Assert(unknownUser).Equal(wrongPassword)
+			g.Assert(blankUser).Equal(wrongPassword)
+
+			// Over the wire too
+			server := httptest.NewServer(pb.NewUsersServer(service, nil))
+			defer server.Close()
+			post := func(body string) (int, string) {
+				resp, err := http.Post(server.URL+pb.UsersPathPrefix+"Login", "application/json", strings.NewReader(body))
+				g.Assert(err).Equal(nil)
+				defer resp.Body.Close()
+				data, err := ioutil.ReadAll(resp.Body)
+				g.Assert(err).Equal(nil)
+				return resp.StatusCode, string(data)
+			}
+			wrongStatus, wrongBody := post(`{"username":"eric","password":"wrong"}`)
+			unknownStatus, unknownBody := post(`{"username":"nobody","password":"wrong"}`)
+			g.Assert(unknownStatus).Equal(wrongStatus)
+			g.Assert(unknownBody).Equal(wrongBody)
+
+			_, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+		})
+
+		g.It("Should check a dummy hash for unknown users", func() {
+			before := hasher.verifies
+			_, err := service.Login(ctx, &pb.LoginReq{Username: "nobody", Password: "wrong"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad username or password"))
+			g.Assert(hasher.verifies).Equal(before + 1)
+
+			_, err = service.Login(ctx, &pb.LoginReq{Username: "someone/else", Password: "wrong"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad username or password"))
+			g.Assert(hasher.verifies).Equal(before + 2)
+		})
+
+		g.It("Should check a dummy hash for legacy records but not other hashers", func() {
+			digest := sha256.Sum256([]byte("correct horse"))
+			g.Assert(store.CreateUser(&pb.PrivateUser{Username: "legacy", PasswordSha256: digest[:]})).Equal(nil)
+			scrypt, err := (&usersservice.ScryptHasher{LogN: 10, R: 8, P: 1, KeyLen: 32, SaltLen: 16}).Hash("correct horse")
+			g.Assert(err).Equal(nil)
+			g.Assert(store.CreateUser(&pb.PrivateUser{Username: "scrypted", PasswordHash: scrypt})).Equal(nil)
+
+			before := hasher.verifies
+			_, err = service.Login(ctx, &pb.LoginReq{Username: "legacy", Password: "wrong"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad username or password"))
+			g.Assert(hasher.verifies).Equal(before + 1)
+
+			// Their own hash sets the timing, the README warns about these
+			_, err = service.Login(ctx, &pb.LoginReq{Username: "scrypted", Password: "wrong"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad username or password"))
+			g.Assert(hasher.verifies).Equal(before + 1)
+		})
+
+		g.It("Should only show users to callers with a session", func() {
+			_, err := service.User(ctx, &pb.UserReq{Username: "eric"})
+			g.Assert(err).Equal(twirp.RequiredArgumentError("session.token"))
+			_, err = service.User(ctx, &pb.UserReq{Username: "nobody"})
+			g.Assert(err).Equal(twirp.RequiredArgumentError("session.token"))
+			_, err = service.User(ctx, &pb.UserReq{Session: &pb.Session{Token: "forged"}, Username: "eric"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid session token"))
+
+			login, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			resp, err := service.User(ctx, &pb.UserReq{Session: login.Session, Username: "eric"})
+			g.Assert(err).Equal(nil)
+			g.Assert(resp.User.Username).Equal("eric")
+		})
+
+		g.It("Should rate limit Register per client ip", func() {
+			server := httptest.NewServer(usersservice.WithClientInfo(pb.NewUsersServer(service, hooks)))
+			defer server.Close()
+			client := pb.NewUsersJSONClient(server.URL, http.DefaultClient)
+
+			_, err := client.Register(ctx, &pb.RegisterReq{Username: "alice", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			_, err = client.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err.(twirp.Error).Code()).Equal(twirp.AlreadyExists)
+			_, err = client.Register(ctx, &pb.RegisterReq{Username: "bob", Password: "correct horse"})
+			g.Assert(err.(twirp.Error).Code()).Equal(twirp.ResourceExhausted)
+		})
+	})
+
+	g.Describe("Change password", func() {
+		var service pb.Users
+		ctx := context.Background()
+
+		login := func(password string) *pb.Session {
+			resp, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: password})
+			g.Assert(err).Equal(nil)
+			return resp.Session
+		}
+
+		g.Before(func() {
+			s, err := usersservice.New(usersservice.WithMemoryStore())
+			if err != nil {
+				panic(err)
+			}
+			s.AuditLog = nil
+			service = s
+
+			if _, err := service.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"}); err != nil {
+				panic(err)
+			}
+		})
+
+		g.It("Should require the current password", func() {
+			session := login("correct horse")
+
+			_, err := service.ChangePassword(ctx, &pb.ChangePasswordReq{Session: session, NewPassword: "battery staple"})
+			g.Assert(err).Equal(twirp.RequiredArgumentError("ChangePasswordReq.old_password"))
+
+			_, err = service.ChangePassword(ctx, &pb.ChangePasswordReq{Session: session, OldPassword: "wrong", NewPassword: "battery staple"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad password"))
+
+			_, err = service.ChangePassword(ctx, &pb.ChangePasswordReq{Session: session, OldPassword: "correct horse"})
+			g.Assert(err).Equal(twirp.RequiredArgumentError("ChangePasswordReq.new_password"))
+
+			_, err = service.ChangePassword(ctx, &pb.ChangePasswordReq{Session: session, OldPassword: "correct horse", NewPassword: "correct horse"})
+			g.Assert(err.(twirp.Error).Code()).Equal(twirp.InvalidArgument)
+		})
+
+		g.It("Should replace the password and keep other sessions by default", func() {
+			session := login("correct horse")
+			other := login("correct horse")
+
+			resp, err := service.ChangePassword(ctx, &pb.ChangePasswordReq{Session: session, OldPassword: "correct horse", NewPassword: "battery staple"})
+			g.Assert(err).Equal(nil)
+			g.Assert(resp.Revoked).Equal(int32(0))
+
+			_, err = service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "bad password"))
+			login("battery staple")
+
+			_, err = service.CurrentUser(ctx, &pb.CurrentUserReq{Session: other})
+			g.Assert(err).Equal(nil)
+		})
+
+		g.It("Should revoke every other session when asked", func() {
+			session := login("battery staple")
+			other := login("battery staple")
+
+			resp, err := service.ChangePassword(ctx, &pb.ChangePasswordReq{
+				Session:             session,
+				OldPassword:         "battery staple",
+				NewPassword:         "quiet please",
+				RevokeOtherSessions: true,
+			})
+			g.Assert(err).Equal(nil)
+			g.Assert(resp.Revoked > 0).IsTrue()
+
+			_, err = service.CurrentUser(ctx, &pb.CurrentUserReq{Session: other})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid session token"))
+			_, err = service.CurrentUser(ctx, &pb.CurrentUserReq{Session: session})
+			g.Assert(err).Equal(nil)
+		})
+	})
+
+	g.Describe("Password reset", func() {
 		var service pb.Users
-		testDbPath := "/tmp/usersservice.db"
+		var store usersservice.Store
+		var wait func()
+		var notifier *recordingNotifier
+		var now time.Time
+		ctx := context.Background()
+
+		requestReset := func() string {
+			sent := len(notifier.sent)
+			_, err := service.RequestPasswordReset(ctx, &pb.RequestPasswordResetReq{UsernameOrEmail: "eric"})
+			g.Assert(err).Equal(nil)
+			wait()
+			g.Assert(len(notifier.sent)).Equal(sent + 1)
+			return notifier.sent[sent].Token
+		}
 
 		g.Before(func() {
-			// Delete the db if it exists
-			if _, err := os.Stat(testDbPath); err == nil {
-				if err := os.RemoveAll(testDbPath); err != nil {
-					panic(err)
+			notifier = &recordingNotifier{}
+			s, err := usersservice.New(usersservice.WithMemoryStore(), usersservice.WithNotifier(notifier))
+			if err != nil {
+				panic(err)
+			}
+			now = time.Unix(1500000000, 0)
+			s.Now = func() time.Time { return now }
+			s.AuditLog = nil
+			service, store, wait = s, s.Store, s.WaitForNotifications
+
+			if _, err := service.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"}); err != nil {
+				panic(err)
+			}
+		})
+
+		g.It("Should not reveal whether the user exists", func() {
+			_, err := service.RequestPasswordReset(ctx, &pb.RequestPasswordResetReq{UsernameOrEmail: "nobody"})
+			g.Assert(err).Equal(nil)
+			wait()
+			g.Assert(len(notifier.sent)).Equal(0)
+		})
+
+		g.It("Should not reveal the user exists when the notification fails", func() {
+			notifier.fail = errors.New("mail server down")
+			defer func() { notifier.fail = nil }()
+			failures := func() int64 {
+				if v, ok := usersservice.Metrics.Get("notification_errors").(*expvar.Int); ok {
+					return v.Value()
 				}
+				return 0
 			}
+			before := failures()
+
+			_, err := service.RequestPasswordReset(ctx, &pb.RequestPasswordResetReq{UsernameOrEmail: "eric"})
+			g.Assert(err).Equal(nil)
+			wait()
+			g.Assert(failures()).Equal(before + 1)
+		})
 
+		g.It("Should notify the user with a token that is only stored hashed", func() {
+			token := requestReset()
+			n := notifier.sent[len(notifier.sent)-1]
+			g.Assert(n.Kind).Equal(usersservice.NotifyPasswordReset)
+			g.Assert(n.Username).Equal("eric")
+			g.Assert(n.ExpiresAt).Equal(now.Add(usersservice.DefaultResetTokenTTL))
 
-			if s, err := usersservice.New(testDbPath); err == nil {
-				service = s
-			} else {
+			_, err := store.TakeResetToken(token)
+			g.Assert(err).Equal(usersservice.ErrNotFound)
+		})
+
+		g.It("Should reset the password once and end every session", func() {
+			login, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			token := requestReset()
+
+			resp, err := service.ResetPassword(ctx, &pb.ResetPasswordReq{ResetToken: token, NewPassword: "battery staple"})
+			g.Assert(err).Equal(nil)
+			g.Assert(resp.Revoked).Equal(int32(1))
+
+			_, err = service.CurrentUser(ctx, &pb.CurrentUserReq{Session: login.Session})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid session token"))
+			_, err = service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "battery staple"})
+			g.Assert(err).Equal(nil)
+
+			// Single use
+			_, err = service.ResetPassword(ctx, &pb.ResetPasswordReq{ResetToken: token, NewPassword: "once again"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid reset token"))
+		})
+
+		g.It("Should reject an expired token", func() {
+			token := requestReset()
+			now = now.Add(usersservice.DefaultResetTokenTTL)
+
+			_, err := service.ResetPassword(ctx, &pb.ResetPasswordReq{ResetToken: token, NewPassword: "too late now"})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "reset token expired"))
+			_, err = service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "battery staple"})
+			g.Assert(err).Equal(nil)
+		})
+
+		g.It("Should require a token and a new password", func() {
+			_, err := service.ResetPassword(ctx, &pb.ResetPasswordReq{NewPassword: "battery staple"})
+			g.Assert(err).Equal(twirp.RequiredArgumentError("ResetPasswordReq.reset_token"))
+			_, err = service.ResetPassword(ctx, &pb.ResetPasswordReq{ResetToken: "x"})
+			g.Assert(err).Equal(twirp.RequiredArgumentError("ResetPasswordReq.new_password"))
+		})
+	})
+
+	g.Describe("Email verification", func() {
+		var service pb.Users
+		var wait func()
+		var notifier *recordingNotifier
+		var now time.Time
+		ctx := context.Background()
+
+		lastToken := func(kind string) string {
+			for i := len(notifier.sent) - 1; i >= 0; i-- {
+				if notifier.sent[i].Kind == kind {
+					return notifier.sent[i].Token
+				}
+			}
+			return ""
+		}
+
+		g.Before(func() {
+			notifier = &recordingNotifier{}
+			s, err := usersservice.New(usersservice.WithMemoryStore(), usersservice.WithNotifier(notifier))
+			if err != nil {
+				panic(err)
+			}
+			now = time.Unix(1500000000, 0)
+			s.Now = func() time.Time { return now }
+			s.AuditLog = nil
+			service, wait = s, s.WaitForNotifications
+
+			if _, err := service.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse", Email: "eric@example.com"}); err != nil {
+				panic(err)
+			}
+		})
+
+		g.It("Should send a verification token at Register", func() {
+			g.Assert(len(notifier.sent)).Equal(1)
+			g.Assert(notifier.sent[0].Kind).Equal(usersservice.NotifyEmailVerification)
+			g.Assert(notifier.sent[0].Email).Equal("eric@example.com")
+		})
+
+		g.It("Should only show the email to the user themselves", func() {
+			login, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			current, err := service.CurrentUser(ctx, &pb.CurrentUserReq{Session: login.Session})
+			g.Assert(err).Equal(nil)
+			g.Assert(current.User.Email).Equal("eric@example.com")
+
+			other, err := service.User(ctx, &pb.UserReq{Username: "eric"})
+			g.Assert(err).Equal(nil)
+			g.Assert(other.User.Email).Equal("")
+		})
+
+		g.It("Should reject an expired token", func() {
+			token := lastToken(usersservice.NotifyEmailVerification)
+			now = now.Add(usersservice.DefaultVerificationTokenTTL)
+			_, err := service.VerifyEmail(ctx, &pb.VerifyEmailReq{Token: token})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "verification token expired"))
+		})
+
+		g.It("Should resend and verify once", func() {
+			login, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			_, err = service.ResendVerification(ctx, &pb.ResendVerificationReq{Session: login.Session})
+			g.Assert(err).Equal(nil)
+			token := lastToken(usersservice.NotifyEmailVerification)
+
+			resp, err := service.VerifyEmail(ctx, &pb.VerifyEmailReq{Token: token})
+			g.Assert(err).Equal(nil)
+			g.Assert(resp.User.EmailVerified).IsTrue()
+
+			user, err := service.User(ctx, &pb.UserReq{Username: "eric"})
+			g.Assert(err).Equal(nil)
+			g.Assert(user.User.EmailVerified).IsTrue()
+
+			_, err = service.VerifyEmail(ctx, &pb.VerifyEmailReq{Token: token})
+			g.Assert(err).Equal(twirp.NewError(twirp.PermissionDenied, "invalid verification token"))
+
+			_, err = service.ResendVerification(ctx, &pb.ResendVerificationReq{Session: login.Session})
+			g.Assert(err).Equal(twirp.NewError(twirp.FailedPrecondition, "email already verified"))
+		})
+
+		g.It("Should send password resets by email", func() {
+			_, err := service.RequestPasswordReset(ctx, &pb.RequestPasswordResetReq{UsernameOrEmail: "Eric@Example.com"})
+			g.Assert(err).Equal(nil)
+			wait()
+			last := notifier.sent[len(notifier.sent)-1]
+			g.Assert(last.Kind).Equal(usersservice.NotifyPasswordReset)
+			g.Assert(last.Username).Equal("eric")
+		})
+	})
+
+	g.Describe("Validation policy", func() {
+		var service pb.Users
+		ctx := context.Background()
+
+		register := func(username, password string) error {
+			_, err := service.Register(ctx, &pb.RegisterReq{Username: username, Password: password})
+			return err
+		}
+		rules := func(err error) string {
+			g.Assert(err.(twirp.Error).Code()).Equal(twirp.InvalidArgument)
+			return err.(twirp.Error).Meta("rules")
+		}
+
+		g.Before(func() {
+			s, err := usersservice.New(usersservice.WithMemoryStore(), usersservice.WithPasswordHasher(&usersservice.BcryptHasher{Cost: 4}))
+			if err != nil {
 				panic(err)
 			}
+			s.AuditLog = nil
+			service = s
 		})
 
-		g.It("Happy Case", func() {
-			// Test registration
-			resp, err := service.Register(context.Background(), &pb.RegisterReq{Username: "eric", Password: "Shhh"})
+		g.It("Should normalize usernames so case variants collide", func() {
+			resp, err := service.Register(ctx, &pb.RegisterReq{Username: "Eric", Password: "correct horse"})
 			g.Assert(err).Equal(nil)
 			g.Assert(resp.User.Username).Equal("eric")
 
-			// Test login
-			loginResp, err := service.Login(context.Background(), &pb.LoginReq{Username: "eric", Password: "Shhh"})
+			g.Assert(register("eric", "correct horse")).Equal(twirp.NewError(twirp.AlreadyExists, "Username: eric already exists"))
+			g.Assert(register("ＥＲＩＣ", "correct horse")).Equal(twirp.NewError(twirp.AlreadyExists, "Username: eric already exists"))
+
+			_, err = service.Login(ctx, &pb.LoginReq{Username: "ERIC", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+		})
+
+		g.It("Should name every rule a username breaks", func() {
+			err := register("a", "correct horse")
+			g.Assert(rules(err)).Equal("min_length")
+			g.Assert(err.(twirp.Error).Meta("argument")).Equal("RegisterReq.username")
+			g.Assert(err.(twirp.Error).Meta("rule.min_length")).Equal("must be at least 2 characters")
+
+			g.Assert(rules(register(strings.Repeat("a", 40)+"/x", "correct horse"))).Equal("max_length,charset")
+			g.Assert(rules(register("Admin", "correct horse"))).Equal("reserved")
+			g.Assert(register("  ", "correct horse")).Equal(twirp.RequiredArgumentError("RegisterReq.username"))
+		})
+
+		g.It("Should name every rule a password breaks", func() {
+			err := register("carol", "hush")
+			g.Assert(rules(err)).Equal("min_length")
+			g.Assert(err.(twirp.Error).Meta("argument")).Equal("RegisterReq.password")
+			g.Assert(rules(register("carol", "PassWord"))).Equal("banned")
+			g.Assert(rules(register("carol", "123456"))).Equal("min_length,banned")
+			g.Assert(rules(register("carol", strings.Repeat("x", 257)))).Equal("max_length")
+			g.Assert(register("carol", "correct horse")).Equal(nil)
+		})
+
+		g.It("Should load a policy file over the defaults", func() {
+			path := "/tmp/usersservice-policy.json"
+			err := ioutil.WriteFile(path, []byte(`{"password_min_length": 12, "reserved_usernames": ["Staff"]}`), 0600)
+			g.Assert(err).Equal(nil)
+
+			policy, err := usersservice.LoadPolicy(path)
 			g.Assert(err).Equal(nil)
+			g.Assert(policy.PasswordMinLength).Equal(12)
+			g.Assert(policy.UsernameMaxLength).Equal(usersservice.DefaultPolicy.UsernameMaxLength)
+			_, err = policy.ValidateUsername("username", "staff")
+			g.Assert(rules(err)).Equal("reserved")
+			_, err = policy.ValidateUsername("username", "admin")
+			g.Assert(err).Equal(nil)
+		})
+	})
+
+	g.Describe("Breached passwords", func() {
+		var service pb.Users
+		var wait func()
+		var notifier *recordingNotifier
+		ctx := context.Background()
+		dir := "/tmp/usersservice-breach"
+
+		// The corpus holds "Breached1" and a few hashes around it
+		breached := sha1.Sum([]byte("Breached1"))
+		hashes := []string{
+			"0000000000000000000000000000000000000000",
+			strings.ToUpper(hex.EncodeToString(breached[:])),
+			"FFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFFF",
+		}
+		rejections := func() int64 {
+			if v, ok := usersservice.Metrics.Get("breached_password_rejections").(*expvar.Int); ok {
+				return v.Value()
+			}
+			return 0
+		}
+		breachedRule := func(err error) {
+			g.Assert(err.(twirp.Error).Code()).Equal(twirp.InvalidArgument)
+			g.Assert(err.(twirp.Error).Meta("rules")).Equal("breached")
+		}
+
+		g.Before(func() {
+			os.RemoveAll(dir)
+			if err := os.MkdirAll(dir+"/ranges", 0700); err != nil {
+				panic(err)
+			}
+			corpus := ""
+			for _, hash := range hashes {
+				corpus += hash + ":12\n"
+				// Range files are named by the first 5 hex digits
+				err := ioutil.WriteFile(dir+"/ranges/"+hash[:5]+".txt", []byte(hash[5:]+":12\r\n"), 0600)
+				if err != nil {
+					panic(err)
+				}
+			}
+			if err := ioutil.WriteFile(dir+"/pwned.txt", []byte(corpus), 0600); err != nil {
+				panic(err)
+			}
 
-			// Test User request
-			userResp, err := service.User(
-				context.Background(),
-				&pb.UserReq{
-					Username: "eric",
-				},
+			count, err := usersservice.BuildBreachIndex(dir+"/pwned.txt", dir+"/pwned.idx")
+			if err != nil {
+				panic(err)
+			}
+			g.Assert(count).Equal(len(hashes))
+
+			notifier = &recordingNotifier{}
+			s, err := usersservice.New(
+				usersservice.WithMemoryStore(),
+				usersservice.WithNotifier(notifier),
+				usersservice.WithBreachIndex(dir+"/pwned.idx"),
 			)
+			if err != nil {
+				panic(err)
+			}
+			s.AuditLog = nil
+			service, wait = s, s.WaitForNotifications
+		})
+
+		g.It("Should find exactly the indexed hashes", func() {
+			index, err := usersservice.OpenBreachIndex(dir + "/pwned.idx")
+			g.Assert(err).Equal(nil)
+			defer index.Close()
+			g.Assert(index.Size()).Equal(len(hashes))
+
+			found, err := index.Breached("Breached1")
+			g.Assert(err).Equal(nil)
+			g.Assert(found).IsTrue()
+			found, err = index.Breached("Breached2")
+			g.Assert(err).Equal(nil)
+			g.Assert(found).IsFalse()
+		})
+
+		g.It("Should index a directory of range files the same way", func() {
+			count, err := usersservice.BuildBreachIndex(dir+"/ranges", dir+"/ranges.idx")
+			g.Assert(err).Equal(nil)
+			g.Assert(count).Equal(len(hashes))
+
+			index, err := usersservice.OpenBreachIndex(dir + "/ranges.idx")
+			g.Assert(err).Equal(nil)
+			defer index.Close()
+			found, err := index.Breached("Breached1")
+			g.Assert(err).Equal(nil)
+			g.Assert(found).IsTrue()
+		})
+
+		g.It("Should refuse a corpus out of hash order", func() {
+			corpus := hashes[2] + ":1\n" + hashes[0] + ":1\n"
+			g.Assert(ioutil.WriteFile(dir+"/unordered.txt", []byte(corpus), 0600)).Equal(nil)
+			_, err := usersservice.BuildBreachIndex(dir+"/unordered.txt", dir+"/unordered.idx")
+			g.Assert(err == nil).IsFalse()
+			_, err = os.Stat(dir + "/unordered.idx")
+			g.Assert(os.IsNotExist(err)).IsTrue()
+
+			_, err = usersservice.OpenBreachIndex(dir + "/pwned.txt")
+			g.Assert(err == nil).IsFalse()
+		})
+
+		g.It("Should reject a breached password at Register, ChangePassword and ResetPassword", func() {
+			before := rejections()
+
+			_, err := service.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "Breached1"})
+			breachedRule(err)
+			g.Assert(err.(twirp.Error).Meta("argument")).Equal("RegisterReq.password")
+
+			_, err = service.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+			login, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			g.Assert(err).Equal(nil)
+
+			_, err = service.ChangePassword(ctx, &pb.ChangePasswordReq{Session: login.Session, OldPassword: "correct horse", NewPassword: "Breached1"})
+			breachedRule(err)
+
+			_, err = service.RequestPasswordReset(ctx, &pb.RequestPasswordResetReq{UsernameOrEmail: "eric"})
 			g.Assert(err).Equal(nil)
-			g.Assert(userResp.User.Username).Equal("eric")
+			wait()
+			token := notifier.sent[len(notifier.sent)-1].Token
+			_, err = service.ResetPassword(ctx, &pb.ResetPasswordReq{ResetToken: token, NewPassword: "Breached1"})
+			breachedRule(err)
+			_, err = service.ResetPassword(ctx, &pb.ResetPasswordReq{ResetToken: token, NewPassword: "Breached2"})
+			g.Assert(err).Equal(nil)
+
+			g.Assert(rejections() - before).Equal(int64(3))
+		})
 
-			// Test CurrentUser request
-			currentUserResp, err := service.CurrentUser(
-				context.Background(),
-				&pb.CurrentUserReq{
-					Session: loginResp.Session,
-				},
+		g.It("Should close every store and index opened when an option fails", func() {
+			first, second := dir+"/first.db", dir+"/second.db"
+			_, err := usersservice.New(
+				usersservice.WithLevelDB(first),
+				usersservice.WithBreachIndex(dir+"/pwned.idx"),
+				usersservice.WithLevelDB(second),
+				usersservice.WithSecretKey([]byte("too short")),
 			)
+			g.Assert(err == nil).IsFalse()
+
+			// LevelDB locks its directory until the store is closed
+			for _, path := range []string{first, second} {
+				store, err := usersservice.NewLevelDBStore(path)
+				g.Assert(err).Equal(nil)
+				store.Close()
+			}
+		})
+	})
+
+	g.Describe("Profile limits", func() {
+		var service pb.Users
+		var session *pb.Session
+		ctx := context.Background()
+
+		update := func(profile *pb.Profile, mask ...string) error {
+			_, err := service.UpdateProfile(ctx, &pb.UpdateProfileReq{Session: session, Profile: profile, UpdateMask: mask})
+			return err
+		}
+		code := func(err error) twirp.ErrorCode {
+			if err == nil {
+				return twirp.NoError
+			}
+			return err.(twirp.Error).Code()
+		}
+
+		g.Before(func() {
+			s, err := usersservice.New(usersservice.WithMemoryStore())
+			if err != nil {
+				panic(err)
+			}
+			s.AuditLog = nil
+			service = s
+
+			if _, err := service.Register(ctx, &pb.RegisterReq{Username: "eric", Password: "correct horse"}); err != nil {
+				panic(err)
+			}
+			login, err := service.Login(ctx, &pb.LoginReq{Username: "eric", Password: "correct horse"})
+			if err != nil {
+				panic(err)
+			}
+			session = login.Session
+		})
+
+		g.It("Should require a known, non-empty update mask", func() {
+			g.Assert(update(&pb.Profile{DisplayName: "Eric"})).Equal(twirp.RequiredArgumentError("UpdateProfileReq.update_mask"))
+			g.Assert(update(&pb.Profile{}, "username")).Equal(twirp.InvalidArgumentError("update_mask", `unknown path "username"`))
+		})
+
+		g.It("Should validate the profile fields", func() {
+			g.Assert(code(update(&pb.Profile{DisplayName: strings.Repeat("é", usersservice.MaxDisplayNameLength)}, "display_name"))).Equal(twirp.NoError)
+			g.Assert(code(update(&pb.Profile{DisplayName: strings.Repeat("é", usersservice.MaxDisplayNameLength+1)}, "display_name"))).Equal(twirp.InvalidArgument)
+			g.Assert(code(update(&pb.Profile{AvatarUrl: "javascript:alert(1)"}, "avatar_url"))).Equal(twirp.InvalidArgument)
+			g.Assert(code(update(&pb.Profile{AvatarUrl: "https://example.com/eric.png"}, "avatar_url"))).Equal(twirp.NoError)
+			g.Assert(code(update(&pb.Profile{Locale: "not a locale"}, "locale"))).Equal(twirp.InvalidArgument)
+			g.Assert(code(update(&pb.Profile{Timezone: "Mars/Olympus_Mons"}, "timezone"))).Equal(twirp.InvalidArgument)
+			g.Assert(code(update(&pb.Profile{Timezone: "UTC"}, "timezone"))).Equal(twirp.NoError)
+		})
+
+		g.It("Should limit attribute keys, values and count", func() {
+			g.Assert(code(update(&pb.Profile{Attributes: map[string]string{"Bad Key": "x"}}, "attributes"))).Equal(twirp.InvalidArgument)
+			g.Assert(code(update(&pb.Profile{Attributes: map[string]string{strings.Repeat("k", usersservice.MaxAttributeKeyLength+1): "x"}}, "attributes"))).Equal(twirp.InvalidArgument)
+			g.Assert(code(update(&pb.Profile{Attributes: map[string]string{"k": strings.Repeat("v", usersservice.MaxAttributeValueLength+1)}}, "attributes"))).Equal(twirp.InvalidArgument)
+
+			attributes := map[string]string{}
+			for i := 0; i < usersservice.MaxAttributes; i++ {
+				attributes[fmt.Sprintf("k%d", i)] = "v"
+			}
+			g.Assert(code(update(&pb.Profile{Attributes: attributes}, "attributes"))).Equal(twirp.NoError)
+			g.Assert(code(update(&pb.Profile{Attributes: map[string]string{"one-more": "v"}}, "attributes.one-more"))).Equal(twirp.InvalidArgument)
+		})
+	})
+
+	g.Describe("Password hashers", func() {
+		hashers := map[string]usersservice.PasswordHasher{
+			"argon2id": usersservice.DefaultPasswordHasher,
+			"bcrypt":   &usersservice.BcryptHasher{Cost: 4},
+			"scrypt":   &usersservice.ScryptHasher{LogN: 10, R: 8, P: 1, KeyLen: 32, SaltLen: 16},
+		}
+
+		for name, hasher := range hashers {
+			hasher := hasher
+			g.It("Should verify "+name+" hashes", func() {
+				encoded, err := hasher.Hash("correct horse")
+				g.Assert(err).Equal(nil)
+
+				ok, err := usersservice.VerifyPassword("correct horse", encoded)
+				g.Assert(err).Equal(nil)
+				g.Assert(ok).IsTrue()
+
+				ok, err = usersservice.VerifyPassword("wrong", encoded)
+				g.Assert(err).Equal(nil)
+				g.Assert(ok).IsFalse()
+			})
+
+			g.It("Should salt "+name+" hashes", func() {
+				first, _ := hasher.Hash("correct horse")
+				second, _ := hasher.Hash("correct horse")
+				g.Assert(first == second).IsFalse()
+			})
+		}
+
+		g.It("Should reject unknown hash formats", func() {
+			_, err := usersservice.VerifyPassword("correct horse", "$md5$nope")
+			g.Assert(err).Equal(usersservice.ErrUnknownHashFormat)
+		})
+	})
+
+	g.Describe("SQLite store", func() {
+		testDbPath := "/tmp/usersservice-migrations.sqlite"
+
+		g.Before(func() {
+			os.Remove(testDbPath)
+		})
+
+		g.It("Should migrate a new database and reopen it without migrating again", func() {
+			store, err := usersservice.NewSQLiteStore(testDbPath)
+			g.Assert(err).Equal(nil)
+			version, err := store.SchemaVersion()
+			g.Assert(err).Equal(nil)
+			g.Assert(version > 0).IsTrue()
+			g.Assert(store.CreateUser(&pb.PrivateUser{Username: "eric", PasswordHash: "x"})).Equal(nil)
+			g.Assert(store.Close()).Equal(nil)
+
+			store, err = usersservice.NewSQLiteStore(testDbPath)
+			g.Assert(err).Equal(nil)
+			defer store.Close()
+			reopened, err := store.SchemaVersion()
+			g.Assert(err).Equal(nil)
+			g.Assert(reopened).Equal(version)
+			user, err := store.GetUser("eric")
 			g.Assert(err).Equal(nil)
-			g.Assert(currentUserResp.User.Username).Equal("eric")
+			g.Assert(user.PasswordHash).Equal("x")
 		})
 
-		g.It("Should fail to register if the username is blank", func() {
-			_, err := service.Register(context.Background(), &pb.RegisterReq{Username: "", Password: "Shhh"})
-			g.Assert(err).Equal(twirp.RequiredArgumentError("RegisterReq.username"))
+		g.It("Should enforce foreign keys on every connection", func() {
+			store, err := usersservice.NewSQLiteStore(testDbPath)
+			g.Assert(err).Equal(nil)
+			defer store.Close()
+
+			// Without idle connections each query opens a new one
+			store.DB.SetMaxIdleConns(0)
+			for i := 0; i < 3; i++ {
+				var enabled int
+				g.Assert(store.DB.QueryRow("PRAGMA foreign_keys").Scan(&enabled)).Equal(nil)
+				g.Assert(enabled).Equal(1)
+			}
+		})
+
+		g.It("Should refuse a database migrated by a newer version", func() {
+			store, err := usersservice.NewSQLiteStore(testDbPath)
+			g.Assert(err).Equal(nil)
+			_, err = store.DB.Exec("INSERT INTO schema_migrations (version) VALUES (1000)")
+			g.Assert(err).Equal(nil)
+			g.Assert(store.Close()).Equal(nil)
+
+			_, err = usersservice.NewSQLiteStore(testDbPath)
+			g.Assert(err == nil).IsFalse()
 		})
 
-		g.It("Should fail to register if the password is blank", func() {
-			_, err := service.Register(context.Background(), &pb.RegisterReq{Username: "eric", Password: ""})
-			g.Assert(err).Equal(twirp.RequiredArgumentError("RegisterReq.password"))
+		g.It("Should enforce unique usernames and index sessions by user", func() {
+			os.Remove(testDbPath)
+			store, err := usersservice.NewSQLiteStore(testDbPath)
+			g.Assert(err).Equal(nil)
+			defer store.Close()
+
+			g.Assert(store.CreateUser(&pb.PrivateUser{Username: "eric"})).Equal(nil)
+			g.Assert(store.CreateUser(&pb.PrivateUser{Username: "eric"})).Equal(usersservice.ErrAlreadyExists)
+
+			var plan string
+			row := store.DB.QueryRow("EXPLAIN QUERY PLAN SELECT token FROM sessions WHERE username = ?", "eric")
+			var id, parent, notused int
+			g.Assert(row.Scan(&id, &parent, &notused, &plan)).Equal(nil)
+			g.Assert(strings.Contains(plan, "sessions_username")).IsTrue()
 		})
 
-		// TODO the rest of the owl.
+		g.It("Should open a path with ? and # in it", func() {
+			path := "/tmp/usersservice-odd?name#1.sqlite"
+			os.Remove(path)
+			defer os.Remove(path)
+			store, err := usersservice.NewSQLiteStore(path)
+			g.Assert(err).Equal(nil)
+			defer store.Close()
+
+			g.Assert(store.CreateUser(&pb.PrivateUser{Username: "eric"})).Equal(nil)
+			_, err = os.Stat(path)
+			g.Assert(err).Equal(nil)
+			var enabled int
+			g.Assert(store.DB.QueryRow("PRAGMA foreign_keys").Scan(&enabled)).Equal(nil)
+			g.Assert(enabled).Equal(1)
+		})
 	})
 }
+
+// barrierHasher holds every Hash call until the expected number of callers
+// have hashed, so concurrent Registers reach the store at the same time
+type barrierHasher struct {
+	usersservice.PasswordHasher
+	ready sync.WaitGroup
+}
+
+func (h *barrierHasher) Hash(password string) (string, error) {
+	encoded, err := h.PasswordHasher.Hash(password)
+	h.ready.Done()
+	h.ready.Wait()
+	return encoded, err
+}
+
+// countingHasher counts Verify calls, Login checks stored hashes with
+// VerifyPassword so these are only its dummy checks
+type countingHasher struct {
+	usersservice.PasswordHasher
+	verifies int
+}
+
+func (h *countingHasher) Verify(password, encoded string) (bool, error) {
+	h.verifies++
+	return h.PasswordHasher.Verify(password, encoded)
+}
+
+// interleavingStore runs afterGetSession once a session has been read, to
+// race another rpc against the one that read it
+type interleavingStore struct {
+	usersservice.Store
+	afterGetSession func()
+}
+
+func (s *interleavingStore) GetSession(token string) (*pb.PrivateSession, error) {
+	session, err := s.Store.GetSession(token)
+	if s.afterGetSession != nil {
+		s.afterGetSession()
+	}
+	return session, err
+}
+
+// storedActions lists the action and subject of every stored audit event
+// whose action starts with prefix, oldest first
+func storedActions(store usersservice.Store, prefix string) []string {
+	events, err := store.ListAuditEvents("", 1000)
+	if err != nil {
+		panic(err)
+	}
+	actions := []string{}
+	for _, event := range events {
+		if strings.HasPrefix(event.Action, prefix) {
+			actions = append(actions, event.Action+" "+event.Subject)
+		}
+	}
+	return actions
+}
+
+// recordingNotifier keeps every notification instead of delivering it
+type recordingNotifier struct {
+	sent []*usersservice.Notification
+	fail error // returned instead of recording when set
+}
+
+func (n *recordingNotifier) Notify(notification *usersservice.Notification) error {
+	if n.fail != nil {
+		return n.fail
+	}
+	n.sent = append(n.sent, notification)
+	return nil
+}
+
+// verify verifies username's email with the last verification token sent to
+// them
+func (n *recordingNotifier) verify(service pb.Users, username string) error {
+	for i := len(n.sent) - 1; i >= 0; i-- {
+		if sent := n.sent[i]; sent.Kind == usersservice.NotifyEmailVerification && sent.Username == username {
+			_, err := service.VerifyEmail(context.Background(), &pb.VerifyEmailReq{Token: sent.Token})
+			return err
+		}
+	}
+	return fmt.Errorf("no verification sent to %s", username)
+}
+
+// totpCode computes the RFC 6238 code an authenticator app shows for a base32
+// secret at t
+func totpCode(secret string, t time.Time) string {
+	key, err := base32.StdEncoding.WithPadding(base32.NoPadding).DecodeString(secret)
+	if err != nil {
+		panic(err)
+	}
+	counter := make([]byte, 8)
+	binary.BigEndian.PutUint64(counter, uint64(t.Unix()/30))
+	mac := hmac.New(sha1.New, key)
+	mac.Write(counter)
+	sum := mac.Sum(nil)
+	offset := sum[len(sum)-1] & 0xf
+	return fmt.Sprintf("%06d", (binary.BigEndian.Uint32(sum[offset:])&0x7fffffff)%1000000)
+}
+
+// softAuthenticator is a WebAuthn authenticator with an ES256 key in memory.
+// It verifies the user unless skipVerification is set.
+type softAuthenticator struct {
+	key              *ecdsa.PrivateKey
+	credentialID     []byte
+	userHandle       []byte
+	signCount        uint32
+	rpID             string
+	origin           string
+	skipVerification bool
+}
+
+func newSoftAuthenticator(rpID, origin string) *softAuthenticator {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		panic(err)
+	}
+	credentialID := make([]byte, 16)
+	if _, err := rand.Read(credentialID); err != nil {
+		panic(err)
+	}
+	return &softAuthenticator{key: key, credentialID: credentialID, rpID: rpID, origin: origin}
+}
+
+// create answers navigator.credentials.create() options with "none"
+// attestation
+func (a *softAuthenticator) create(options string) (clientDataJSON, attestationObject []byte) {
+	var parsed struct {
+		PublicKey struct {
+			Challenge string
+			User      struct{ ID string }
+		}
+	}
+	if err := json.Unmarshal([]byte(options), &parsed); err != nil {
+		panic(err)
+	}
+	userHandle, err := base64.RawURLEncoding.DecodeString(parsed.PublicKey.User.ID)
+	if err != nil {
+		panic(err)
+	}
+	a.userHandle = userHandle
+
+	x, y := make([]byte, 32), make([]byte, 32)
+	a.key.X.FillBytes(x)
+	a.key.Y.FillBytes(y)
+	coseKey := cborEncode(cborMap{int64(1), int64(2), int64(3), int64(-7), int64(-1), int64(1), int64(-2), x, int64(-3), y})
+
+	authData := a.authData(0x40)
+	authData = append(authData, make([]byte, 16)...) // aaguid
+	authData = append(authData, byte(len(a.credentialID)>>8), byte(len(a.credentialID)))
+	authData = append(authData, a.credentialID...)
+	authData = append(authData, coseKey...)
+
+	clientDataJSON = a.clientData("webauthn.create", parsed.PublicKey.Challenge)
+	attestationObject = cborEncode(cborMap{"fmt", "none", "attStmt", cborMap{}, "authData", authData})
+	return clientDataJSON, attestationObject
+}
+
+// get answers navigator.credentials.get() options
+func (a *softAuthenticator) get(options string) *pb.FinishWebAuthnLoginReq {
+	var parsed struct {
+		PublicKey struct{ Challenge string }
+	}
+	if err := json.Unmarshal([]byte(options), &parsed); err != nil {
+		panic(err)
+	}
+	a.signCount++
+	authData := a.authData(0)
+	clientDataJSON := a.clientData("webauthn.get", parsed.PublicKey.Challenge)
+
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return &pb.FinishWebAuthnLoginReq{
+		CredentialId:      a.credentialID,
+		ClientDataJson:    clientDataJSON,
+		AuthenticatorData: authData,
+		Signature:         signature,
+		UserHandle:        a.userHandle,
+	}
+}
+
+func (a *softAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	flags |= 0x01 // user present
+	if !a.skipVerification {
+		flags |= 0x04
+	}
+	authData := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(authData[33:], a.signCount)
+	return authData
+}
+
+func (a *softAuthenticator) clientData(kind, challenge string) []byte {
+	data, err := json.Marshal(map[string]string{"type": kind, "challenge": challenge, "origin": a.origin})
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// cborMap is a CBOR map as alternating keys and values, in encoding order
+type cborMap []interface{}
+
+// cborEncode encodes the CBOR a softAuthenticator needs
+func cborEncode(v interface{}) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n < 256:
+			return []byte{major<<5 | 24, byte(n)}
+		default:
+			return []byte{major<<5 | 25, byte(n >> 8), byte(n)}
+		}
+	}
+	switch v := v.(type) {
+	case int64:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+		return head(0, uint64(v))
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case cborMap:
+		out := head(5, uint64(len(v)/2))
+		for _, item := range v {
+			out = append(out, cborEncode(item)...)
+		}
+		return out
+	}
+	panic(fmt.Sprintf("cborEncode: unsupported %T", v))
+}